@@ -0,0 +1,135 @@
+package ethgo
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// BlobSize is the size of a blob (4096 field elements of 32 bytes)
+	BlobSize = 131072
+
+	// blobFieldElements is the number of field elements of a blob
+	blobFieldElements = 4096
+
+	// blobCommitmentVersionKZG is the version byte of the versioned hashes
+	blobCommitmentVersionKZG = 0x01
+)
+
+// Blob is the data carried by a blob transaction (eip-4844)
+type Blob [BlobSize]byte
+
+// KZGCommitment is the KZG commitment of a blob
+type KZGCommitment [48]byte
+
+// KZGProof is the KZG proof that a blob matches its commitment
+type KZGProof [48]byte
+
+// VersionedHash returns the versioned hash of the commitment
+// that is included in the blob transaction
+func (c KZGCommitment) VersionedHash() Hash {
+	h := Hash(sha256.Sum256(c[:]))
+	h[0] = blobCommitmentVersionKZG
+	return h
+}
+
+// KZG computes the KZG commitments and proofs of the blobs
+type KZG interface {
+	BlobToCommitment(blob *Blob) (KZGCommitment, error)
+	ComputeBlobProof(blob *Blob, commitment KZGCommitment) (KZGProof, error)
+}
+
+// BlobSidecar are the blobs, commitments and proofs of a blob
+// transaction. They are sent with the transaction to the network
+// but they are not part of the transaction included in the block.
+type BlobSidecar struct {
+	Blobs       []Blob
+	Commitments []KZGCommitment
+	Proofs      []KZGProof
+}
+
+// NewBlobSidecar computes the commitments and the proofs of the blobs
+func NewBlobSidecar(kzg KZG, blobs []Blob) (*BlobSidecar, error) {
+	sidecar := &BlobSidecar{
+		Blobs:       blobs,
+		Commitments: make([]KZGCommitment, len(blobs)),
+		Proofs:      make([]KZGProof, len(blobs)),
+	}
+	for indx := range blobs {
+		commitment, err := kzg.BlobToCommitment(&blobs[indx])
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute commitment of blob %d: %v", indx, err)
+		}
+		proof, err := kzg.ComputeBlobProof(&blobs[indx], commitment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute proof of blob %d: %v", indx, err)
+		}
+		sidecar.Commitments[indx] = commitment
+		sidecar.Proofs[indx] = proof
+	}
+	return sidecar, nil
+}
+
+// VersionedHashes returns the versioned hashes of the commitments
+func (b *BlobSidecar) VersionedHashes() []Hash {
+	hashes := make([]Hash, len(b.Commitments))
+	for indx, commitment := range b.Commitments {
+		hashes[indx] = commitment.VersionedHash()
+	}
+	return hashes
+}
+
+// Copy makes a copy of the sidecar
+func (b *BlobSidecar) Copy() *BlobSidecar {
+	bb := &BlobSidecar{
+		Blobs:       append([]Blob{}, b.Blobs...),
+		Commitments: append([]KZGCommitment{}, b.Commitments...),
+		Proofs:      append([]KZGProof{}, b.Proofs...),
+	}
+	return bb
+}
+
+// blobLengthPrefix is the size of the length of the data encoded in the blobs
+const blobLengthPrefix = 8
+
+// EncodeBlobs packs the data in blobs prefixed with its length (8 bytes big endian).
+// Each field element stores 31 bytes of data with a leading zero byte so that it
+// is lower than the modulus.
+func EncodeBlobs(data []byte) []Blob {
+	prefix := make([]byte, blobLengthPrefix, blobLengthPrefix+len(data))
+	binary.BigEndian.PutUint64(prefix, uint64(len(data)))
+	data = append(prefix, data...)
+
+	blobs := []Blob{}
+	for {
+		var blob Blob
+		for i := 0; i < blobFieldElements && len(data) != 0; i++ {
+			n := copy(blob[i*32+1:(i+1)*32], data)
+			data = data[n:]
+		}
+		blobs = append(blobs, blob)
+		if len(data) == 0 {
+			return blobs
+		}
+	}
+}
+
+// DecodeBlobs returns the data packed in the blobs with EncodeBlobs
+func DecodeBlobs(blobs []Blob) ([]byte, error) {
+	data := make([]byte, 0, len(blobs)*blobFieldElements*31)
+	for _, blob := range blobs {
+		for i := 0; i < blobFieldElements; i++ {
+			data = append(data, blob[i*32+1:(i+1)*32]...)
+		}
+	}
+	if len(data) < blobLengthPrefix {
+		return nil, fmt.Errorf("no blobs to decode")
+	}
+	size := binary.BigEndian.Uint64(data[:blobLengthPrefix])
+	data = data[blobLengthPrefix:]
+	if size > uint64(len(data)) {
+		return nil, fmt.Errorf("blobs have %d bytes of data but the length is %d", len(data), size)
+	}
+	return data[:size], nil
+}
@@ -0,0 +1,85 @@
+package ethgo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlob_VersionedHash(t *testing.T) {
+	// commitment of the empty blob (point at infinity)
+	commitment := KZGCommitment{0xc0}
+	assert.Equal(t, "0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014", commitment.VersionedHash().String())
+}
+
+func TestBlob_EncodeDecode(t *testing.T) {
+	// the length prefix takes 8 bytes of the first blob
+	full := 4096*31 - 8
+
+	cases := []struct {
+		data  []byte
+		blobs int
+	}{
+		{[]byte{}, 1},
+		{[]byte{0x1}, 1},
+		{[]byte{0x1, 0x0, 0x0}, 1},
+		{[]byte{0x0}, 1},
+		{bytes.Repeat([]byte{0x1}, 31), 1},
+		{bytes.Repeat([]byte{0x1}, full), 1},
+		{bytes.Repeat([]byte{0x1}, full+1), 2},
+		{append(bytes.Repeat([]byte{0x1}, full), make([]byte, 32)...), 2},
+	}
+	for _, c := range cases {
+		blobs := EncodeBlobs(c.data)
+		assert.Len(t, blobs, c.blobs)
+		for _, blob := range blobs {
+			// the field elements are lower than the modulus
+			for i := 0; i < blobFieldElements; i++ {
+				assert.Equal(t, byte(0), blob[i*32])
+			}
+		}
+
+		data, err := DecodeBlobs(blobs)
+		assert.NoError(t, err)
+		assert.Equal(t, c.data, append([]byte{}, data...))
+	}
+
+	// the length is higher than the data of the blobs
+	blobs := EncodeBlobs([]byte{0x1})
+	blobs[0][1] = 0x1
+
+	_, err := DecodeBlobs(blobs)
+	assert.Error(t, err)
+
+	_, err = DecodeBlobs(nil)
+	assert.Error(t, err)
+}
+
+type mockKZG struct{}
+
+func (m *mockKZG) BlobToCommitment(blob *Blob) (KZGCommitment, error) {
+	var c KZGCommitment
+	h := sha256.Sum256(blob[:])
+	copy(c[:], h[:])
+	return c, nil
+}
+
+func (m *mockKZG) ComputeBlobProof(blob *Blob, commitment KZGCommitment) (KZGProof, error) {
+	return KZGProof(commitment), nil
+}
+
+func TestBlob_Sidecar(t *testing.T) {
+	blobs := EncodeBlobs(bytes.Repeat([]byte{0x1}, 4096*31))
+
+	sidecar, err := NewBlobSidecar(&mockKZG{}, blobs)
+	assert.NoError(t, err)
+	assert.Len(t, sidecar.Commitments, 2)
+	assert.Len(t, sidecar.Proofs, 2)
+
+	hashes := sidecar.VersionedHashes()
+	assert.Len(t, hashes, 2)
+	assert.Equal(t, sidecar.Commitments[1].VersionedHash(), hashes[1])
+	assert.Equal(t, byte(0x01), hashes[0][0])
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/umbracle/ethgo"
 	"github.com/umbracle/ethgo/jsonrpc"
+	"github.com/umbracle/ethgo/jsonrpc/transport"
 )
 
 // BlockProvider are the eth1x methods required by the block tracker
@@ -382,8 +383,25 @@ func NewSubscriptionBlockTracker(logger *log.Logger, client *jsonrpc.Client) (*S
 // Track implements the BlockTracker interface
 func (s *SubscriptionBlockTracker) Track(ctx context.Context, handle func(block *ethgo.Block) error) error {
 	data := make(chan []byte)
-	cancel, err := s.client.Subscribe("newHeads", func(b []byte) {
-		data <- b
+	resumed := make(chan struct{}, 1)
+
+	cancel, err := s.client.SubscribeWithOpts(ctx, &transport.SubscribeOpts{
+		Method: "newHeads",
+		Callback: func(b []byte) {
+			select {
+			case data <- b:
+			case <-ctx.Done():
+			}
+		},
+		Resumed: func() {
+			select {
+			case resumed <- struct{}{}:
+			default:
+			}
+		},
+		Failed: func(err error) {
+			s.logger.Printf("[ERR]: Tracker failed to resume the newHeads subscription: %v", err)
+		},
 	})
 	if err != nil {
 		return err
@@ -400,8 +418,19 @@ func (s *SubscriptionBlockTracker) Track(ctx context.Context, handle func(block
 					handle(&block)
 				}
 
+			case <-resumed:
+				// the connection dropped and some heads may have been lost.
+				// Handle the latest block to backfill the missing ones.
+				block, err := s.client.Eth().WithContext(ctx).GetBlockByNumber(ethgo.Latest, false)
+				if err != nil {
+					s.logger.Printf("[ERR]: Tracker failed to get last block: %v", err)
+				} else {
+					handle(block)
+				}
+
 			case <-ctx.Done():
 				cancel()
+				return
 			}
 		}
 	}()
@@ -0,0 +1,94 @@
+package ethgo
+
+import (
+	"encoding/hex"
+)
+
+// BloomSize is the size in bytes of a logs bloom
+const BloomSize = 256
+
+// Bloom is the 2048 bits bloom filter of the addresses and topics of the logs
+// of a receipt or a block. A false result of Test means that the value is not
+// in the logs but a true result may be a false positive.
+type Bloom [BloomSize]byte
+
+// BytesToBloom converts bytes to a bloom. It is an empty bloom if the bytes are empty.
+func BytesToBloom(b []byte) Bloom {
+	var bloom Bloom
+	if len(b) > BloomSize {
+		b = b[len(b)-BloomSize:]
+	}
+	copy(bloom[BloomSize-len(b):], b)
+	return bloom
+}
+
+// CreateBloom returns the bloom of the addresses and topics of the logs
+func CreateBloom(logs []*Log) Bloom {
+	var bloom Bloom
+	for _, log := range logs {
+		bloom.AddLog(log)
+	}
+	return bloom
+}
+
+// Add adds the value to the bloom
+func (b *Bloom) Add(data []byte) {
+	for _, bit := range bloomBits(data) {
+		b[BloomSize-1-bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// AddLog adds the address and the topics of the log to the bloom
+func (b *Bloom) AddLog(log *Log) {
+	b.Add(log.Address[:])
+	for _, topic := range log.Topics {
+		b.Add(topic[:])
+	}
+}
+
+// Test returns false if the value (i.e. an address or a topic) is not in the bloom
+func (b Bloom) Test(data []byte) bool {
+	for _, bit := range bloomBits(data) {
+		if b[BloomSize-1-bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes returns the bytes of the bloom
+func (b Bloom) Bytes() []byte {
+	return b[:]
+}
+
+// MarshalText implements the marshal interface
+func (b Bloom) MarshalText() ([]byte, error) {
+	return []byte("0x" + hex.EncodeToString(b[:])), nil
+}
+
+// UnmarshalText implements the unmarshal interface
+func (b *Bloom) UnmarshalText(input []byte) error {
+	return unmarshalTextByte(b[:], input, BloomSize)
+}
+
+// bloomBits returns the three bits of the bloom set by the value. Each
+// bit is taken from a pair of bytes of the hash of the value (modulo 2048).
+func bloomBits(data []byte) [3]uint {
+	hash := Keccak256(data)
+
+	var bits [3]uint
+	for i := range bits {
+		bits[i] = (uint(hash[2*i])<<8 | uint(hash[2*i+1])) & 2047
+	}
+	return bits
+}
+
+// Bloom returns the logs bloom of the block
+func (b *Block) Bloom() Bloom {
+	return BytesToBloom(b.LogsBloom)
+}
+
+// Bloom returns the logs bloom of the receipt
+func (r *Receipt) Bloom() Bloom {
+	return BytesToBloom(r.LogsBloom)
+}
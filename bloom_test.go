@@ -0,0 +1,106 @@
+package ethgo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloom(t *testing.T) {
+	positive := []string{"testtest", "test", "hallo", "other"}
+	negative := []string{"tes", "lo"}
+
+	var b Bloom
+	for _, data := range positive {
+		b.Add([]byte(data))
+	}
+	for _, data := range positive {
+		assert.True(t, b.Test([]byte(data)))
+	}
+	for _, data := range negative {
+		assert.False(t, b.Test([]byte(data)))
+	}
+
+	// same result as go-ethereum
+	b = Bloom{}
+	for i := 0; i < 100; i++ {
+		b.Add([]byte(fmt.Sprintf("xxxxxxxxxx data %d yyyyyyyyyyyyyy", i)))
+	}
+	assert.Equal(t, "0xc8d3ca65cdb4874300a9e39475508f23ed6da09fdbc487f89a2dcf50b09eb263", BytesToHash(Keccak256(b.Bytes())).String())
+}
+
+func TestBloom_Logs(t *testing.T) {
+	logs := []*Log{
+		{Address: Address{0x1}, Topics: []Hash{{0x1}}},
+		{Address: Address{0x2}, Topics: []Hash{{0x2}, {0x3}}},
+	}
+	bloom := CreateBloom(logs)
+
+	for _, log := range logs {
+		assert.True(t, bloom.Test(log.Address[:]))
+		for _, topic := range log.Topics {
+			assert.True(t, bloom.Test(topic[:]))
+		}
+	}
+
+	// json encoding
+	data, err := bloom.MarshalText()
+	assert.NoError(t, err)
+
+	var bloom2 Bloom
+	assert.NoError(t, bloom2.UnmarshalText(data))
+	assert.Equal(t, bloom, bloom2)
+
+	receipt := &Receipt{LogsBloom: bloom.Bytes()}
+	assert.Equal(t, bloom, receipt.Bloom())
+
+	// the block does not have a bloom
+	assert.Equal(t, Bloom{}, (&Block{}).Bloom())
+}
+
+func TestLogFilter_Match(t *testing.T) {
+	log := &Log{
+		Address: Address{0x1},
+		Topics:  []Hash{{0x1}, {0x2}},
+	}
+	topic := func(b byte) *Hash {
+		return &Hash{b}
+	}
+
+	cases := []struct {
+		filter *LogFilter
+		match  bool
+	}{
+		{&LogFilter{}, true},
+		{&LogFilter{Address: []Address{{0x2}, {0x1}}}, true},
+		{&LogFilter{Address: []Address{{0x2}}}, false},
+		{&LogFilter{Topics: [][]*Hash{{topic(0x1)}}}, true},
+		{&LogFilter{Topics: [][]*Hash{{topic(0x2)}}}, false},
+		// or
+		{&LogFilter{Topics: [][]*Hash{{topic(0x2), topic(0x1)}}}, true},
+		// and
+		{&LogFilter{Topics: [][]*Hash{{topic(0x1)}, {topic(0x2)}}}, true},
+		{&LogFilter{Topics: [][]*Hash{{topic(0x1)}, {topic(0x3)}}}, false},
+		// wildcards
+		{&LogFilter{Topics: [][]*Hash{nil, {topic(0x2)}}}, true},
+		{&LogFilter{Topics: [][]*Hash{{nil}, {topic(0x2)}}}, true},
+		// more topics than the log
+		{&LogFilter{Topics: [][]*Hash{nil, nil, nil}}, false},
+		{&LogFilter{Address: []Address{{0x1}}, Topics: [][]*Hash{{topic(0x1)}, nil}}, true},
+	}
+
+	bloom := CreateBloom([]*Log{log})
+	for indx, c := range cases {
+		assert.Equal(t, c.match, c.filter.Match(log), "case %d", indx)
+
+		// the bloom matches every filter that matches the log
+		if c.match {
+			assert.True(t, c.filter.MatchBloom(bloom), "case %d", indx)
+		}
+	}
+
+	assert.False(t, (&LogFilter{Address: []Address{{0x2}}}).MatchBloom(bloom))
+	assert.False(t, (&LogFilter{Topics: [][]*Hash{nil, {topic(0x3)}}}).MatchBloom(bloom))
+	assert.True(t, (&LogFilter{}).MatchBloom(Bloom{}))
+}
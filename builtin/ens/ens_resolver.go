@@ -1,19 +1,32 @@
 package ens
 
 import (
+	"context"
+	"strings"
+
 	"github.com/umbracle/ethgo"
 	"github.com/umbracle/ethgo/contract"
 	"github.com/umbracle/ethgo/jsonrpc"
-	"strings"
 )
 
 type ENSResolver struct {
+	addr     ethgo.Address
 	e        *ENS
 	provider *jsonrpc.Eth
 }
 
 func NewENSResolver(addr ethgo.Address, provider *jsonrpc.Client) *ENSResolver {
-	return &ENSResolver{NewENS(addr, contract.WithJsonRPC(provider.Eth())), provider.Eth()}
+	return newENSResolver(addr, provider.Eth())
+}
+
+func newENSResolver(addr ethgo.Address, provider *jsonrpc.Eth) *ENSResolver {
+	return &ENSResolver{addr, NewENS(addr, contract.WithJsonRPC(provider)), provider}
+}
+
+// WithContext returns a copy of the resolver whose requests are
+// aborted when the context is done
+func (e *ENSResolver) WithContext(ctx context.Context) *ENSResolver {
+	return newENSResolver(e.addr, e.provider.WithContext(ctx))
 }
 
 func (e *ENSResolver) Resolve(addr string, block ...ethgo.BlockNumber) (res ethgo.Address, err error) {
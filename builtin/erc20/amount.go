@@ -0,0 +1,43 @@
+package erc20
+
+import (
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+)
+
+// Amount returns the value with the decimals of the token
+func (e *ERC20) Amount(value *big.Int) (*ethgo.Amount, error) {
+	decimals, err := e.Decimals()
+	if err != nil {
+		return nil, err
+	}
+	return ethgo.NewAmount(value, decimals), nil
+}
+
+// ParseAmount parses a decimal amount of the token (i.e. "1.5") to its value
+func (e *ERC20) ParseAmount(s string) (*big.Int, error) {
+	decimals, err := e.Decimals()
+	if err != nil {
+		return nil, err
+	}
+	return ethgo.ParseUnits(s, decimals)
+}
+
+// FormatAmount formats the value as a decimal amount of the token
+func (e *ERC20) FormatAmount(value *big.Int) (string, error) {
+	decimals, err := e.Decimals()
+	if err != nil {
+		return "", err
+	}
+	return ethgo.FormatUnits(value, decimals), nil
+}
+
+// BalanceOfAmount returns the balance of the owner with the decimals of the token
+func (e *ERC20) BalanceOfAmount(owner ethgo.Address, block ...ethgo.BlockNumber) (*ethgo.Amount, error) {
+	balance, err := e.BalanceOf(owner, block...)
+	if err != nil {
+		return nil, err
+	}
+	return e.Amount(balance)
+}
@@ -1,12 +1,17 @@
 package erc20
 
 import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/umbracle/ethgo"
 	"github.com/umbracle/ethgo/contract"
 	"github.com/umbracle/ethgo/jsonrpc"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
 	"github.com/umbracle/ethgo/testutil"
 )
 
@@ -51,3 +56,33 @@ func TestTotalSupply(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, supply.String(), "1000000000000000000000000000")
 }
+
+func TestERC20Amount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req codec.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// decimals returns 6
+		resp := &codec.Response{ID: req.ID, Result: json.RawMessage(`"0x0000000000000000000000000000000000000000000000000000000000000006"`)}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer srv.Close()
+
+	c, _ := jsonrpc.NewClient(srv.URL)
+	erc20 := NewERC20(zeroX, contract.WithJsonRPC(c.Eth()))
+
+	value, err := erc20.ParseAmount("12.345678")
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678", value.String())
+
+	_, err = erc20.ParseAmount("1.0000001")
+	assert.Error(t, err)
+
+	str, err := erc20.FormatAmount(big.NewInt(1500000))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.5", str)
+
+	amount, err := erc20.Amount(big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000001", amount.String())
+}
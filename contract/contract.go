@@ -1,12 +1,17 @@
 package contract
 
 import (
+	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/umbracle/ethgo"
 	"github.com/umbracle/ethgo/abi"
+	"github.com/umbracle/ethgo/feeoracle"
 	"github.com/umbracle/ethgo/jsonrpc"
 	"github.com/umbracle/ethgo/wallet"
 )
@@ -17,12 +22,44 @@ type Provider interface {
 	Txn(ethgo.Address, ethgo.Key, []byte) (Txn, error)
 }
 
+// ProviderContext is implemented by the providers whose calls can be aborted
+// with a context. The contract uses CallContext instead of Call if it is available.
+type ProviderContext interface {
+	CallContext(context.Context, ethgo.Address, []byte, *CallOpts) ([]byte, error)
+}
+
 type jsonRPCNodeProvider struct {
 	client  *jsonrpc.Eth
 	eip1559 bool
+	fees    FeeStrategy
+	chainID chainIDCache
+}
+
+// chainIDCache queries the chain id of the node only once
+type chainIDCache struct {
+	lock    sync.Mutex
+	chainID *big.Int
+}
+
+func (c *chainIDCache) get(client *jsonrpc.Eth) (*big.Int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.chainID == nil {
+		chainID, err := client.ChainID()
+		if err != nil {
+			return nil, err
+		}
+		c.chainID = chainID
+	}
+	return new(big.Int).Set(c.chainID), nil
 }
 
 func (j *jsonRPCNodeProvider) Call(addr ethgo.Address, input []byte, opts *CallOpts) ([]byte, error) {
+	return j.CallContext(context.Background(), addr, input, opts)
+}
+
+func (j *jsonRPCNodeProvider) CallContext(ctx context.Context, addr ethgo.Address, input []byte, opts *CallOpts) ([]byte, error) {
 	msg := &ethgo.CallMsg{
 		To:   &addr,
 		Data: input,
@@ -30,7 +67,11 @@ func (j *jsonRPCNodeProvider) Call(addr ethgo.Address, input []byte, opts *CallO
 	if opts.From != ethgo.ZeroAddress {
 		msg.From = opts.From
 	}
-	rawStr, err := j.client.Call(msg, opts.Block)
+	block := opts.Block
+	if block == nil {
+		block = ethgo.Latest
+	}
+	rawStr, err := j.client.WithContext(ctx).Call(msg, block, jsonrpc.WithStateOverrides(opts.StateOverrides), jsonrpc.WithBlockOverrides(opts.BlockOverrides))
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +90,8 @@ func (j *jsonRPCNodeProvider) Txn(addr ethgo.Address, key ethgo.Key, input []byt
 		key:     key,
 		to:      addr,
 		eip1559: j.eip1559,
+		fees:    j.fees,
+		chainID: &j.chainID,
 	}
 	return txn, nil
 }
@@ -63,6 +106,42 @@ type jsonrpcTransaction struct {
 	txn     *ethgo.Transaction
 	txnRaw  []byte
 	eip1559 bool
+	fees    FeeStrategy
+	chainID *chainIDCache
+}
+
+// FeeStrategy suggests the fees of the transactions sent to the contract
+// (i.e. a feeoracle.Oracle)
+type FeeStrategy interface {
+	SuggestFee(ctx context.Context) (*feeoracle.Fee, error)
+}
+
+// gasPriceStrategy uses the gas price of the node as the fees
+type gasPriceStrategy struct {
+	client *jsonrpc.Eth
+}
+
+func (g *gasPriceStrategy) SuggestFee(ctx context.Context) (*feeoracle.Fee, error) {
+	gasPrice, err := g.client.WithContext(ctx).GasPrice()
+	if err != nil {
+		return nil, err
+	}
+	fee := &feeoracle.Fee{
+		MaxFeePerGas:         new(big.Int).SetUint64(gasPrice),
+		MaxPriorityFeePerGas: new(big.Int).SetUint64(gasPrice),
+		GasPrice:             new(big.Int).SetUint64(gasPrice),
+	}
+	return fee, nil
+}
+
+func (j *jsonrpcTransaction) feeStrategy(dynamicFee bool) FeeStrategy {
+	if j.fees != nil {
+		return j.fees
+	}
+	if dynamicFee {
+		return feeoracle.NewOracle(j.client)
+	}
+	return &gasPriceStrategy{client: j.client}
 }
 
 func (j *jsonrpcTransaction) Hash() ethgo.Hash {
@@ -74,16 +153,31 @@ func (j *jsonrpcTransaction) WithOpts(opts *TxnOpts) {
 }
 
 func (j *jsonrpcTransaction) Build() error {
+	return j.BuildContext(context.Background())
+}
+
+func (j *jsonrpcTransaction) BuildContext(ctx context.Context) error {
 	var err error
 	from := j.key.Address()
+	client := j.client.WithContext(ctx)
+
+	// blob transactions are always dynamic fee transactions
+	dynamicFee := j.eip1559 || j.opts.Sidecar != nil
 
-	// estimate gas price
-	if j.opts.GasPrice == 0 && !j.eip1559 {
-		j.opts.GasPrice, err = j.client.GasPrice()
+	// estimate the fees
+	var fee *feeoracle.Fee
+	if dynamicFee || j.opts.GasPrice == 0 {
+		fee, err = j.feeStrategy(dynamicFee).SuggestFee(ctx)
 		if err != nil {
 			return err
 		}
 	}
+	if j.opts.GasPrice == 0 && !dynamicFee {
+		if !fee.GasPrice.IsUint64() {
+			return fmt.Errorf("gas price %s overflows", fee.GasPrice)
+		}
+		j.opts.GasPrice = fee.GasPrice.Uint64()
+	}
 	// estimate gas limit
 	if j.opts.GasLimit == 0 {
 		msg := &ethgo.CallMsg{
@@ -96,20 +190,20 @@ func (j *jsonrpcTransaction) Build() error {
 		if j.to != ethgo.ZeroAddress {
 			msg.To = &j.to
 		}
-		j.opts.GasLimit, err = j.client.EstimateGas(msg)
+		j.opts.GasLimit, err = client.EstimateGas(msg)
 		if err != nil {
 			return err
 		}
 	}
 	// calculate the nonce
 	if j.opts.Nonce == 0 {
-		j.opts.Nonce, err = j.client.GetNonce(from, ethgo.Latest)
+		j.opts.Nonce, err = client.GetNonce(from, ethgo.Latest)
 		if err != nil {
 			return fmt.Errorf("failed to calculate nonce: %v", err)
 		}
 	}
 
-	chainID, err := j.client.ChainID()
+	chainID, err := j.chainID.get(client)
 	if err != nil {
 		return err
 	}
@@ -128,16 +222,25 @@ func (j *jsonrpcTransaction) Build() error {
 		rawTxn.To = &j.to
 	}
 
-	if j.eip1559 {
+	if dynamicFee {
 		rawTxn.Type = ethgo.TransactionDynamicFee
-
-		// use gas price as fee data
-		gasPrice, err := j.client.GasPrice()
-		if err != nil {
-			return err
+		rawTxn.MaxFeePerGas = fee.MaxFeePerGas
+		rawTxn.MaxPriorityFeePerGas = fee.MaxPriorityFeePerGas
+	}
+	if j.opts.Sidecar != nil {
+		rawTxn.Type = ethgo.TransactionBlob
+		rawTxn.Sidecar = j.opts.Sidecar
+		rawTxn.BlobVersionedHashes = j.opts.Sidecar.VersionedHashes()
+
+		rawTxn.MaxFeePerBlobGas = j.opts.MaxFeePerBlobGas
+		if rawTxn.MaxFeePerBlobGas == nil {
+			// pay for twice the current blob base fee
+			blobBaseFee, err := client.BlobBaseFee()
+			if err != nil {
+				return err
+			}
+			rawTxn.MaxFeePerBlobGas = blobBaseFee.Mul(blobBaseFee, big.NewInt(2))
 		}
-		rawTxn.MaxFeePerGas = new(big.Int).SetUint64(gasPrice)
-		rawTxn.MaxPriorityFeePerGas = new(big.Int).SetUint64(gasPrice)
 	}
 
 	j.txn = rawTxn
@@ -145,13 +248,17 @@ func (j *jsonrpcTransaction) Build() error {
 }
 
 func (j *jsonrpcTransaction) Do() error {
+	return j.DoContext(context.Background())
+}
+
+func (j *jsonrpcTransaction) DoContext(ctx context.Context) error {
 	if j.txn == nil {
-		if err := j.Build(); err != nil {
+		if err := j.BuildContext(ctx); err != nil {
 			return err
 		}
 	}
 
-	signer := wallet.NewEIP155Signer(j.txn.ChainID.Uint64())
+	signer := wallet.LatestSignerForChainID(j.txn.ChainID.Uint64())
 	signedTxn, err := signer.SignTx(j.txn, j.key)
 	if err != nil {
 		return err
@@ -162,7 +269,7 @@ func (j *jsonrpcTransaction) Do() error {
 	}
 
 	j.txnRaw = txnRaw
-	hash, err := j.client.SendRawTransaction(j.txnRaw)
+	hash, err := j.client.WithContext(ctx).SendRawTransaction(j.txnRaw)
 	if err != nil {
 		return err
 	}
@@ -171,23 +278,41 @@ func (j *jsonrpcTransaction) Do() error {
 }
 
 func (j *jsonrpcTransaction) Wait() (*ethgo.Receipt, error) {
+	return j.WaitContext(context.Background())
+}
+
+func (j *jsonrpcTransaction) WaitContext(ctx context.Context) (*ethgo.Receipt, error) {
 	if (j.hash == ethgo.Hash{}) {
 		panic("transaction not executed")
 	}
 
+	client := j.client.WithContext(ctx)
 	for {
-		receipt, err := j.client.GetTransactionReceipt(j.hash)
+		receipt, err := client.GetTransactionReceipt(j.hash)
 		if err != nil {
-			if err.Error() != "not found" {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !errors.Is(err, jsonrpc.ErrNotFound) {
 				return nil, err
 			}
 		}
 		if receipt != nil {
 			return receipt, nil
 		}
+
+		select {
+		case <-time.After(waitPollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
 }
 
+// waitPollInterval is the interval to query the receipt of a
+// transaction while waiting for it to be mined
+var waitPollInterval = 100 * time.Millisecond
+
 // Txn is the transaction object returned
 type Txn interface {
 	Hash() ethgo.Hash
@@ -196,12 +321,21 @@ type Txn interface {
 	Wait() (*ethgo.Receipt, error)
 }
 
+// TxnContext is implemented by the transactions that can be sent and
+// waited with a context (i.e. the transactions of the json-rpc provider)
+type TxnContext interface {
+	DoContext(ctx context.Context) error
+	WaitContext(ctx context.Context) (*ethgo.Receipt, error)
+}
+
 type Opts struct {
 	JsonRPCEndpoint string
 	JsonRPCClient   *jsonrpc.Eth
 	Provider        Provider
 	Sender          ethgo.Key
 	EIP1559         bool
+	FeeStrategy     FeeStrategy
+	JsonRPCOptions  []jsonrpc.ConfigOption
 }
 
 type ContractOption func(*Opts)
@@ -218,6 +352,14 @@ func WithJsonRPC(client *jsonrpc.Eth) ContractOption {
 	}
 }
 
+// WithJsonRPCOptions sets the options of the json-rpc client created for the
+// endpoint (i.e. jsonrpc.WithCache to cache the immutable responses)
+func WithJsonRPCOptions(opts ...jsonrpc.ConfigOption) ContractOption {
+	return func(o *Opts) {
+		o.JsonRPCOptions = append(o.JsonRPCOptions, opts...)
+	}
+}
+
 func WithProvider(provider Provider) ContractOption {
 	return func(o *Opts) {
 		o.Provider = provider
@@ -236,6 +378,14 @@ func WithEIP1559() ContractOption {
 	}
 }
 
+// WithFeeStrategy sets the strategy to compute the fees of the transactions. By default,
+// EIP-1559 transactions use a feeoracle.Oracle and legacy transactions the gas price of the node.
+func WithFeeStrategy(strategy FeeStrategy) ContractOption {
+	return func(o *Opts) {
+		o.FeeStrategy = strategy
+	}
+}
+
 func DeployContract(abi *abi.ABI, bin []byte, args []interface{}, opts ...ContractOption) (Txn, error) {
 	a := NewContract(ethgo.Address{}, abi, opts...)
 	a.bin = bin
@@ -254,10 +404,10 @@ func NewContract(addr ethgo.Address, abi *abi.ABI, opts ...ContractOption) *Cont
 	if opt.Provider != nil {
 		provider = opt.Provider
 	} else if opt.JsonRPCClient != nil {
-		provider = &jsonRPCNodeProvider{client: opt.JsonRPCClient, eip1559: opt.EIP1559}
+		provider = &jsonRPCNodeProvider{client: opt.JsonRPCClient, eip1559: opt.EIP1559, fees: opt.FeeStrategy}
 	} else {
-		client, _ := jsonrpc.NewClient(opt.JsonRPCEndpoint)
-		provider = &jsonRPCNodeProvider{client: client.Eth(), eip1559: opt.EIP1559}
+		client, _ := jsonrpc.NewClient(opt.JsonRPCEndpoint, opt.JsonRPCOptions...)
+		provider = &jsonRPCNodeProvider{client: client.Eth(), eip1559: opt.EIP1559, fees: opt.FeeStrategy}
 	}
 
 	a := &Contract{
@@ -288,6 +438,11 @@ type TxnOpts struct {
 	GasPrice uint64
 	GasLimit uint64
 	Nonce    uint64
+
+	// Sidecar are the blobs sent with the transaction (eip-4844).
+	// MaxFeePerBlobGas defaults to twice the current blob base fee.
+	Sidecar          *ethgo.BlobSidecar
+	MaxFeePerBlobGas *big.Int
 }
 
 func (a *Contract) Txn(method string, args ...interface{}) (Txn, error) {
@@ -332,11 +487,29 @@ func (a *Contract) Txn(method string, args ...interface{}) (Txn, error) {
 }
 
 type CallOpts struct {
-	Block ethgo.BlockNumber
+	// Block is the block number, hash or ethgo.BlockHashSelector
+	// of the call. It defaults to the latest block.
+	Block ethgo.BlockNumberOrHash
 	From  ethgo.Address
+
+	// StateOverrides and BlockOverrides replace the state and the
+	// block of the call (i.e. to simulate a modified contract)
+	StateOverrides jsonrpc.StateOverride
+	BlockOverrides *jsonrpc.BlockOverrides
+}
+
+func (a *Contract) Call(method string, block ethgo.BlockNumberOrHash, args ...interface{}) (map[string]interface{}, error) {
+	return a.CallContext(context.Background(), method, block, args...)
 }
 
-func (a *Contract) Call(method string, block ethgo.BlockNumber, args ...interface{}) (map[string]interface{}, error) {
+// CallContext makes a call to the contract that is aborted if the context is done
+func (a *Contract) CallContext(ctx context.Context, method string, block ethgo.BlockNumberOrHash, args ...interface{}) (map[string]interface{}, error) {
+	return a.CallWithOpts(ctx, method, &CallOpts{Block: block}, args...)
+}
+
+// CallWithOpts makes a call to the contract with the given options. The
+// sender of the call is the key of the contract if it is not set.
+func (a *Contract) CallWithOpts(ctx context.Context, method string, opts *CallOpts, args ...interface{}) (map[string]interface{}, error) {
 	m := a.abi.GetMethod(method)
 	if m == nil {
 		return nil, fmt.Errorf("method %s not found", method)
@@ -347,13 +520,18 @@ func (a *Contract) Call(method string, block ethgo.BlockNumber, args ...interfac
 		return nil, err
 	}
 
-	opts := &CallOpts{
-		Block: block,
+	callOpts := *opts
+	if callOpts.From == ethgo.ZeroAddress && a.key != nil {
+		callOpts.From = a.key.Address()
 	}
-	if a.key != nil {
-		opts.From = a.key.Address()
+	opts = &callOpts
+
+	var rawOutput []byte
+	if provider, ok := a.provider.(ProviderContext); ok {
+		rawOutput, err = provider.CallContext(ctx, a.addr, data, opts)
+	} else {
+		rawOutput, err = a.provider.Call(a.addr, data, opts)
 	}
-	rawOutput, err := a.provider.Call(a.addr, data, opts)
 	if err != nil {
 		return nil, err
 	}
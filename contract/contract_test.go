@@ -1,9 +1,16 @@
 package contract
 
 import (
+	"context"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/umbracle/ethgo"
@@ -299,3 +306,132 @@ func TestContract_EIP1559(t *testing.T) {
 	assert.NotZero(t, txnObj.MaxFeePerGas)
 	assert.NotZero(t, txnObj.MaxPriorityFeePerGas)
 }
+
+type mockCallProvider struct {
+	Provider
+	opts *CallOpts
+	out  []byte
+}
+
+func (m *mockCallProvider) CallContext(ctx context.Context, addr ethgo.Address, input []byte, opts *CallOpts) ([]byte, error) {
+	m.opts = opts
+	return m.out, nil
+}
+
+// mockLegacyCallProvider only implements the Call method without context
+type mockLegacyCallProvider struct {
+	Provider
+	opts *CallOpts
+}
+
+func (m *mockLegacyCallProvider) Call(addr ethgo.Address, input []byte, opts *CallOpts) ([]byte, error) {
+	m.opts = opts
+
+	out := make([]byte, 32)
+	out[31] = 0x2
+	return out, nil
+}
+
+func TestContract_CallWithOpts(t *testing.T) {
+	abi0, err := abi.NewABIFromList([]string{
+		"function getVal() view returns (uint256)",
+	})
+	assert.NoError(t, err)
+
+	key, _ := wallet.GenerateKey()
+	provider := &mockCallProvider{out: make([]byte, 32)}
+	provider.out[31] = 0x1
+
+	c := NewContract(addr0B, abi0, WithProvider(provider), WithSender(key))
+
+	code := ethgo.ArgBytes{0x1}
+	opts := &CallOpts{
+		Block:          ethgo.BlockHashSelector{Hash: ethgo.Hash{0x1}},
+		StateOverrides: jsonrpc.StateOverride{addr0B: {Code: &code}},
+	}
+	vals, err := c.CallWithOpts(context.Background(), "getVal", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, vals["0"], big.NewInt(1))
+
+	// the sender defaults to the key of the contract
+	assert.Equal(t, key.Address(), provider.opts.From)
+	assert.Equal(t, opts.Block, provider.opts.Block)
+	assert.Equal(t, opts.StateOverrides, provider.opts.StateOverrides)
+	assert.Equal(t, ethgo.ZeroAddress, opts.From)
+}
+
+func TestContract_CallLegacyProvider(t *testing.T) {
+	abi0, err := abi.NewABIFromList([]string{
+		"function getVal() view returns (uint256)",
+	})
+	assert.NoError(t, err)
+
+	provider := &mockLegacyCallProvider{}
+	c := NewContract(addr0B, abi0, WithProvider(provider))
+
+	vals, err := c.CallContext(context.Background(), "getVal", ethgo.Latest)
+	assert.NoError(t, err)
+	assert.Equal(t, vals["0"], big.NewInt(2))
+	assert.Equal(t, ethgo.Latest, provider.opts.Block)
+
+	// the json-rpc provider and transactions implement the context interfaces
+	var _ ProviderContext = &jsonRPCNodeProvider{}
+	var _ TxnContext = &jsonrpcTransaction{}
+}
+
+func TestContract_ChainIDCache(t *testing.T) {
+	calls := int32(0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "eth_chainId", req.Method)
+
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":"0x5"}`, req.ID)))
+	}))
+	defer srv.Close()
+
+	client, err := jsonrpc.NewClient(srv.URL)
+	assert.NoError(t, err)
+
+	key, _ := wallet.GenerateKey()
+	c := NewContract(addr0B, nil, WithJsonRPC(client.Eth()), WithSender(key))
+
+	for i := 0; i < 2; i++ {
+		txn, err := c.provider.Txn(addr0B, key, nil)
+		assert.NoError(t, err)
+
+		txn.WithOpts(&TxnOpts{GasPrice: 1, GasLimit: 21000, Nonce: 1})
+		assert.NoError(t, txn.(*jsonrpcTransaction).Build())
+		assert.Equal(t, big.NewInt(5), txn.(*jsonrpcTransaction).txn.ChainID)
+	}
+
+	// the chain id is only queried once by the provider
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestContract_WaitMethodNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-32000,"message":"method not found"}}`, req.ID)))
+	}))
+	defer srv.Close()
+
+	client, err := jsonrpc.NewClient(srv.URL)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// the error is returned instead of polling the receipt until the timeout
+	txn := &jsonrpcTransaction{hash: ethgo.Hash{0x1}, client: client.Eth()}
+	_, err = txn.WaitContext(ctx)
+	assert.Error(t, err)
+	assert.NoError(t, ctx.Err())
+}
@@ -1,6 +1,7 @@
 package ens
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -83,11 +84,21 @@ func NewENS(opts ...EnsOption) (*ENS, error) {
 }
 
 func (e *ENS) Resolve(name string) (ethgo.Address, error) {
-	resolver := ens.NewENSResolver(e.config.Resolver, e.config.Client)
+	return e.ResolveContext(context.Background(), name)
+}
+
+// ResolveContext resolves the name and aborts the requests if the context is done
+func (e *ENS) ResolveContext(ctx context.Context, name string) (ethgo.Address, error) {
+	resolver := ens.NewENSResolver(e.config.Resolver, e.config.Client).WithContext(ctx)
 	return resolver.Resolve(name)
 }
 
 func (e *ENS) ReverseResolve(addr ethgo.Address) (string, error) {
-	resolver := ens.NewENSResolver(e.config.Resolver, e.config.Client)
+	return e.ReverseResolveContext(context.Background(), addr)
+}
+
+// ReverseResolveContext reverse resolves the address and aborts the requests if the context is done
+func (e *ENS) ReverseResolveContext(ctx context.Context, addr ethgo.Address) (string, error) {
+	resolver := ens.NewENSResolver(e.config.Resolver, e.config.Client).WithContext(ctx)
 	return resolver.ReverseResolve(addr)
 }
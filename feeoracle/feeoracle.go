@@ -0,0 +1,375 @@
+package feeoracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+// Tier is the speed at which a transaction is expected to be included
+type Tier int
+
+const (
+	Slow Tier = iota
+	Standard
+	Fast
+)
+
+func (t Tier) String() string {
+	switch t {
+	case Slow:
+		return "slow"
+	case Standard:
+		return "standard"
+	case Fast:
+		return "fast"
+	}
+	return fmt.Sprintf("Tier(%d)", int(t))
+}
+
+// Fee are the fees suggested for a tier. GasPrice is the price of a legacy
+// transaction, on pre-London chains the max fee and the priority fee are
+// equal to the gas price.
+type Fee struct {
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	GasPrice             *big.Int
+}
+
+// Suggestion are the fees suggested for each tier
+type Suggestion struct {
+	// BaseFee is the base fee of the next block. It is nil on pre-London chains.
+	BaseFee *big.Int
+
+	// Legacy is set if the fees are computed from the gas price of the transactions
+	Legacy bool
+
+	Slow     *Fee
+	Standard *Fee
+	Fast     *Fee
+}
+
+// Fee returns the fees of the tier
+func (s *Suggestion) Fee(tier Tier) *Fee {
+	switch tier {
+	case Slow:
+		return s.Slow
+	case Fast:
+		return s.Fast
+	}
+	return s.Standard
+}
+
+// Config is the configuration of the oracle
+type Config struct {
+	// Blocks is the number of past blocks used to compute the fees
+	Blocks uint64
+
+	// Percentiles are the percentiles (between 0 and 100) of the priority fees
+	// (or the gas prices in legacy mode) paid in the past blocks for each tier
+	Percentiles [3]float64
+
+	// BlocksAhead is the number of consecutive full blocks (each one increases the
+	// base fee by 12.5%) that the max fee of each tier can pay for
+	BlocksAhead [3]uint64
+
+	// Tier is the tier used by SuggestFee
+	Tier Tier
+
+	// Legacy computes the fees from the gas price of the transactions
+	// in the past blocks even if the chain supports EIP-1559
+	Legacy bool
+
+	// MinPriorityFee is the minimum priority fee suggested
+	MinPriorityFee *big.Int
+}
+
+// DefaultConfig returns the default configuration of the oracle
+func DefaultConfig() *Config {
+	return &Config{
+		Blocks:      20,
+		Percentiles: [3]float64{10, 50, 90},
+		BlocksAhead: [3]uint64{1, 3, 6},
+		Tier:        Standard,
+	}
+}
+
+type Option func(*Config)
+
+// WithBlocks sets the number of past blocks used to compute the fees
+func WithBlocks(blocks uint64) Option {
+	return func(c *Config) {
+		c.Blocks = blocks
+	}
+}
+
+// WithPercentiles sets the percentiles of the slow, standard and fast tiers
+func WithPercentiles(slow, standard, fast float64) Option {
+	return func(c *Config) {
+		c.Percentiles = [3]float64{slow, standard, fast}
+	}
+}
+
+// WithBlocksAhead sets the number of full blocks the max fee of each tier can pay for
+func WithBlocksAhead(slow, standard, fast uint64) Option {
+	return func(c *Config) {
+		c.BlocksAhead = [3]uint64{slow, standard, fast}
+	}
+}
+
+// WithTier sets the tier used by SuggestFee
+func WithTier(tier Tier) Option {
+	return func(c *Config) {
+		c.Tier = tier
+	}
+}
+
+// WithLegacy computes the fees from the gas price of the transactions
+func WithLegacy() Option {
+	return func(c *Config) {
+		c.Legacy = true
+	}
+}
+
+// WithMinPriorityFee sets the minimum priority fee suggested
+func WithMinPriorityFee(fee *big.Int) Option {
+	return func(c *Config) {
+		c.MinPriorityFee = fee
+	}
+}
+
+// Oracle suggests the fees of the transactions from the fees paid in the past blocks
+type Oracle struct {
+	client *jsonrpc.Eth
+	config *Config
+}
+
+// NewOracle creates a new fee oracle
+func NewOracle(client *jsonrpc.Eth, opts ...Option) *Oracle {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return &Oracle{
+		client: client,
+		config: config,
+	}
+}
+
+// SuggestFee returns the fees of the tier of the oracle
+func (o *Oracle) SuggestFee(ctx context.Context) (*Fee, error) {
+	suggestion, err := o.Suggest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return suggestion.Fee(o.config.Tier), nil
+}
+
+// Suggest returns the fees of the slow, standard and fast tiers. It uses the
+// priority fees and the base fees of the past blocks (eth_feeHistory) unless
+// the chain does not support EIP-1559 or the oracle is in legacy mode.
+func (o *Oracle) Suggest(ctx context.Context) (*Suggestion, error) {
+	if o.config.Legacy {
+		return o.suggestLegacy(ctx)
+	}
+
+	client := o.client.WithContext(ctx)
+	history, err := client.FeeHistoryWithRewards(o.config.Blocks, ethgo.Latest, o.config.Percentiles[:]...)
+	if err != nil {
+		var obj *codec.ErrorObject
+		if errors.As(err, &obj) && obj.Code == codeMethodNotFound {
+			return o.suggestLegacy(ctx)
+		}
+		return nil, err
+	}
+
+	// the last base fee is the one of the next block
+	if len(history.BaseFee) == 0 || history.BaseFee[len(history.BaseFee)-1].Sign() == 0 {
+		return o.suggestLegacy(ctx)
+	}
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	tips, err := o.priorityFees(client, history)
+	if err != nil {
+		return nil, err
+	}
+
+	// the max fee pays for the highest base fee of the past blocks increased
+	// as if the next blocks were full. If the blocks are above the gas target
+	// (the base fee is rising) it pays for one more full block.
+	maxBaseFee := new(big.Int).Set(baseFee)
+	for _, fee := range history.BaseFee {
+		if fee.Cmp(maxBaseFee) > 0 {
+			maxBaseFee.Set(fee)
+		}
+	}
+	rising := averageRatio(history.GasUsedRatio) > 0.5
+
+	fees := [3]*Fee{}
+	for tier := range fees {
+		blocks := o.config.BlocksAhead[tier]
+		if rising {
+			blocks++
+		}
+		maxFee := increaseBaseFee(maxBaseFee, blocks)
+
+		fees[tier] = &Fee{
+			MaxFeePerGas:         maxFee.Add(maxFee, tips[tier]),
+			MaxPriorityFeePerGas: tips[tier],
+			GasPrice:             new(big.Int).Add(baseFee, tips[tier]),
+		}
+	}
+
+	suggestion := &Suggestion{
+		BaseFee:  new(big.Int).Set(baseFee),
+		Slow:     fees[Slow],
+		Standard: fees[Standard],
+		Fast:     fees[Fast],
+	}
+	return suggestion, nil
+}
+
+// priorityFees returns the median of the priority fees paid at the percentile of each
+// tier in the non empty past blocks. If all the blocks are empty it uses the priority
+// fee suggested by the node.
+func (o *Oracle) priorityFees(client *jsonrpc.Eth, history *jsonrpc.FeeHistory) ([3]*big.Int, error) {
+	tips := [3]*big.Int{}
+	for tier := range tips {
+		values := []*big.Int{}
+		for indx, reward := range history.Reward {
+			if indx < len(history.GasUsedRatio) && history.GasUsedRatio[indx] == 0 {
+				// the block is empty and the rewards are zero
+				continue
+			}
+			if tier < len(reward) {
+				values = append(values, reward[tier])
+			}
+		}
+		if len(values) != 0 {
+			tips[tier] = new(big.Int).Set(percentile(values, 50))
+		}
+	}
+
+	if tips[Slow] == nil || tips[Standard] == nil || tips[Fast] == nil {
+		tip, err := client.MaxPriorityFeePerGas()
+		if err != nil {
+			return tips, err
+		}
+		for tier := range tips {
+			tips[tier] = new(big.Int).Set(tip)
+		}
+	}
+	for tier := range tips {
+		if tier > 0 && tips[tier].Cmp(tips[tier-1]) < 0 {
+			// a faster tier never pays less
+			tips[tier].Set(tips[tier-1])
+		}
+		if o.config.MinPriorityFee != nil && tips[tier].Cmp(o.config.MinPriorityFee) < 0 {
+			tips[tier].Set(o.config.MinPriorityFee)
+		}
+	}
+	return tips, nil
+}
+
+// suggestLegacy returns the percentiles of the gas price of the transactions in the
+// past blocks. If there are no transactions it uses the gas price suggested by the node.
+func (o *Oracle) suggestLegacy(ctx context.Context) (*Suggestion, error) {
+	client := o.client.WithContext(ctx)
+
+	num, err := client.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	prices := []*big.Int{}
+	for i := uint64(0); i < o.config.Blocks && i <= num; i++ {
+		block, err := client.GetBlockByNumber(ethgo.BlockNumber(num-i), true)
+		if err != nil {
+			return nil, err
+		}
+		for _, txn := range block.Transactions {
+			prices = append(prices, new(big.Int).SetUint64(txn.GasPrice))
+		}
+	}
+
+	fees := [3]*Fee{}
+	for tier := range fees {
+		var price *big.Int
+		if len(prices) != 0 {
+			price = new(big.Int).Set(percentile(prices, o.config.Percentiles[tier]))
+		} else {
+			gasPrice, err := client.GasPrice()
+			if err != nil {
+				return nil, err
+			}
+			price = new(big.Int).SetUint64(gasPrice)
+		}
+		if tier > 0 && price.Cmp(fees[tier-1].GasPrice) < 0 {
+			price.Set(fees[tier-1].GasPrice)
+		}
+		if o.config.MinPriorityFee != nil && price.Cmp(o.config.MinPriorityFee) < 0 {
+			price.Set(o.config.MinPriorityFee)
+		}
+		fees[tier] = &Fee{
+			MaxFeePerGas:         new(big.Int).Set(price),
+			MaxPriorityFeePerGas: new(big.Int).Set(price),
+			GasPrice:             price,
+		}
+	}
+
+	suggestion := &Suggestion{
+		Legacy:   true,
+		Slow:     fees[Slow],
+		Standard: fees[Standard],
+		Fast:     fees[Fast],
+	}
+	return suggestion, nil
+}
+
+// codeMethodNotFound is the jsonrpc code returned if the node does not support eth_feeHistory
+const codeMethodNotFound = -32601
+
+// percentile returns the value at the percentile (between 0 and 100) of the values
+func percentile(values []*big.Int, p float64) *big.Int {
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+
+	indx := int(p / 100 * float64(len(sorted)-1))
+	if indx < 0 {
+		indx = 0
+	} else if indx >= len(sorted) {
+		indx = len(sorted) - 1
+	}
+	return sorted[indx]
+}
+
+func averageRatio(ratios []float64) float64 {
+	if len(ratios) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, ratio := range ratios {
+		sum += ratio
+	}
+	return sum / float64(len(ratios))
+}
+
+// increaseBaseFee returns the base fee after the given number of full
+// blocks, each one of them increases the base fee by 12.5% (EIP-1559)
+func increaseBaseFee(baseFee *big.Int, blocks uint64) *big.Int {
+	fee := new(big.Int).Set(baseFee)
+	for i := uint64(0); i < blocks; i++ {
+		fee.Mul(fee, big.NewInt(9))
+		fee.Div(fee, big.NewInt(8))
+	}
+	return fee
+}
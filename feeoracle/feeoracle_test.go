@@ -0,0 +1,181 @@
+package feeoracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo/jsonrpc"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+func newTestOracle(t *testing.T, results map[string]string, opts ...Option) (*Oracle, map[string]json.RawMessage) {
+	params := map[string]json.RawMessage{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req codec.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		params[req.Method] = req.Params
+
+		resp := &codec.Response{ID: req.ID}
+		if result, ok := results[req.Method]; ok {
+			resp.Result = json.RawMessage(result)
+		} else {
+			resp.Error = &codec.ErrorObject{Code: -32601, Message: "method not found"}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := jsonrpc.NewClient(srv.URL)
+	assert.NoError(t, err)
+
+	return NewOracle(client.Eth(), opts...), params
+}
+
+func TestOracle_FeeHistory(t *testing.T) {
+	oracle, params := newTestOracle(t, map[string]string{
+		"eth_feeHistory": `{
+			"oldestBlock": "0x1",
+			"baseFeePerGas": ["0x64", "0x6e", "0x78", "0x64"],
+			"gasUsedRatio": [0.9, 0, 0.8],
+			"reward": [
+				["0x1", "0x5", "0xa"],
+				["0x0", "0x0", "0x0"],
+				["0x3", "0x7", "0x2"]
+			]
+		}`,
+	}, WithBlocks(3))
+
+	res, err := oracle.Suggest(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, `["0x3","latest",[10,50,90]]`, string(params["eth_feeHistory"]))
+
+	assert.False(t, res.Legacy)
+	assert.Equal(t, "100", res.BaseFee.String())
+
+	// the empty block is not used and the fast tier pays at least the standard tier
+	assert.Equal(t, "1", res.Slow.MaxPriorityFeePerGas.String())
+	assert.Equal(t, "5", res.Standard.MaxPriorityFeePerGas.String())
+	assert.Equal(t, "5", res.Fast.MaxPriorityFeePerGas.String())
+
+	// the base fee is rising, the max fee pays for the highest base fee (120)
+	// increased by one more full block than the default
+	assert.Equal(t, big.NewInt(151+1), res.Slow.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(190+5), res.Standard.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(268+5), res.Fast.MaxFeePerGas)
+
+	assert.Equal(t, "105", res.Standard.GasPrice.String())
+
+	fee, err := oracle.SuggestFee(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, res.Standard, fee)
+}
+
+func TestOracle_EmptyBlocks(t *testing.T) {
+	oracle, _ := newTestOracle(t, map[string]string{
+		"eth_feeHistory": `{
+			"oldestBlock": "0x1",
+			"baseFeePerGas": ["0x64", "0x64"],
+			"gasUsedRatio": [0],
+			"reward": [["0x0", "0x0", "0x0"]]
+		}`,
+		"eth_maxPriorityFeePerGas": `"0x10"`,
+	}, WithMinPriorityFee(big.NewInt(20)), WithTier(Fast))
+
+	fee, err := oracle.SuggestFee(context.Background())
+	assert.NoError(t, err)
+
+	// the node suggestion is used and then raised to the minimum
+	assert.Equal(t, "20", fee.MaxPriorityFeePerGas.String())
+	assert.Equal(t, big.NewInt(199+20), fee.MaxFeePerGas)
+}
+
+func TestOracle_Legacy(t *testing.T) {
+	block := func(num string, prices ...string) string {
+		txns := []map[string]string{}
+		for _, price := range prices {
+			txns = append(txns, map[string]string{
+				"hash":     "0x0000000000000000000000000000000000000000000000000000000000000001",
+				"from":     "0x0000000000000000000000000000000000000001",
+				"input":    "0x",
+				"gas":      "0x5208",
+				"gasPrice": price,
+				"value":    "0x0",
+				"nonce":    "0x0",
+				"v":        "0x1b",
+				"r":        "0x1",
+				"s":        "0x1",
+			})
+		}
+		data, _ := json.Marshal(map[string]interface{}{
+			"number":           num,
+			"hash":             "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"parentHash":       "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"sha3Uncles":       "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"transactionsRoot": "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"stateRoot":        "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"receiptsRoot":     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"miner":            "0x0000000000000000000000000000000000000000",
+			"gasLimit":         "0x0",
+			"gasUsed":          "0x0",
+			"timestamp":        "0x0",
+			"difficulty":       "0x0",
+			"extraData":        "0x",
+			"transactions":     txns,
+		})
+		return string(data)
+	}
+
+	results := map[string]string{
+		"eth_blockNumber":      `"0x1"`,
+		"eth_getBlockByNumber": block("0x1", "0x1", "0x2", "0x3", "0x4", "0x5"),
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		// eth_feeHistory is not available
+		oracle, _ := newTestOracle(t, results, WithBlocks(1))
+
+		res, err := oracle.Suggest(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, res.Legacy)
+		assert.Nil(t, res.BaseFee)
+
+		assert.Equal(t, "1", res.Slow.GasPrice.String())
+		assert.Equal(t, "3", res.Standard.GasPrice.String())
+		assert.Equal(t, "4", res.Fast.GasPrice.String())
+		assert.Equal(t, res.Fast.GasPrice, res.Fast.MaxFeePerGas)
+		assert.Equal(t, res.Fast.GasPrice, res.Fast.MaxPriorityFeePerGas)
+	})
+
+	t.Run("NoBaseFee", func(t *testing.T) {
+		results := map[string]string{
+			"eth_feeHistory":       `{"oldestBlock": "0x1", "baseFeePerGas": ["0x0", "0x0"], "gasUsedRatio": [0.5]}`,
+			"eth_blockNumber":      `"0x1"`,
+			"eth_getBlockByNumber": block("0x1"),
+			"eth_gasPrice":         `"0x10"`,
+		}
+		oracle, _ := newTestOracle(t, results, WithBlocks(1))
+
+		// there are no transactions, the gas price of the node is used
+		res, err := oracle.Suggest(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, res.Legacy)
+		assert.Equal(t, "16", res.Slow.GasPrice.String())
+		assert.Equal(t, "16", res.Fast.GasPrice.String())
+	})
+
+	t.Run("Forced", func(t *testing.T) {
+		oracle, params := newTestOracle(t, results, WithBlocks(1), WithLegacy(), WithPercentiles(0, 0, 100))
+
+		res, err := oracle.Suggest(context.Background())
+		assert.NoError(t, err)
+		assert.NotContains(t, params, "eth_feeHistory")
+		assert.Equal(t, "1", res.Standard.GasPrice.String())
+		assert.Equal(t, "5", res.Fast.GasPrice.String())
+	})
+}
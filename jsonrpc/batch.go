@@ -0,0 +1,143 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/transport"
+)
+
+// BatchElem is a single request in a batch call
+type BatchElem = transport.BatchElem
+
+// BatchCall sends all the requests in a single jsonrpc batch. The result or
+// the error of each request is set in its BatchElem. The returned error is
+// only set if the batch itself could not be sent. If the transport does not
+// support batches, the requests are sent one by one.
+func (c *Client) BatchCall(elems []*BatchElem) error {
+	return c.BatchCallContext(context.Background(), elems)
+}
+
+// BatchCallContext sends the batch request and aborts it if the context is done
+func (c *Client) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	if c.invoke == nil {
+		return c.batchCall(ctx, elems)
+	}
+	if elems == nil {
+		elems = []*BatchElem{}
+	}
+	return c.invoke(ctx, &Request{Batch: elems})
+}
+
+func (c *Client) batchCall(ctx context.Context, elems []*BatchElem) error {
+	if batch, ok := c.transport.(transport.BatchTransport); ok {
+		return batch.BatchCallContext(ctx, elems)
+	}
+	for _, elem := range elems {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		elem.Error = transport.CallContext(ctx, c.transport, elem.Method, elem.Result, elem.Params...)
+	}
+	return nil
+}
+
+// batchError returns the first error in the batch elements
+func batchError(elems []*BatchElem) error {
+	for indx, elem := range elems {
+		if elem.Error != nil {
+			return fmt.Errorf("batch element %d (%s) failed: %w", indx, elem.Method, elem.Error)
+		}
+	}
+	return nil
+}
+
+// GetBlocksByNumber returns the blocks by number with a single batch request
+func (e *Eth) GetBlocksByNumber(nums []ethgo.BlockNumber, full bool) ([]*ethgo.Block, error) {
+	blocks := make([]*ethgo.Block, len(nums))
+	elems := make([]*BatchElem, len(nums))
+	for indx, num := range nums {
+		elems[indx] = &BatchElem{
+			Method: "eth_getBlockByNumber",
+			Params: []interface{}{num.String(), full},
+			Result: &blocks[indx],
+		}
+	}
+	if err := e.c.BatchCallContext(e.ctx, elems); err != nil {
+		return nil, err
+	}
+	if err := batchError(elems); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// GetBlocksByHash returns the blocks by hash with a single batch request
+func (e *Eth) GetBlocksByHash(hashes []ethgo.Hash, full bool) ([]*ethgo.Block, error) {
+	blocks := make([]*ethgo.Block, len(hashes))
+	elems := make([]*BatchElem, len(hashes))
+	for indx, hash := range hashes {
+		elems[indx] = &BatchElem{
+			Method: "eth_getBlockByHash",
+			Params: []interface{}{hash, full},
+			Result: &blocks[indx],
+		}
+	}
+	if err := e.c.BatchCallContext(e.ctx, elems); err != nil {
+		return nil, err
+	}
+	if err := batchError(elems); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// GetTransactionReceipts returns the receipts of the transactions with a single batch request
+func (e *Eth) GetTransactionReceipts(hashes []ethgo.Hash) ([]*ethgo.Receipt, error) {
+	receipts := make([]*ethgo.Receipt, len(hashes))
+	elems := make([]*BatchElem, len(hashes))
+	for indx, hash := range hashes {
+		elems[indx] = &BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Params: []interface{}{hash},
+			Result: &receipts[indx],
+		}
+	}
+	if err := e.c.BatchCallContext(e.ctx, elems); err != nil {
+		return nil, err
+	}
+	if err := batchError(elems); err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// GetBalances returns the balances of the accounts with a single batch request
+func (e *Eth) GetBalances(addrs []ethgo.Address, blockNumber ethgo.BlockNumberOrHash) ([]*big.Int, error) {
+	out := make([]string, len(addrs))
+	elems := make([]*BatchElem, len(addrs))
+	for indx, addr := range addrs {
+		elems[indx] = &BatchElem{
+			Method: "eth_getBalance",
+			Params: []interface{}{addr, blockParam(blockNumber)},
+			Result: &out[indx],
+		}
+	}
+	if err := e.c.BatchCallContext(e.ctx, elems); err != nil {
+		return nil, err
+	}
+	if err := batchError(elems); err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(addrs))
+	for indx, str := range out {
+		b, ok := new(big.Int).SetString(str[2:], 16)
+		if !ok {
+			return nil, fmt.Errorf("failed to convert to big.int")
+		}
+		balances[indx] = b
+	}
+	return balances, nil
+}
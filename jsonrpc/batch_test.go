@@ -0,0 +1,51 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/testutil"
+)
+
+func TestBatchGetBlocksByNumber(t *testing.T) {
+	testutil.MultiAddr(t, nil, func(s *testutil.TestServer, addr string) {
+		c, _ := NewClient(addr)
+		defer c.Close()
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, s.ProcessBlock())
+		}
+
+		num, err := c.Eth().BlockNumber()
+		assert.NoError(t, err)
+
+		nums := []ethgo.BlockNumber{}
+		for i := uint64(0); i <= num; i++ {
+			nums = append(nums, ethgo.BlockNumber(i))
+		}
+		blocks, err := c.Eth().GetBlocksByNumber(nums, false)
+		assert.NoError(t, err)
+		assert.Len(t, blocks, len(nums))
+
+		for indx, block := range blocks {
+			assert.Equal(t, uint64(indx), block.Number)
+		}
+	})
+}
+
+func TestBatchCallError(t *testing.T) {
+	testutil.MultiAddr(t, nil, func(s *testutil.TestServer, addr string) {
+		c, _ := NewClient(addr)
+		defer c.Close()
+
+		var num string
+		elems := []*BatchElem{
+			{Method: "eth_blockNumber", Result: &num},
+			{Method: "eth_unknownMethod"},
+		}
+		assert.NoError(t, c.BatchCall(elems))
+		assert.NoError(t, elems[0].Error)
+		assert.Error(t, elems[1].Error)
+	})
+}
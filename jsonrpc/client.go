@@ -1,13 +1,22 @@
 package jsonrpc
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"time"
+
 	"github.com/umbracle/ethgo/jsonrpc/transport"
 )
 
 // Client is the jsonrpc client
 type Client struct {
 	transport transport.Transport
+	pubsub    transport.Transport
 	endpoints endpoints
+	subs      *subscriptionManager
+	invoke    Invoker
 }
 
 type endpoints struct {
@@ -15,10 +24,18 @@ type endpoints struct {
 	e *Eth
 	n *Net
 	d *Debug
+	t *Trace
+
+	engine *Engine
+	txpool *TxPool
 }
 
 type Config struct {
-	headers map[string]string
+	headers      map[string]string
+	interceptors []Interceptor
+	cache        []transport.CacheOption
+	useCache     bool
+	transport    []transport.Option
 }
 
 type ConfigOption func(*Config)
@@ -31,24 +48,80 @@ func WithHeaders(headers map[string]string) ConfigOption {
 	}
 }
 
+// WithAuth sets the credentials of the http and websocket requests
+// (i.e. a transport.JWTAuth for the engine api or a transport.BearerAuth)
+func WithAuth(auth transport.Auth) ConfigOption {
+	return func(c *Config) {
+		c.transport = append(c.transport, transport.WithAuth(auth))
+	}
+}
+
+// WithHTTPClient sets the client of the http transport
+func WithHTTPClient(client *http.Client) ConfigOption {
+	return func(c *Config) {
+		c.transport = append(c.transport, transport.WithHTTPClient(client))
+	}
+}
+
+// WithTLSConfig sets the tls configuration of the http and websocket transports
+func WithTLSConfig(config *tls.Config) ConfigOption {
+	return func(c *Config) {
+		c.transport = append(c.transport, transport.WithTLSConfig(config))
+	}
+}
+
+// WithCache caches the responses that cannot change (see transport.Cache)
+func WithCache(opts ...transport.CacheOption) ConfigOption {
+	return func(c *Config) {
+		c.useCache = true
+		c.cache = append(c.cache, opts...)
+	}
+}
+
 func NewClient(addr string, opts ...ConfigOption) (*Client, error) {
 	config := &Config{headers: map[string]string{}}
 	for _, opt := range opts {
 		opt(config)
 	}
 
-	c := &Client{}
-	c.endpoints.w = &Web3{c}
-	c.endpoints.e = &Eth{c}
-	c.endpoints.n = &Net{c}
-	c.endpoints.d = &Debug{c}
-
-	t, err := transport.NewTransport(addr, config.headers)
+	t, err := transport.NewTransport(addr, config.headers, config.transport...)
 	if err != nil {
 		return nil, err
 	}
-	c.transport = t
-	return c, nil
+	return newClient(t, config), nil
+}
+
+// NewClientWithTransport creates a client that sends the requests with
+// the given transport (i.e. a transport wrapped with retries). The headers
+// option is not used since the transport is already created.
+func NewClientWithTransport(t transport.Transport, opts ...ConfigOption) *Client {
+	config := &Config{headers: map[string]string{}}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return newClient(t, config)
+}
+
+func newClient(t transport.Transport, config *Config) *Client {
+	c := &Client{
+		transport: t,
+		pubsub:    t,
+	}
+	if config.useCache {
+		c.transport = transport.NewCache(t, config.cache...)
+	}
+	if len(config.interceptors) != 0 {
+		c.invoke = chainInterceptors(config.interceptors, c.send)
+	}
+	c.subs = newSubscriptionManager(c)
+	c.endpoints.w = &Web3{c, context.Background()}
+	c.endpoints.e = &Eth{c, context.Background()}
+	c.endpoints.n = &Net{c, context.Background()}
+	c.endpoints.d = &Debug{c, context.Background()}
+	c.endpoints.t = &Trace{c, context.Background()}
+	c.endpoints.engine = &Engine{c, context.Background()}
+	c.endpoints.txpool = &TxPool{c, context.Background()}
+	return c
 }
 
 // Close closes the transport
@@ -58,7 +131,36 @@ func (c *Client) Close() error {
 
 // Call makes a jsonrpc call
 func (c *Client) Call(method string, out interface{}, params ...interface{}) error {
-	return c.transport.Call(method, out, params...)
+	return c.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext makes a jsonrpc call that is aborted if the context is done
+func (c *Client) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	if c.invoke == nil {
+		return transport.CallContext(ctx, c.transport, method, out, params...)
+	}
+	req := &Request{
+		Method: method,
+		Params: params,
+	}
+	if err := c.invoke(ctx, req); err != nil {
+		return err
+	}
+	return json.Unmarshal(req.Response, out)
+}
+
+// send sends the request with the transport once it went through all the interceptors
+func (c *Client) send(ctx context.Context, req *Request) error {
+	now := time.Now()
+
+	var err error
+	if req.Batch != nil {
+		err = c.batchCall(ctx, req.Batch)
+	} else {
+		err = transport.CallContext(ctx, c.transport, req.Method, &req.Response, req.Params...)
+	}
+	req.Duration = time.Since(now)
+	return err
 }
 
 // SetMaxConnsLimit sets the maximum number of connections that can be established with a host
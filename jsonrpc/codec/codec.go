@@ -41,3 +41,20 @@ func (e *ErrorObject) Error() string {
 	}
 	return string(data)
 }
+
+// NewRequest creates a jsonrpc request with the encoded params
+func NewRequest(id uint64, method string, params []interface{}) (*Request, error) {
+	request := &Request{
+		JsonRPC: "2.0",
+		ID:      id,
+		Method:  method,
+	}
+	if len(params) > 0 {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		request.Params = data
+	}
+	return request, nil
+}
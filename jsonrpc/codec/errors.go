@@ -0,0 +1,291 @@
+package codec
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when the requested block, transaction or receipt does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrNonceTooLow is returned when the nonce of the transaction was already used
+	ErrNonceTooLow = errors.New("nonce too low")
+
+	// ErrInsufficientFunds is returned when the sender cannot pay for the gas and value of the transaction
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// ErrReplacementUnderpriced is returned when a pending transaction is replaced without enough fee bump
+	ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
+)
+
+const (
+	// codeParseError, codeInvalidRequest and codeMethodNotFound are the
+	// jsonrpc codes of a malformed or unsupported request
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+
+	// codeExecutionReverted is the code used by geth and erigon for reverts
+	codeExecutionReverted = 3
+
+	// codeVMError is the code used by nethermind for reverts
+	codeVMError = -32015
+)
+
+var (
+	// errorSelector is the selector of Error(string)
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+	// panicSelector is the selector of Panic(uint256)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// RevertError is returned when the execution of a call or a
+// transaction reverts. Data is the return data of the execution.
+type RevertError struct {
+	Data []byte
+
+	// Reason is the decoded revert reason if the data is an
+	// Error(string) or a Panic(uint256) (i.e. a failed require)
+	Reason string
+}
+
+// Error implements the error interface
+func (e *RevertError) Error() string {
+	if e.Reason != "" {
+		return "execution reverted: " + e.Reason
+	}
+	return "execution reverted"
+}
+
+// RangeLimitError is returned when the provider rejects a query (i.e. eth_getLogs)
+// because the block range is too big or it returns too many results.
+type RangeLimitError struct {
+	Message string
+
+	// From and To are the block range suggested by the provider (if any)
+	From, To *uint64
+}
+
+// Error implements the error interface
+func (e *RangeLimitError) Error() string {
+	return "range limit exceeded: " + e.Message
+}
+
+// ErrorCode returns the jsonrpc error code
+func (e *ErrorObject) ErrorCode() int {
+	return e.Code
+}
+
+// ErrorData returns the data of the error
+func (e *ErrorObject) ErrorData() interface{} {
+	return e.Data
+}
+
+// Unwrap returns the typed error of the jsonrpc error (if any) so that
+// it can be checked with errors.Is or errors.As:
+//
+//	var revert *codec.RevertError
+//	if errors.As(err, &revert) { ... }
+//	if errors.Is(err, codec.ErrNonceTooLow) { ... }
+func (e *ErrorObject) Unwrap() error {
+	return classifyError(e)
+}
+
+var (
+	notFoundMessages = []string{
+		"header not found",
+		"block not found",
+		"transaction not found",
+		"receipt not found",
+		"unknown block",
+		"unknown transaction",
+	}
+	nonceTooLowMessages = []string{
+		"nonce too low",
+		"oldnonce",      // nethermind
+		"nonce_too_low", // besu
+		"nonce is too low",
+		"already been used", // nonce has already been used
+	}
+	insufficientFundsMessages = []string{
+		"insufficient funds",
+		"insufficientfunds",            // nethermind
+		"upfront cost exceeds",         // besu
+		"upfront_cost_exceeds_balance", // besu
+	}
+	replacementUnderpricedMessages = []string{
+		"replacement transaction underpriced",
+		"replacement_underpriced", // besu
+		"replacementnotallowed",   // nethermind
+		"replacement fee too low",
+	}
+	revertMessages = []string{
+		"execution reverted",
+		"vm execution error", // nethermind
+	}
+	// rangeLimitMessages only match the errors of queries with too many results
+	// or blocks (i.e. not "block range extends beyond current head block")
+	rangeLimitMessages = []string{
+		"query returned more than",   // geth and infura
+		"response size exceeded",     // alchemy
+		"exceed maximum block range", // alchemy and erigon
+		"exceeds maximum range",
+		"block range is too wide",
+		"block range too large",
+		"block range is too large",
+		"range limit exceeded",
+		"too many blocks",
+	}
+)
+
+func containsAny(msg string, substrs []string) bool {
+	for _, str := range substrs {
+		if strings.Contains(msg, str) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyError returns the typed error of the jsonrpc error using the codes
+// and then the messages of geth, erigon, nethermind and besu
+func classifyError(e *ErrorObject) error {
+	switch e.Code {
+	case codeParseError, codeInvalidRequest, codeMethodNotFound:
+		// the request is wrong, not the execution
+		return nil
+	case codeExecutionReverted, codeVMError:
+		return newRevertError(e)
+	}
+	msg := strings.ToLower(e.Message)
+
+	switch {
+	case containsAny(msg, revertMessages):
+		return newRevertError(e)
+	case containsAny(msg, rangeLimitMessages):
+		return newRangeLimitError(e.Message)
+	case containsAny(msg, nonceTooLowMessages):
+		return ErrNonceTooLow
+	case containsAny(msg, insufficientFundsMessages):
+		return ErrInsufficientFunds
+	case containsAny(msg, replacementUnderpricedMessages):
+		return ErrReplacementUnderpriced
+	case containsAny(msg, notFoundMessages):
+		return ErrNotFound
+	}
+	return nil
+}
+
+var hexDataRegexp = regexp.MustCompile(`0x[0-9a-fA-F]*`)
+
+// revertData returns the return data of the revert. Nodes set it either as
+// a hex string, a message with the hex string (i.e. "Reverted 0x...") or
+// nested in an object.
+func revertData(data interface{}) []byte {
+	switch obj := data.(type) {
+	case string:
+		match := hexDataRegexp.FindString(obj)
+		if match == "" || len(match)%2 != 0 {
+			return nil
+		}
+		buf, err := hex.DecodeString(match[2:])
+		if err != nil {
+			return nil
+		}
+		return buf
+	case map[string]interface{}:
+		for _, key := range []string{"data", "result", "originalError"} {
+			if val, ok := obj[key]; ok {
+				if buf := revertData(val); buf != nil {
+					return buf
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// NewRevertError creates a revert error with the return data of the
+// execution and decodes its reason if it is available
+func NewRevertError(data []byte) *RevertError {
+	revert := &RevertError{
+		Data: data,
+	}
+	if reason, ok := decodeRevertReason(data); ok {
+		revert.Reason = reason
+	}
+	return revert
+}
+
+func newRevertError(e *ErrorObject) *RevertError {
+	revert := NewRevertError(revertData(e.Data))
+	if revert.Reason != "" {
+		return revert
+	}
+	if i := strings.Index(e.Message, ":"); i != -1 {
+		// the reason is only in the message (i.e. "execution reverted: reason")
+		revert.Reason = strings.TrimSpace(e.Message[i+1:])
+	}
+	return revert
+}
+
+// decodeRevertReason decodes the return data of an Error(string) or Panic(uint256) revert
+func decodeRevertReason(data []byte) (string, bool) {
+	if len(data) < 4 {
+		return "", false
+	}
+	selector, data := data[:4], data[4:]
+
+	switch string(selector) {
+	case string(errorSelector):
+		if len(data) < 64 {
+			return "", false
+		}
+		// the bounds are compared without additions since the
+		// offset and the size can be any uint64
+		offset := new(big.Int).SetBytes(data[:32])
+		if !offset.IsUint64() || offset.Uint64() > uint64(len(data))-32 {
+			return "", false
+		}
+		start := offset.Uint64()
+		size := new(big.Int).SetBytes(data[start : start+32])
+		if !size.IsUint64() || size.Uint64() > uint64(len(data))-start-32 {
+			return "", false
+		}
+		return string(data[start+32 : start+32+size.Uint64()]), true
+
+	case string(panicSelector):
+		if len(data) < 32 {
+			return "", false
+		}
+		code := binary.BigEndian.Uint64(data[24:32])
+		return fmt.Sprintf("panic code 0x%x", code), true
+	}
+	return "", false
+}
+
+// suggestedRangeRegexp matches the block range suggested by some providers
+// (i.e. "this block range should work: [0x1, 0x2]")
+var suggestedRangeRegexp = regexp.MustCompile(`\[(0x[0-9a-fA-F]+|\d+),\s*(0x[0-9a-fA-F]+|\d+)\]`)
+
+func newRangeLimitError(msg string) *RangeLimitError {
+	err := &RangeLimitError{
+		Message: msg,
+	}
+	if match := suggestedRangeRegexp.FindStringSubmatch(msg); match != nil {
+		from, err1 := strconv.ParseUint(match[1], 0, 64)
+		to, err2 := strconv.ParseUint(match[2], 0, 64)
+		if err1 == nil && err2 == nil {
+			err.From, err.To = &from, &to
+		}
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package codec
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const revertReasonData = "0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000126e6f7420656e6f7567682062616c616e63650000000000000000000000000000"
+
+func TestErrorObject_Sentinels(t *testing.T) {
+	cases := []struct {
+		code int
+		msg  string
+		err  error
+	}{
+		// geth and erigon
+		{-32000, "nonce too low", ErrNonceTooLow},
+		{-32000, "insufficient funds for gas * price + value", ErrInsufficientFunds},
+		{-32000, "replacement transaction underpriced", ErrReplacementUnderpriced},
+		{-32000, "header not found", ErrNotFound},
+		// nethermind
+		{-32010, "OldNonce", ErrNonceTooLow},
+		{-32010, "InsufficientFunds, Balance is zero, cannot pay gas", ErrInsufficientFunds},
+		{-32010, "ReplacementNotAllowed", ErrReplacementUnderpriced},
+		{-32001, "Block not found", ErrNotFound},
+		// besu
+		{-32001, "Nonce too low", ErrNonceTooLow},
+		{-32004, "Upfront cost exceeds account balance", ErrInsufficientFunds},
+		{-32000, "REPLACEMENT_UNDERPRICED", ErrReplacementUnderpriced},
+		{-32000, "Unknown block", ErrNotFound},
+		// not classified
+		{-32601, "the method eth_foo does not exist/is not available", nil},
+		{-32601, "method not found", nil},
+		{-32000, "method not found", nil},
+		{-32000, "filter not found", nil},
+		{-32600, "invalid request: unknown block", nil},
+		{-32000, "snapshot reverted", nil},
+		{-32000, "failed", nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.msg, func(t *testing.T) {
+			var err error = &ErrorObject{Code: c.code, Message: c.msg}
+			err = fmt.Errorf("wrapped: %w", err)
+
+			if c.err == nil {
+				assert.Nil(t, errors.Unwrap(errors.Unwrap(err)))
+			} else {
+				assert.True(t, errors.Is(err, c.err))
+			}
+
+			var obj *ErrorObject
+			assert.True(t, errors.As(err, &obj))
+			assert.Equal(t, c.code, obj.ErrorCode())
+		})
+	}
+}
+
+func TestErrorObject_Revert(t *testing.T) {
+	cases := []struct {
+		name   string
+		obj    string
+		data   string
+		reason string
+	}{
+		{
+			"geth",
+			`{"code":3,"message":"execution reverted: not enough balance","data":"` + revertReasonData + `"}`,
+			revertReasonData,
+			"not enough balance",
+		},
+		{
+			"geth custom error",
+			`{"code":3,"message":"execution reverted","data":"0x12345678"}`,
+			"0x12345678",
+			"",
+		},
+		{
+			"nethermind",
+			`{"code":-32015,"message":"VM execution error.","data":"Reverted ` + revertReasonData + `"}`,
+			revertReasonData,
+			"not enough balance",
+		},
+		{
+			"besu",
+			`{"code":-32000,"message":"Execution reverted","data":"` + revertReasonData + `"}`,
+			revertReasonData,
+			"not enough balance",
+		},
+		{
+			"nested data",
+			`{"code":-32000,"message":"execution reverted","data":{"originalError":{"data":"0x12345678"}}}`,
+			"0x12345678",
+			"",
+		},
+		{
+			"panic",
+			`{"code":3,"message":"execution reverted","data":"0x4e487b710000000000000000000000000000000000000000000000000000000000000011"}`,
+			"0x4e487b710000000000000000000000000000000000000000000000000000000000000011",
+			"panic code 0x11",
+		},
+		{
+			"reason in message",
+			`{"code":-32000,"message":"execution reverted: Ownable: caller is not the owner"}`,
+			"",
+			"Ownable: caller is not the owner",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			obj := &ErrorObject{}
+			assert.NoError(t, json.Unmarshal([]byte(c.obj), obj))
+
+			var revert *RevertError
+			assert.True(t, errors.As(obj, &revert))
+
+			if c.data == "" {
+				assert.Nil(t, revert.Data)
+			} else {
+				assert.Equal(t, c.data, fmt.Sprintf("0x%x", revert.Data))
+			}
+			assert.Equal(t, c.reason, revert.Reason)
+		})
+	}
+}
+
+func TestNewRevertError_Malformed(t *testing.T) {
+	word := func(str string) string {
+		return fmt.Sprintf("%064s", str)
+	}
+
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"short", "08c379a0" + word("20")},
+		{"offset out of bounds", "08c379a0" + word("40") + word("0")},
+		{"offset overflow", "08c379a0" + word("ffffffffffffffe0") + word("0")},
+		{"size out of bounds", "08c379a0" + word("20") + word("21") + word("0")},
+		{"size overflow", "08c379a0" + word("20") + word("ffffffffffffffff") + word("0")},
+		{"size not uint64", "08c379a0" + word("20") + word("10000000000000000") + word("0")},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := hex.DecodeString(c.data)
+			assert.NoError(t, err)
+
+			revert := NewRevertError(data)
+			assert.Equal(t, data, revert.Data)
+			assert.Equal(t, "", revert.Reason)
+		})
+	}
+}
+
+func TestErrorObject_RangeLimit(t *testing.T) {
+	var err error = &ErrorObject{Code: -32005, Message: "query returned more than 10000 results"}
+
+	var rangeErr *RangeLimitError
+	assert.True(t, errors.As(err, &rangeErr))
+	assert.Nil(t, rangeErr.From)
+
+	err = &ErrorObject{Code: -32602, Message: "Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range and no limit on the response size, or you can request any block range with a cap of 10K logs in the response. Based on your parameters and the response size limit, this block range should work: [0x10, 0x20]"}
+	assert.True(t, errors.As(err, &rangeErr))
+	assert.Equal(t, uint64(0x10), *rangeErr.From)
+	assert.Equal(t, uint64(0x20), *rangeErr.To)
+
+	err = &ErrorObject{Code: -32000, Message: "exceed maximum block range: 5000"}
+	assert.True(t, errors.As(err, &rangeErr))
+
+	// other errors about the block range are not range limits
+	err = &ErrorObject{Code: -32000, Message: "block range extends beyond current head block"}
+	assert.False(t, errors.As(err, &rangeErr))
+
+	err = &ErrorObject{Code: -32000, Message: "invalid block range params"}
+	assert.False(t, errors.As(err, &rangeErr))
+}
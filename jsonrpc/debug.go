@@ -1,9 +1,18 @@
 package jsonrpc
 
-import "github.com/umbracle/ethgo"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
 
 type Debug struct {
-	c *Client
+	c   *Client
+	ctx context.Context
 }
 
 // Eth returns the reference to the eth namespace
@@ -11,6 +20,130 @@ func (c *Client) Debug() *Debug {
 	return c.endpoints.d
 }
 
+// WithContext returns a copy of the debug namespace whose requests
+// are aborted when the context is done
+func (d *Debug) WithContext(ctx context.Context) *Debug {
+	return &Debug{c: d.c, ctx: ctx}
+}
+
+// Built-in tracers of geth
+const (
+	CallTracer     = "callTracer"
+	PrestateTracer = "prestateTracer"
+	FourByteTracer = "4byteTracer"
+)
+
+// StructLoggerConfig is the configuration of the default tracer (struct logs)
+type StructLoggerConfig struct {
+	EnableMemory     bool `json:"enableMemory,omitempty"`
+	DisableStack     bool `json:"disableStack,omitempty"`
+	DisableStorage   bool `json:"disableStorage,omitempty"`
+	EnableReturnData bool `json:"enableReturnData,omitempty"`
+}
+
+// CallTracerConfig is the configuration of the callTracer
+type CallTracerConfig struct {
+	// OnlyTopCall does not trace the inner calls
+	OnlyTopCall bool `json:"onlyTopCall,omitempty"`
+
+	// WithLog includes the logs emitted by each call
+	WithLog bool `json:"withLog,omitempty"`
+}
+
+// PrestateTracerConfig is the configuration of the prestateTracer
+type PrestateTracerConfig struct {
+	// DiffMode returns the state before and after the execution
+	// of the accounts modified
+	DiffMode       bool `json:"diffMode,omitempty"`
+	DisableCode    bool `json:"disableCode,omitempty"`
+	DisableStorage bool `json:"disableStorage,omitempty"`
+}
+
+// TraceConfig is the configuration of the debug_trace* requests
+type TraceConfig struct {
+	StructLoggerConfig
+	Tracer       string      `json:"tracer,omitempty"`
+	TracerConfig interface{} `json:"tracerConfig,omitempty"`
+	Timeout      string      `json:"timeout,omitempty"`
+
+	// StateOverrides and BlockOverrides are only used by TraceCall
+	StateOverrides StateOverride   `json:"stateOverrides,omitempty"`
+	BlockOverrides *BlockOverrides `json:"blockOverrides,omitempty"`
+}
+
+type TraceOption func(*TraceConfig)
+
+// WithStructLogger traces the execution with the default tracer that
+// returns the opcodes executed (see TraceResult.StructLogs)
+func WithStructLogger(config *StructLoggerConfig) TraceOption {
+	return func(c *TraceConfig) {
+		c.Tracer, c.TracerConfig = "", nil
+		if config != nil {
+			c.StructLoggerConfig = *config
+		}
+	}
+}
+
+// WithCallTracer traces the execution with the callTracer that returns
+// the tree of calls (see TraceResult.CallFrame)
+func WithCallTracer(config *CallTracerConfig) TraceOption {
+	return withTracer(CallTracer, config)
+}
+
+// WithPrestateTracer traces the execution with the prestateTracer that returns the
+// accounts touched (see TraceResult.Prestate and TraceResult.PrestateDiff in diff mode)
+func WithPrestateTracer(config *PrestateTracerConfig) TraceOption {
+	return withTracer(PrestateTracer, config)
+}
+
+// With4ByteTracer traces the execution with the 4byteTracer that returns the
+// selectors and the size of the inputs of the calls (see TraceResult.FourByte)
+func With4ByteTracer() TraceOption {
+	return withTracer(FourByteTracer, nil)
+}
+
+// WithJSTracer traces the execution with the javascript code of a tracer.
+// The result can be decoded with TraceResult.Decode.
+func WithJSTracer(code string, config interface{}) TraceOption {
+	return withTracer(code, config)
+}
+
+func withTracer(tracer string, config interface{}) TraceOption {
+	return func(c *TraceConfig) {
+		c.Tracer = tracer
+		c.TracerConfig = config
+	}
+}
+
+// WithTraceTimeout sets the timeout of the tracer in the node
+func WithTraceTimeout(timeout time.Duration) TraceOption {
+	return func(c *TraceConfig) {
+		c.Timeout = timeout.String()
+	}
+}
+
+// WithTraceStateOverrides replaces the state of some accounts during the traced call
+func WithTraceStateOverrides(overrides StateOverride) TraceOption {
+	return func(c *TraceConfig) {
+		c.StateOverrides = overrides
+	}
+}
+
+// WithTraceBlockOverrides replaces the fields of the block of the traced call
+func WithTraceBlockOverrides(overrides *BlockOverrides) TraceOption {
+	return func(c *TraceConfig) {
+		c.BlockOverrides = overrides
+	}
+}
+
+func newTraceConfig(opts []TraceOption) *TraceConfig {
+	config := &TraceConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
 type TransactionTrace struct {
 	Gas         uint64
 	ReturnValue string
@@ -28,8 +161,165 @@ type StructLogs struct {
 	Storage map[string]string
 }
 
-func (d *Debug) TraceTransaction(hash ethgo.Hash) (*TransactionTrace, error) {
+// CallFrame is a call in the result of the callTracer
+type CallFrame struct {
+	Type         string          `json:"type"`
+	From         ethgo.Address   `json:"from"`
+	To           *ethgo.Address  `json:"to,omitempty"`
+	Value        *ethgo.ArgBig   `json:"value,omitempty"`
+	Gas          ethgo.ArgUint64 `json:"gas"`
+	GasUsed      ethgo.ArgUint64 `json:"gasUsed"`
+	Input        ethgo.ArgBytes  `json:"input"`
+	Output       ethgo.ArgBytes  `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []*CallFrame    `json:"calls,omitempty"`
+	Logs         []*CallLog      `json:"logs,omitempty"`
+}
+
+// Revert returns the revert error of the call with the decoded reason
+// or nil if the call did not revert
+func (c *CallFrame) Revert() *RevertError {
+	if c.Error != "execution reverted" {
+		return nil
+	}
+	revert := codec.NewRevertError(c.Output)
+	if revert.Reason == "" {
+		revert.Reason = c.RevertReason
+	}
+	return revert
+}
+
+// CallLog is a log emitted by a call in the result of the callTracer
+type CallLog struct {
+	Address  ethgo.Address   `json:"address"`
+	Topics   []ethgo.Hash    `json:"topics"`
+	Data     ethgo.ArgBytes  `json:"data"`
+	Position ethgo.ArgUint64 `json:"position"`
+}
+
+// PrestateAccount is the state of an account in the result of the prestateTracer
+type PrestateAccount struct {
+	Balance *ethgo.ArgBig             `json:"balance,omitempty"`
+	Nonce   uint64                    `json:"nonce,omitempty"`
+	Code    ethgo.ArgBytes            `json:"code,omitempty"`
+	Storage map[ethgo.Hash]ethgo.Hash `json:"storage,omitempty"`
+}
+
+// PrestateDiff is the result of the prestateTracer in diff mode. Pre has the
+// state of the modified accounts before the execution and Post only has the
+// fields modified by the execution.
+type PrestateDiff struct {
+	Pre  map[ethgo.Address]*PrestateAccount `json:"pre"`
+	Post map[ethgo.Address]*PrestateAccount `json:"post"`
+}
+
+// TraceResult is the result of a trace. Its format depends on the tracer.
+type TraceResult struct {
+	raw json.RawMessage
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (t *TraceResult) UnmarshalJSON(data []byte) error {
+	t.raw = append(t.raw[:0], data...)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (t *TraceResult) MarshalJSON() ([]byte, error) {
+	if t.raw == nil {
+		return []byte("null"), nil
+	}
+	return t.raw, nil
+}
+
+// Raw returns the json encoded result
+func (t *TraceResult) Raw() json.RawMessage {
+	return t.raw
+}
+
+// Decode decodes the result (i.e. of a javascript tracer)
+func (t *TraceResult) Decode(out interface{}) error {
+	if err := json.Unmarshal(t.raw, out); err != nil {
+		return fmt.Errorf("failed to decode trace: %v", err)
+	}
+	return nil
+}
+
+// StructLogs returns the result of the default tracer
+func (t *TraceResult) StructLogs() (*TransactionTrace, error) {
+	var res *TransactionTrace
+	err := t.Decode(&res)
+	return res, err
+}
+
+// CallFrame returns the result of the callTracer
+func (t *TraceResult) CallFrame() (*CallFrame, error) {
+	var res *CallFrame
+	err := t.Decode(&res)
+	return res, err
+}
+
+// Prestate returns the result of the prestateTracer
+func (t *TraceResult) Prestate() (map[ethgo.Address]*PrestateAccount, error) {
+	var res map[ethgo.Address]*PrestateAccount
+	err := t.Decode(&res)
+	return res, err
+}
+
+// PrestateDiff returns the result of the prestateTracer in diff mode
+func (t *TraceResult) PrestateDiff() (*PrestateDiff, error) {
+	var res *PrestateDiff
+	err := t.Decode(&res)
+	return res, err
+}
+
+// FourByte returns the result of the 4byteTracer. The keys are the selector
+// and the size of the input (i.e. 0x27dc297e-128) and the values the number of calls.
+func (t *TraceResult) FourByte() (map[string]uint64, error) {
+	var res map[string]uint64
+	err := t.Decode(&res)
+	return res, err
+}
+
+// BlockTrace is the trace of a transaction of a block
+type BlockTrace struct {
+	TxHash ethgo.Hash   `json:"txHash"`
+	Result *TraceResult `json:"result"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// TraceTransaction returns the opcodes executed by the transaction
+func (d *Debug) TraceTransaction(hash ethgo.Hash, opts ...TraceOption) (*TransactionTrace, error) {
 	var res *TransactionTrace
-	err := d.c.Call("debug_traceTransaction", &res, hash)
+	err := d.c.CallContext(d.ctx, "debug_traceTransaction", &res, hash, newTraceConfig(opts))
+	return res, err
+}
+
+// TraceTransactionResult traces the transaction with the tracer of the options
+func (d *Debug) TraceTransactionResult(hash ethgo.Hash, opts ...TraceOption) (*TraceResult, error) {
+	var res *TraceResult
+	err := d.c.CallContext(d.ctx, "debug_traceTransaction", &res, hash, newTraceConfig(opts))
+	return res, err
+}
+
+// TraceCall traces the execution of a call on top of the state of the block
+func (d *Debug) TraceCall(msg *ethgo.CallMsg, block ethgo.BlockNumberOrHash, opts ...TraceOption) (*TraceResult, error) {
+	var res *TraceResult
+	err := d.c.CallContext(d.ctx, "debug_traceCall", &res, msg, blockParam(block), newTraceConfig(opts))
+	return res, err
+}
+
+// TraceBlockByNumber traces all the transactions of the block
+func (d *Debug) TraceBlockByNumber(block ethgo.BlockNumber, opts ...TraceOption) ([]*BlockTrace, error) {
+	var res []*BlockTrace
+	err := d.c.CallContext(d.ctx, "debug_traceBlockByNumber", &res, block.String(), newTraceConfig(opts))
+	return res, err
+}
+
+// TraceBlockByHash traces all the transactions of the block
+func (d *Debug) TraceBlockByHash(hash ethgo.Hash, opts ...TraceOption) ([]*BlockTrace, error) {
+	var res []*BlockTrace
+	err := d.c.CallContext(d.ctx, "debug_traceBlockByHash", &res, hash, newTraceConfig(opts))
 	return res, err
 }
@@ -1,9 +1,16 @@
 package jsonrpc
 
 import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
 	"github.com/umbracle/ethgo/testutil"
 )
 
@@ -25,3 +32,128 @@ func TestDebug_TraceTransaction(t *testing.T) {
 	assert.Greater(t, trace.Gas, uint64(20000))
 	assert.NotEmpty(t, trace.StructLogs)
 }
+
+// newStubServer returns a server that replies with the results of each method
+// and records the params of the requests
+func newStubServer(t *testing.T, results map[string]string) (*httptest.Server, map[string]json.RawMessage) {
+	params := map[string]json.RawMessage{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req codec.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		params[req.Method] = req.Params
+
+		resp := &codec.Response{ID: req.ID}
+		if result, ok := results[req.Method]; ok {
+			resp.Result = json.RawMessage(result)
+		} else {
+			resp.Error = &codec.ErrorObject{Code: -32601, Message: "method not found"}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	return srv, params
+}
+
+func TestDebug_TraceCallTracer(t *testing.T) {
+	srv, params := newStubServer(t, map[string]string{
+		"debug_traceCall": `{
+			"type": "CALL",
+			"from": "0x0100000000000000000000000000000000000000",
+			"to": "0x0200000000000000000000000000000000000000",
+			"value": "0x0",
+			"gas": "0x5208",
+			"gasUsed": "0x100",
+			"input": "0x01",
+			"error": "execution reverted",
+			"calls": [{
+				"type": "STATICCALL",
+				"from": "0x0200000000000000000000000000000000000000",
+				"to": "0x0300000000000000000000000000000000000000",
+				"gas": "0x10",
+				"gasUsed": "0x10",
+				"input": "0x",
+				"output": "0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000a6e6f7420656e6f75676800000000000000000000000000000000000000000000",
+				"error": "execution reverted",
+				"logs": [{"address": "0x0300000000000000000000000000000000000000", "topics": [], "data": "0x", "position": "0x0"}]
+			}]
+		}`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	code := ethgo.ArgBytes{0x1}
+	res, err := c.Debug().TraceCall(&ethgo.CallMsg{To: &addr1}, ethgo.Latest,
+		WithCallTracer(&CallTracerConfig{WithLog: true}),
+		WithTraceTimeout(10*time.Second),
+		WithTraceStateOverrides(StateOverride{addr1: {Code: &code}}),
+		WithTraceBlockOverrides(&BlockOverrides{Time: new(ethgo.ArgUint64)}),
+	)
+	assert.NoError(t, err)
+
+	var raw []json.RawMessage
+	assert.NoError(t, json.Unmarshal(params["debug_traceCall"], &raw))
+	assert.Len(t, raw, 3)
+	assert.Equal(t, `"latest"`, string(raw[1]))
+	assert.JSONEq(t, `{
+		"tracer": "callTracer",
+		"tracerConfig": {"withLog": true},
+		"timeout": "10s",
+		"stateOverrides": {"0x0200000000000000000000000000000000000000": {"code": "0x01"}},
+		"blockOverrides": {"time": "0x0"}
+	}`, string(raw[2]))
+
+	frame, err := res.CallFrame()
+	assert.NoError(t, err)
+	assert.Equal(t, "CALL", frame.Type)
+	assert.Equal(t, uint64(0x5208), frame.Gas.Uint64())
+	assert.NotNil(t, frame.Revert())
+	assert.Len(t, frame.Calls, 1)
+
+	inner := frame.Calls[0]
+	assert.Nil(t, inner.Value)
+	assert.Len(t, inner.Logs, 1)
+	assert.Equal(t, "not enough", inner.Revert().Reason)
+}
+
+func TestDebug_TraceBlock(t *testing.T) {
+	srv, params := newStubServer(t, map[string]string{
+		"debug_traceBlockByNumber": `[
+			{"txHash": "0x0000000000000000000000000000000000000000000000000000000000000001", "result": {"0x27dc297e-128": 1}},
+			{"txHash": "0x0000000000000000000000000000000000000000000000000000000000000002", "error": "execution timeout"}
+		]`,
+		"debug_traceBlockByHash": `[{"txHash": "0x0000000000000000000000000000000000000000000000000000000000000001", "result": {
+			"pre": {"0x0100000000000000000000000000000000000000": {"balance": "0x10", "nonce": 1, "storage": {"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"}}},
+			"post": {"0x0100000000000000000000000000000000000000": {"balance": "0x5"}}
+		}}]`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	traces, err := c.Debug().TraceBlockByNumber(ethgo.BlockNumber(10), With4ByteTracer())
+	assert.NoError(t, err)
+	assert.Equal(t, `["0xa",{"tracer":"4byteTracer"}]`, string(params["debug_traceBlockByNumber"]))
+	assert.Len(t, traces, 2)
+
+	selectors, err := traces[0].Result.FourByte()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"0x27dc297e-128": 1}, selectors)
+	assert.Nil(t, traces[1].Result)
+	assert.Equal(t, "execution timeout", traces[1].Error)
+
+	traces, err = c.Debug().TraceBlockByHash(ethgo.Hash{0x1}, WithPrestateTracer(&PrestateTracerConfig{DiffMode: true}))
+	assert.NoError(t, err)
+
+	diff, err := traces[0].Result.PrestateDiff()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), diff.Pre[addr0].Nonce)
+	assert.Equal(t, "16", (*big.Int)(diff.Pre[addr0].Balance).String())
+	assert.Equal(t, ethgo.HexToHash("0x2"), diff.Pre[addr0].Storage[ethgo.HexToHash("0x1")])
+	assert.Equal(t, "5", (*big.Int)(diff.Post[addr0].Balance).String())
+
+	// javascript tracers return arbitrary results
+	var out map[string]interface{}
+	assert.NoError(t, traces[0].Result.Decode(&out))
+	assert.Contains(t, out, "pre")
+}
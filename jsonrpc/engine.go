@@ -0,0 +1,281 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/umbracle/ethgo"
+)
+
+// Engine is the engine namespace used by the consensus clients to drive
+// an execution client. The engine api requires a client created with a
+// transport.JWTAuth (see WithAuth).
+type Engine struct {
+	c   *Client
+	ctx context.Context
+}
+
+// Engine returns the reference to the engine namespace
+func (c *Client) Engine() *Engine {
+	return c.endpoints.engine
+}
+
+// WithContext returns a copy of the engine namespace whose requests
+// are aborted when the context is done
+func (e *Engine) WithContext(ctx context.Context) *Engine {
+	return &Engine{c: e.c, ctx: ctx}
+}
+
+// Withdrawal is a validator withdrawal of the consensus layer
+type Withdrawal struct {
+	Index          ethgo.ArgUint64 `json:"index"`
+	ValidatorIndex ethgo.ArgUint64 `json:"validatorIndex"`
+	Address        ethgo.Address   `json:"address"`
+	Amount         ethgo.ArgUint64 `json:"amount"`
+}
+
+// ExecutionPayloadV1 is the execution payload of the Paris fork
+type ExecutionPayloadV1 struct {
+	ParentHash    ethgo.Hash       `json:"parentHash"`
+	FeeRecipient  ethgo.Address    `json:"feeRecipient"`
+	StateRoot     ethgo.Hash       `json:"stateRoot"`
+	ReceiptsRoot  ethgo.Hash       `json:"receiptsRoot"`
+	LogsBloom     ethgo.ArgBytes   `json:"logsBloom"`
+	PrevRandao    ethgo.Hash       `json:"prevRandao"`
+	BlockNumber   ethgo.ArgUint64  `json:"blockNumber"`
+	GasLimit      ethgo.ArgUint64  `json:"gasLimit"`
+	GasUsed       ethgo.ArgUint64  `json:"gasUsed"`
+	Timestamp     ethgo.ArgUint64  `json:"timestamp"`
+	ExtraData     ethgo.ArgBytes   `json:"extraData"`
+	BaseFeePerGas *ethgo.ArgBig    `json:"baseFeePerGas"`
+	BlockHash     ethgo.Hash       `json:"blockHash"`
+	Transactions  []ethgo.ArgBytes `json:"transactions"`
+}
+
+// ExecutionPayloadV2 is the execution payload of the Shanghai fork
+type ExecutionPayloadV2 struct {
+	ExecutionPayloadV1
+	Withdrawals []*Withdrawal `json:"withdrawals"`
+}
+
+// ExecutionPayloadV3 is the execution payload of the Cancun and Prague forks
+type ExecutionPayloadV3 struct {
+	ExecutionPayloadV2
+	BlobGasUsed   ethgo.ArgUint64 `json:"blobGasUsed"`
+	ExcessBlobGas ethgo.ArgUint64 `json:"excessBlobGas"`
+}
+
+const (
+	PayloadStatusValid            = "VALID"
+	PayloadStatusInvalid          = "INVALID"
+	PayloadStatusSyncing          = "SYNCING"
+	PayloadStatusAccepted         = "ACCEPTED"
+	PayloadStatusInvalidBlockHash = "INVALID_BLOCK_HASH"
+)
+
+// PayloadStatus is the result of the validation of a payload
+type PayloadStatus struct {
+	Status          string      `json:"status"`
+	LatestValidHash *ethgo.Hash `json:"latestValidHash"`
+	ValidationError *string     `json:"validationError"`
+}
+
+// ForkchoiceState is the head, safe and finalized blocks of the consensus client
+type ForkchoiceState struct {
+	HeadBlockHash      ethgo.Hash `json:"headBlockHash"`
+	SafeBlockHash      ethgo.Hash `json:"safeBlockHash"`
+	FinalizedBlockHash ethgo.Hash `json:"finalizedBlockHash"`
+}
+
+// PayloadAttributesV1 are the attributes to build a payload in the Paris fork
+type PayloadAttributesV1 struct {
+	Timestamp             ethgo.ArgUint64 `json:"timestamp"`
+	PrevRandao            ethgo.Hash      `json:"prevRandao"`
+	SuggestedFeeRecipient ethgo.Address   `json:"suggestedFeeRecipient"`
+}
+
+// PayloadAttributesV2 are the attributes to build a payload in the Shanghai fork
+type PayloadAttributesV2 struct {
+	PayloadAttributesV1
+	Withdrawals []*Withdrawal `json:"withdrawals"`
+}
+
+// PayloadAttributesV3 are the attributes to build a payload in the Cancun and Prague forks
+type PayloadAttributesV3 struct {
+	PayloadAttributesV2
+	ParentBeaconBlockRoot ethgo.Hash `json:"parentBeaconBlockRoot"`
+}
+
+// PayloadID is the identifier of a payload being built
+type PayloadID [8]byte
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (p PayloadID) MarshalText() ([]byte, error) {
+	return []byte("0x" + hex.EncodeToString(p[:])), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (p *PayloadID) UnmarshalText(b []byte) error {
+	buf, err := hex.DecodeString(strings.TrimPrefix(string(b), "0x"))
+	if err != nil {
+		return err
+	}
+	if len(buf) != len(p) {
+		return fmt.Errorf("payload id must be %d bytes but found %d", len(p), len(buf))
+	}
+	copy(p[:], buf)
+	return nil
+}
+
+// String returns the hex encoding of the payload id
+func (p PayloadID) String() string {
+	return "0x" + hex.EncodeToString(p[:])
+}
+
+// ForkchoiceUpdatedResult is the result of a forkchoice update
+type ForkchoiceUpdatedResult struct {
+	PayloadStatus PayloadStatus `json:"payloadStatus"`
+	PayloadID     *PayloadID    `json:"payloadId"`
+}
+
+// BlobsBundleV1 are the blobs of the transactions of a payload
+type BlobsBundleV1 struct {
+	Commitments []ethgo.ArgBytes `json:"commitments"`
+	Proofs      []ethgo.ArgBytes `json:"proofs"`
+	Blobs       []ethgo.ArgBytes `json:"blobs"`
+}
+
+// GetPayloadV2Response is the response of engine_getPayloadV2
+type GetPayloadV2Response struct {
+	ExecutionPayload *ExecutionPayloadV2 `json:"executionPayload"`
+	BlockValue       *ethgo.ArgBig       `json:"blockValue"`
+}
+
+// GetPayloadV3Response is the response of engine_getPayloadV3
+type GetPayloadV3Response struct {
+	ExecutionPayload      *ExecutionPayloadV3 `json:"executionPayload"`
+	BlockValue            *ethgo.ArgBig       `json:"blockValue"`
+	BlobsBundle           *BlobsBundleV1      `json:"blobsBundle"`
+	ShouldOverrideBuilder bool                `json:"shouldOverrideBuilder"`
+}
+
+// GetPayloadV4Response is the response of engine_getPayloadV4
+type GetPayloadV4Response struct {
+	GetPayloadV3Response
+	ExecutionRequests []ethgo.ArgBytes `json:"executionRequests"`
+}
+
+// BlobAndProofV1 is a blob in the transaction pool with its proof
+type BlobAndProofV1 struct {
+	Blob  ethgo.ArgBytes `json:"blob"`
+	Proof ethgo.ArgBytes `json:"proof"`
+}
+
+// NewPayloadV1 sends a Paris payload to validate and execute
+func (e *Engine) NewPayloadV1(payload *ExecutionPayloadV1) (*PayloadStatus, error) {
+	var out *PayloadStatus
+	err := e.c.CallContext(e.ctx, "engine_newPayloadV1", &out, payload)
+	return out, err
+}
+
+// NewPayloadV2 sends a Paris or Shanghai payload to validate and execute
+func (e *Engine) NewPayloadV2(payload *ExecutionPayloadV2) (*PayloadStatus, error) {
+	var out *PayloadStatus
+	err := e.c.CallContext(e.ctx, "engine_newPayloadV2", &out, payload)
+	return out, err
+}
+
+// NewPayloadV3 sends a Cancun payload with the versioned hashes of its blobs
+// and the root of the parent beacon block
+func (e *Engine) NewPayloadV3(payload *ExecutionPayloadV3, versionedHashes []ethgo.Hash, parentBeaconBlockRoot ethgo.Hash) (*PayloadStatus, error) {
+	if versionedHashes == nil {
+		versionedHashes = []ethgo.Hash{}
+	}
+	var out *PayloadStatus
+	err := e.c.CallContext(e.ctx, "engine_newPayloadV3", &out, payload, versionedHashes, parentBeaconBlockRoot)
+	return out, err
+}
+
+// NewPayloadV4 sends a Prague payload with the versioned hashes of its blobs,
+// the root of the parent beacon block and the execution layer requests
+func (e *Engine) NewPayloadV4(payload *ExecutionPayloadV3, versionedHashes []ethgo.Hash, parentBeaconBlockRoot ethgo.Hash, executionRequests []ethgo.ArgBytes) (*PayloadStatus, error) {
+	if versionedHashes == nil {
+		versionedHashes = []ethgo.Hash{}
+	}
+	if executionRequests == nil {
+		executionRequests = []ethgo.ArgBytes{}
+	}
+	var out *PayloadStatus
+	err := e.c.CallContext(e.ctx, "engine_newPayloadV4", &out, payload, versionedHashes, parentBeaconBlockRoot, executionRequests)
+	return out, err
+}
+
+// ForkchoiceUpdatedV1 updates the forkchoice state and starts to build
+// a Paris payload if the attributes are set
+func (e *Engine) ForkchoiceUpdatedV1(state *ForkchoiceState, attrs *PayloadAttributesV1) (*ForkchoiceUpdatedResult, error) {
+	var out *ForkchoiceUpdatedResult
+	err := e.c.CallContext(e.ctx, "engine_forkchoiceUpdatedV1", &out, state, attrs)
+	return out, err
+}
+
+// ForkchoiceUpdatedV2 updates the forkchoice state and starts to build
+// a Shanghai payload if the attributes are set
+func (e *Engine) ForkchoiceUpdatedV2(state *ForkchoiceState, attrs *PayloadAttributesV2) (*ForkchoiceUpdatedResult, error) {
+	var out *ForkchoiceUpdatedResult
+	err := e.c.CallContext(e.ctx, "engine_forkchoiceUpdatedV2", &out, state, attrs)
+	return out, err
+}
+
+// ForkchoiceUpdatedV3 updates the forkchoice state and starts to build
+// a Cancun or Prague payload if the attributes are set
+func (e *Engine) ForkchoiceUpdatedV3(state *ForkchoiceState, attrs *PayloadAttributesV3) (*ForkchoiceUpdatedResult, error) {
+	var out *ForkchoiceUpdatedResult
+	err := e.c.CallContext(e.ctx, "engine_forkchoiceUpdatedV3", &out, state, attrs)
+	return out, err
+}
+
+// GetPayloadV1 returns the Paris payload being built
+func (e *Engine) GetPayloadV1(id PayloadID) (*ExecutionPayloadV1, error) {
+	var out *ExecutionPayloadV1
+	err := e.c.CallContext(e.ctx, "engine_getPayloadV1", &out, id)
+	return out, err
+}
+
+// GetPayloadV2 returns the Paris or Shanghai payload being built and its value
+func (e *Engine) GetPayloadV2(id PayloadID) (*GetPayloadV2Response, error) {
+	var out *GetPayloadV2Response
+	err := e.c.CallContext(e.ctx, "engine_getPayloadV2", &out, id)
+	return out, err
+}
+
+// GetPayloadV3 returns the Cancun payload being built with its blobs
+func (e *Engine) GetPayloadV3(id PayloadID) (*GetPayloadV3Response, error) {
+	var out *GetPayloadV3Response
+	err := e.c.CallContext(e.ctx, "engine_getPayloadV3", &out, id)
+	return out, err
+}
+
+// GetPayloadV4 returns the Prague payload being built with its blobs and requests
+func (e *Engine) GetPayloadV4(id PayloadID) (*GetPayloadV4Response, error) {
+	var out *GetPayloadV4Response
+	err := e.c.CallContext(e.ctx, "engine_getPayloadV4", &out, id)
+	return out, err
+}
+
+// ExchangeCapabilities sends the engine methods supported by the consensus
+// client and returns the ones supported by the execution client
+func (e *Engine) ExchangeCapabilities(methods []string) ([]string, error) {
+	var out []string
+	err := e.c.CallContext(e.ctx, "engine_exchangeCapabilities", &out, methods)
+	return out, err
+}
+
+// GetBlobsV1 returns the blobs in the transaction pool with the versioned
+// hashes. The missing blobs are returned as nil.
+func (e *Engine) GetBlobsV1(versionedHashes []ethgo.Hash) ([]*BlobAndProofV1, error) {
+	var out []*BlobAndProofV1
+	err := e.c.CallContext(e.ctx, "engine_getBlobsV1", &out, versionedHashes)
+	return out, err
+}
@@ -0,0 +1,155 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+	"github.com/umbracle/ethgo/jsonrpc/transport"
+)
+
+// newEngineServer returns a stub of the engine api that replies with the
+// results of each method and records the params of the requests
+func newEngineServer(t *testing.T, results map[string]string) (*httptest.Server, map[string]json.RawMessage) {
+	params := map[string]json.RawMessage{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req codec.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		params[req.Method] = req.Params
+
+		resp := &codec.Response{ID: req.ID}
+		if result, ok := results[req.Method]; ok {
+			resp.Result = json.RawMessage(result)
+		} else {
+			resp.Error = &codec.ErrorObject{Code: -32601, Message: "method not found"}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	return srv, params
+}
+
+func newEngineClient(t *testing.T, addr string) *Client {
+	auth, err := transport.NewJWTAuth([]byte("0123456789abcdef0123456789abcdef"))
+	assert.NoError(t, err)
+
+	c, err := NewClient(addr, WithAuth(auth))
+	assert.NoError(t, err)
+	return c
+}
+
+func TestEngine_Auth(t *testing.T) {
+	srv, _ := newEngineServer(t, map[string]string{
+		"engine_exchangeCapabilities": `["engine_newPayloadV3"]`,
+	})
+	defer srv.Close()
+
+	// the stub rejects the requests without credentials
+	c, err := NewClient(srv.URL)
+	assert.NoError(t, err)
+	_, err = c.Engine().ExchangeCapabilities([]string{"engine_newPayloadV3"})
+	assert.Error(t, err)
+
+	c = newEngineClient(t, srv.URL)
+	methods, err := c.Engine().ExchangeCapabilities([]string{"engine_newPayloadV3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"engine_newPayloadV3"}, methods)
+}
+
+func TestEngine_NewPayload(t *testing.T) {
+	srv, params := newEngineServer(t, map[string]string{
+		"engine_newPayloadV3": `{"status":"INVALID","latestValidHash":"0x0000000000000000000000000000000000000000000000000000000000000001","validationError":"bad block"}`,
+		"engine_newPayloadV4": `{"status":"VALID","latestValidHash":null,"validationError":null}`,
+	})
+	defer srv.Close()
+
+	c := newEngineClient(t, srv.URL)
+
+	payload := &ExecutionPayloadV3{}
+	payload.BlockNumber = 10
+	payload.BaseFeePerGas = new(ethgo.ArgBig)
+	payload.Withdrawals = []*Withdrawal{{Index: 1, Amount: 2}}
+	payload.BlobGasUsed = 0x20000
+
+	status, err := c.Engine().NewPayloadV3(payload, nil, ethgo.Hash{0x1})
+	assert.NoError(t, err)
+	assert.Equal(t, PayloadStatusInvalid, status.Status)
+	assert.Equal(t, ethgo.HexToHash("0x1"), *status.LatestValidHash)
+	assert.Equal(t, "bad block", *status.ValidationError)
+
+	var raw []json.RawMessage
+	assert.NoError(t, json.Unmarshal(params["engine_newPayloadV3"], &raw))
+	assert.Len(t, raw, 3)
+	assert.Equal(t, "[]", string(raw[1]))
+
+	var obj map[string]interface{}
+	assert.NoError(t, json.Unmarshal(raw[0], &obj))
+	assert.Equal(t, "0xa", obj["blockNumber"])
+	assert.Equal(t, "0x20000", obj["blobGasUsed"])
+	assert.Equal(t, "0x0", obj["excessBlobGas"])
+	assert.Len(t, obj["withdrawals"], 1)
+
+	status, err = c.Engine().NewPayloadV4(payload, []ethgo.Hash{{0x1}}, ethgo.Hash{}, []ethgo.ArgBytes{{0x1, 0x2}})
+	assert.NoError(t, err)
+	assert.Equal(t, PayloadStatusValid, status.Status)
+	assert.Nil(t, status.LatestValidHash)
+
+	assert.NoError(t, json.Unmarshal(params["engine_newPayloadV4"], &raw))
+	assert.Len(t, raw, 4)
+	assert.Equal(t, `["0x0102"]`, string(raw[3]))
+}
+
+func TestEngine_ForkchoiceAndGetPayload(t *testing.T) {
+	srv, params := newEngineServer(t, map[string]string{
+		"engine_forkchoiceUpdatedV3": `{"payloadStatus":{"status":"VALID"},"payloadId":"0x0102030405060708"}`,
+		"engine_getPayloadV4": `{
+			"executionPayload": {"blockNumber":"0x5","baseFeePerGas":"0x7","transactions":["0x01"],"withdrawals":[],"blobGasUsed":"0x0","excessBlobGas":"0x1"},
+			"blockValue": "0x10",
+			"blobsBundle": {"commitments":["0x01"],"proofs":["0x02"],"blobs":["0x03"]},
+			"shouldOverrideBuilder": true,
+			"executionRequests": ["0x00aa"]
+		}`,
+		"engine_getBlobsV1": `[{"blob":"0x01","proof":"0x02"},null]`,
+	})
+	defer srv.Close()
+
+	c := newEngineClient(t, srv.URL)
+
+	attrs := &PayloadAttributesV3{}
+	attrs.Timestamp = 12
+	res, err := c.Engine().ForkchoiceUpdatedV3(&ForkchoiceState{HeadBlockHash: ethgo.Hash{0x1}}, attrs)
+	assert.NoError(t, err)
+	assert.Equal(t, PayloadStatusValid, res.PayloadStatus.Status)
+	assert.Equal(t, "0x0102030405060708", res.PayloadID.String())
+
+	var raw []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(params["engine_forkchoiceUpdatedV3"], &raw))
+	assert.Equal(t, "0xc", raw[1]["timestamp"])
+	assert.Contains(t, raw[1], "parentBeaconBlockRoot")
+	assert.Contains(t, raw[1], "withdrawals")
+
+	payload, err := c.Engine().GetPayloadV4(*res.PayloadID)
+	assert.NoError(t, err)
+	assert.Equal(t, `["0x0102030405060708"]`, string(params["engine_getPayloadV4"]))
+	assert.Equal(t, uint64(5), payload.ExecutionPayload.BlockNumber.Uint64())
+	assert.Equal(t, uint64(1), payload.ExecutionPayload.ExcessBlobGas.Uint64())
+	assert.Equal(t, []ethgo.ArgBytes{{0x1}}, payload.ExecutionPayload.Transactions)
+	assert.True(t, payload.ShouldOverrideBuilder)
+	assert.Equal(t, []ethgo.ArgBytes{{0x3}}, payload.BlobsBundle.Blobs)
+	assert.Equal(t, []ethgo.ArgBytes{{0x0, 0xaa}}, payload.ExecutionRequests)
+
+	blobs, err := c.Engine().GetBlobsV1([]ethgo.Hash{{0x1}, {0x2}})
+	assert.NoError(t, err)
+	assert.Len(t, blobs, 2)
+	assert.Equal(t, ethgo.ArgBytes{0x2}, blobs[0].Proof)
+	assert.Nil(t, blobs[1])
+}
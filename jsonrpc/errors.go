@@ -0,0 +1,21 @@
+package jsonrpc
+
+import (
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+// The errors of the jsonrpc responses are classified with these types and
+// they can be checked with errors.Is and errors.As. The *codec.ErrorObject
+// with the code and the data of the error is also available with errors.As.
+var (
+	ErrNotFound               = codec.ErrNotFound
+	ErrNonceTooLow            = codec.ErrNonceTooLow
+	ErrInsufficientFunds      = codec.ErrInsufficientFunds
+	ErrReplacementUnderpriced = codec.ErrReplacementUnderpriced
+)
+
+// RevertError is returned when a call or a transaction reverts
+type RevertError = codec.RevertError
+
+// RangeLimitError is returned when the provider rejects the block range of a query
+type RangeLimitError = codec.RangeLimitError
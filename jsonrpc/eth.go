@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -11,7 +12,8 @@ import (
 
 // Eth is the eth namespace
 type Eth struct {
-	c *Client
+	c   *Client
+	ctx context.Context
 }
 
 // Eth returns the reference to the eth namespace
@@ -19,10 +21,16 @@ func (c *Client) Eth() *Eth {
 	return c.endpoints.e
 }
 
+// WithContext returns a copy of the eth namespace whose requests
+// are aborted when the context is done
+func (e *Eth) WithContext(ctx context.Context) *Eth {
+	return &Eth{c: e.c, ctx: ctx}
+}
+
 // GetCode returns the code of a contract
 func (e *Eth) GetCode(addr ethgo.Address, block ethgo.BlockNumberOrHash) (string, error) {
 	var res string
-	if err := e.c.Call("eth_getCode", &res, addr, block.Location()); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_getCode", &res, addr, blockParam(block)); err != nil {
 		return "", err
 	}
 	return res, nil
@@ -31,7 +39,7 @@ func (e *Eth) GetCode(addr ethgo.Address, block ethgo.BlockNumberOrHash) (string
 // Accounts returns a list of addresses owned by client.
 func (e *Eth) Accounts() ([]ethgo.Address, error) {
 	var out []ethgo.Address
-	if err := e.c.Call("eth_accounts", &out); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_accounts", &out); err != nil {
 		return nil, err
 	}
 	return out, nil
@@ -40,14 +48,30 @@ func (e *Eth) Accounts() ([]ethgo.Address, error) {
 // GetStorageAt returns the value from a storage position at a given address.
 func (e *Eth) GetStorageAt(addr ethgo.Address, slot ethgo.Hash, block ethgo.BlockNumberOrHash) (ethgo.Hash, error) {
 	var hash ethgo.Hash
-	err := e.c.Call("eth_getStorageAt", &hash, addr, slot, block.Location())
+	err := e.c.CallContext(e.ctx, "eth_getStorageAt", &hash, addr, slot, blockParam(block))
 	return hash, err
 }
 
+// GetProof returns the merkle proof of the account and of the storage slots at a given block.
+// The proof can be verified against the state root of the block with the trie package.
+func (e *Eth) GetProof(addr ethgo.Address, slots []ethgo.Hash, block ethgo.BlockNumberOrHash) (*ethgo.AccountProof, error) {
+	if slots == nil {
+		slots = []ethgo.Hash{}
+	}
+	var proof *ethgo.AccountProof
+	if err := e.c.CallContext(e.ctx, "eth_getProof", &proof, addr, slots, blockParam(block)); err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, ErrNotFound
+	}
+	return proof, nil
+}
+
 // BlockNumber returns the number of most recent block.
 func (e *Eth) BlockNumber() (uint64, error) {
 	var out string
-	if err := e.c.Call("eth_blockNumber", &out); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_blockNumber", &out); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(out)
@@ -56,7 +80,7 @@ func (e *Eth) BlockNumber() (uint64, error) {
 // GetBlockByNumber returns information about a block by block number.
 func (e *Eth) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Block, error) {
 	var b *ethgo.Block
-	if err := e.c.Call("eth_getBlockByNumber", &b, i.String(), full); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_getBlockByNumber", &b, i.String(), full); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -65,7 +89,7 @@ func (e *Eth) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Block, er
 // GetBlockByHash returns information about a block by hash.
 func (e *Eth) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, error) {
 	var b *ethgo.Block
-	if err := e.c.Call("eth_getBlockByHash", &b, hash, full); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_getBlockByHash", &b, hash, full); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -74,7 +98,7 @@ func (e *Eth) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, error) {
 // GetFilterChanges returns the filter changes for log filters
 func (e *Eth) GetFilterChanges(id string) ([]*ethgo.Log, error) {
 	var logs []*ethgo.Log
-	if err := e.c.Call("eth_getFilterChanges", &logs, id); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_getFilterChanges", &logs, id); err != nil {
 		return nil, err
 	}
 	return logs, nil
@@ -83,14 +107,14 @@ func (e *Eth) GetFilterChanges(id string) ([]*ethgo.Log, error) {
 // GetTransactionByHash returns a transaction by his hash
 func (e *Eth) GetTransactionByHash(hash ethgo.Hash) (*ethgo.Transaction, error) {
 	var txn *ethgo.Transaction
-	err := e.c.Call("eth_getTransactionByHash", &txn, hash)
+	err := e.c.CallContext(e.ctx, "eth_getTransactionByHash", &txn, hash)
 	return txn, err
 }
 
 // GetFilterChangesBlock returns the filter changes for block filters
 func (e *Eth) GetFilterChangesBlock(id string) ([]ethgo.Hash, error) {
 	var hashes []ethgo.Hash
-	if err := e.c.Call("eth_getFilterChanges", &hashes, id); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_getFilterChanges", &hashes, id); err != nil {
 		return nil, err
 	}
 	return hashes, nil
@@ -99,21 +123,21 @@ func (e *Eth) GetFilterChangesBlock(id string) ([]ethgo.Hash, error) {
 // NewFilter creates a new log filter
 func (e *Eth) NewFilter(filter *ethgo.LogFilter) (string, error) {
 	var id string
-	err := e.c.Call("eth_newFilter", &id, filter)
+	err := e.c.CallContext(e.ctx, "eth_newFilter", &id, filter)
 	return id, err
 }
 
 // NewBlockFilter creates a new block filter
 func (e *Eth) NewBlockFilter() (string, error) {
 	var id string
-	err := e.c.Call("eth_newBlockFilter", &id, nil)
+	err := e.c.CallContext(e.ctx, "eth_newBlockFilter", &id, nil)
 	return id, err
 }
 
 // UninstallFilter uninstalls a filter
 func (e *Eth) UninstallFilter(id string) (bool, error) {
 	var res bool
-	err := e.c.Call("eth_uninstallFilter", &res, id)
+	err := e.c.CallContext(e.ctx, "eth_uninstallFilter", &res, id)
 	return res, err
 }
 
@@ -121,28 +145,35 @@ func (e *Eth) UninstallFilter(id string) (bool, error) {
 func (e *Eth) SendRawTransaction(data []byte) (ethgo.Hash, error) {
 	var hash ethgo.Hash
 	hexData := "0x" + hex.EncodeToString(data)
-	err := e.c.Call("eth_sendRawTransaction", &hash, hexData)
+	err := e.c.CallContext(e.ctx, "eth_sendRawTransaction", &hash, hexData)
 	return hash, err
 }
 
 // SendTransaction creates new message call transaction or a contract creation.
 func (e *Eth) SendTransaction(txn *ethgo.Transaction) (ethgo.Hash, error) {
 	var hash ethgo.Hash
-	err := e.c.Call("eth_sendTransaction", &hash, txn)
+	err := e.c.CallContext(e.ctx, "eth_sendTransaction", &hash, txn)
 	return hash, err
 }
 
 // GetTransactionReceipt returns the receipt of a transaction by transaction hash.
 func (e *Eth) GetTransactionReceipt(hash ethgo.Hash) (*ethgo.Receipt, error) {
 	var receipt *ethgo.Receipt
-	err := e.c.Call("eth_getTransactionReceipt", &receipt, hash)
+	err := e.c.CallContext(e.ctx, "eth_getTransactionReceipt", &receipt, hash)
 	return receipt, err
 }
 
+// GetBlockReceipts returns the receipts of all the transactions of a block (eth_getBlockReceipts)
+func (e *Eth) GetBlockReceipts(block ethgo.BlockNumberOrHash) ([]*ethgo.Receipt, error) {
+	var receipts []*ethgo.Receipt
+	err := e.c.CallContext(e.ctx, "eth_getBlockReceipts", &receipts, blockParam(block))
+	return receipts, err
+}
+
 // GetNonce returns the nonce of the account
 func (e *Eth) GetNonce(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash) (uint64, error) {
 	var nonce string
-	if err := e.c.Call("eth_getTransactionCount", &nonce, addr, blockNumber.Location()); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_getTransactionCount", &nonce, addr, blockParam(blockNumber)); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(nonce)
@@ -151,7 +182,7 @@ func (e *Eth) GetNonce(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash)
 // GetBalance returns the balance of the account of given address.
 func (e *Eth) GetBalance(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash) (*big.Int, error) {
 	var out string
-	if err := e.c.Call("eth_getBalance", &out, addr, blockNumber.Location()); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_getBalance", &out, addr, blockParam(blockNumber)); err != nil {
 		return nil, err
 	}
 	b, ok := new(big.Int).SetString(out[2:], 16)
@@ -164,16 +195,63 @@ func (e *Eth) GetBalance(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash
 // GasPrice returns the current price per gas in wei.
 func (e *Eth) GasPrice() (uint64, error) {
 	var out string
-	if err := e.c.Call("eth_gasPrice", &out); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_gasPrice", &out); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(out)
 }
 
+// CallConfig are the optional params of eth_call and eth_estimateGas
+type CallConfig struct {
+	StateOverrides StateOverride
+	BlockOverrides *BlockOverrides
+}
+
+type CallOption func(*CallConfig)
+
+// WithStateOverrides replaces the state of some accounts during the call
+func WithStateOverrides(overrides StateOverride) CallOption {
+	return func(c *CallConfig) {
+		c.StateOverrides = overrides
+	}
+}
+
+// WithBlockOverrides replaces the fields of the block of the call. It is
+// only used by eth_call.
+func WithBlockOverrides(overrides *BlockOverrides) CallOption {
+	return func(c *CallConfig) {
+		c.BlockOverrides = overrides
+	}
+}
+
+// params returns the optional params of the request. The overrides are
+// only included if they are set since some nodes do not support them.
+func (c *CallConfig) params(blockOverrides bool) []interface{} {
+	params := []interface{}{}
+	if c.StateOverrides != nil || (blockOverrides && c.BlockOverrides != nil) {
+		params = append(params, c.StateOverrides)
+	}
+	if blockOverrides && c.BlockOverrides != nil {
+		params = append(params, c.BlockOverrides)
+	}
+	return params
+}
+
+func newCallConfig(opts []CallOption) *CallConfig {
+	config := &CallConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
 // Call executes a new message call immediately without creating a transaction on the block chain.
-func (e *Eth) Call(msg *ethgo.CallMsg, block ethgo.BlockNumber) (string, error) {
+// The block is either a number, a hash or an ethgo.BlockHashSelector (EIP-1898).
+func (e *Eth) Call(msg *ethgo.CallMsg, block ethgo.BlockNumberOrHash, opts ...CallOption) (string, error) {
+	params := append([]interface{}{msg, blockParam(block)}, newCallConfig(opts).params(true)...)
+
 	var out string
-	if err := e.c.Call("eth_call", &out, msg, block.String()); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_call", &out, params...); err != nil {
 		return "", err
 	}
 	return out, nil
@@ -185,7 +263,7 @@ func (e *Eth) EstimateGasContract(bin []byte) (uint64, error) {
 	msg := map[string]interface{}{
 		"data": "0x" + hex.EncodeToString(bin),
 	}
-	if err := e.c.Call("eth_estimateGas", &out, msg); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_estimateGas", &out, msg); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(out)
@@ -194,7 +272,18 @@ func (e *Eth) EstimateGasContract(bin []byte) (uint64, error) {
 // EstimateGas generates and returns an estimate of how much gas is necessary to allow the transaction to complete.
 func (e *Eth) EstimateGas(msg *ethgo.CallMsg) (uint64, error) {
 	var out string
-	if err := e.c.Call("eth_estimateGas", &out, msg); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_estimateGas", &out, msg); err != nil {
+		return 0, err
+	}
+	return parseUint64orHex(out)
+}
+
+// EstimateGasAt estimates the gas of the transaction on top of the state of the block
+func (e *Eth) EstimateGasAt(msg *ethgo.CallMsg, block ethgo.BlockNumberOrHash, opts ...CallOption) (uint64, error) {
+	params := append([]interface{}{msg, blockParam(block)}, newCallConfig(opts).params(false)...)
+
+	var out string
+	if err := e.c.CallContext(e.ctx, "eth_estimateGas", &out, params...); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(out)
@@ -203,7 +292,7 @@ func (e *Eth) EstimateGas(msg *ethgo.CallMsg) (uint64, error) {
 // GetLogs returns an array of all logs matching a given filter object
 func (e *Eth) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
 	var out []*ethgo.Log
-	if err := e.c.Call("eth_getLogs", &out, filter); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_getLogs", &out, filter); err != nil {
 		return nil, err
 	}
 	return out, nil
@@ -212,7 +301,7 @@ func (e *Eth) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
 // ChainID returns the id of the chain
 func (e *Eth) ChainID() (*big.Int, error) {
 	var out string
-	if err := e.c.Call("eth_chainId", &out); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_chainId", &out); err != nil {
 		return nil, err
 	}
 	return parseBigInt(out), nil
@@ -260,10 +349,48 @@ func (f *FeeHistory) UnmarshalJSON(data []byte) error {
 }
 
 // FeeHistory returns base fee per gas and transaction effective priority fee
+//
+// Deprecated: the from argument is sent as the block count of eth_feeHistory.
+// Use FeeHistoryWithRewards instead.
 func (e *Eth) FeeHistory(from, to ethgo.BlockNumber) (*FeeHistory, error) {
 	var out *FeeHistory
-	if err := e.c.Call("eth_feeHistory", &out, from.String(), to.String(), nil); err != nil {
+	if err := e.c.CallContext(e.ctx, "eth_feeHistory", &out, from.String(), to.String(), nil); err != nil {
 		return nil, err
 	}
 	return out, nil
 }
+
+// FeeHistoryWithRewards returns the base fee per gas and the gas used ratio of the last
+// blockCount blocks up to the newest block. If reward percentiles (between 0 and 100 in
+// ascending order) are given, it also returns the effective priority fee paid at each
+// percentile weighted by the gas used of the transactions in the block.
+func (e *Eth) FeeHistoryWithRewards(blockCount uint64, newest ethgo.BlockNumber, rewardPercentiles ...float64) (*FeeHistory, error) {
+	var percentiles []float64
+	if len(rewardPercentiles) != 0 {
+		percentiles = rewardPercentiles
+	}
+
+	var out *FeeHistory
+	if err := e.c.CallContext(e.ctx, "eth_feeHistory", &out, ethgo.ArgUint64(blockCount), newest.String(), percentiles); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BlobBaseFee returns the base fee per blob gas of the next block (eip-4844)
+func (e *Eth) BlobBaseFee() (*big.Int, error) {
+	var out string
+	if err := e.c.CallContext(e.ctx, "eth_blobBaseFee", &out); err != nil {
+		return nil, err
+	}
+	return parseBigInt(out), nil
+}
+
+// MaxPriorityFeePerGas returns the priority fee per gas suggested by the node
+func (e *Eth) MaxPriorityFeePerGas() (*big.Int, error) {
+	var out string
+	if err := e.c.CallContext(e.ctx, "eth_maxPriorityFeePerGas", &out); err != nil {
+		return nil, err
+	}
+	return parseBigInt(out), nil
+}
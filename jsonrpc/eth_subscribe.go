@@ -0,0 +1,387 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/transport"
+)
+
+// ErrSubscriptionQueueOverflow is returned when the subscriber does not
+// consume the notifications fast enough
+var ErrSubscriptionQueueOverflow = errors.New("subscription queue overflow")
+
+const subscriptionQueueSize = 1000
+
+// Subscription is a typed subscription created with one of the Subscribe
+// methods of the eth namespace
+type Subscription struct {
+	queue     chan []byte
+	errCh     chan error
+	resumedCh chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+
+	shared *sharedSubscription
+	handle func(buf []byte, closeCh <-chan struct{}) error
+}
+
+// Err returns the errors of the subscription. The subscription is
+// terminated after an ErrSubscriptionQueueOverflow error or if it
+// cannot be started again after a reconnection.
+func (s *Subscription) Err() <-chan error {
+	return s.errCh
+}
+
+// Resumed notifies that the subscription was started again on a new
+// connection and some notifications may have been lost
+func (s *Subscription) Resumed() <-chan struct{} {
+	return s.resumedCh
+}
+
+// Unsubscribe stops the subscription. The upstream subscription is
+// cancelled once all the subscribers that share it are stopped.
+func (s *Subscription) Unsubscribe() error {
+	s.close()
+	return s.shared.remove(s)
+}
+
+func (s *Subscription) close() {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+	})
+}
+
+func (s *Subscription) sendErr(err error) {
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
+
+func (s *Subscription) deliver(buf []byte) {
+	select {
+	case s.queue <- buf:
+	default:
+		s.sendErr(ErrSubscriptionQueueOverflow)
+		s.close()
+		s.shared.remove(s)
+	}
+}
+
+func (s *Subscription) run() {
+	for {
+		select {
+		case buf := <-s.queue:
+			if err := s.handle(buf, s.closeCh); err != nil {
+				s.sendErr(err)
+			}
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// sharedSubscription is an upstream subscription shared by all the
+// subscribers with the same method and params
+type sharedSubscription struct {
+	key    string
+	m      *subscriptionManager
+	cancel func() error
+
+	lock sync.Mutex
+	subs map[*Subscription]struct{}
+	done bool
+}
+
+func (s *sharedSubscription) subscribers() []*Subscription {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res := make([]*Subscription, 0, len(s.subs))
+	for sub := range s.subs {
+		res = append(res, sub)
+	}
+	return res
+}
+
+func (s *sharedSubscription) dispatch(buf []byte) {
+	for _, sub := range s.subscribers() {
+		sub.deliver(buf)
+	}
+}
+
+func (s *sharedSubscription) resumed() {
+	for _, sub := range s.subscribers() {
+		select {
+		case sub.resumedCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *sharedSubscription) failed(err error) {
+	s.m.lock.Lock()
+	if s.m.subs[s.key] == s {
+		delete(s.m.subs, s.key)
+	}
+	s.m.lock.Unlock()
+
+	s.lock.Lock()
+	subs := s.subs
+	s.subs = map[*Subscription]struct{}{}
+	s.done = true
+	s.lock.Unlock()
+
+	for sub := range subs {
+		sub.sendErr(err)
+		sub.close()
+	}
+}
+
+func (s *sharedSubscription) remove(sub *Subscription) error {
+	s.m.lock.Lock()
+	defer s.m.lock.Unlock()
+
+	s.lock.Lock()
+	if _, ok := s.subs[sub]; !ok {
+		done := s.done
+		s.lock.Unlock()
+		if done {
+			// the upstream subscription already failed
+			return nil
+		}
+		return fmt.Errorf("subscription not found")
+	}
+	delete(s.subs, sub)
+	empty := len(s.subs) == 0
+	s.lock.Unlock()
+
+	if !empty {
+		return nil
+	}
+	s.lock.Lock()
+	s.done = true
+	s.lock.Unlock()
+
+	if s.m.subs[s.key] == s {
+		delete(s.m.subs, s.key)
+	}
+	return s.cancel()
+}
+
+// subscriptionManager tracks the upstream subscriptions of a client
+type subscriptionManager struct {
+	c    *Client
+	lock sync.Mutex
+	subs map[string]*sharedSubscription
+}
+
+func newSubscriptionManager(c *Client) *subscriptionManager {
+	return &subscriptionManager{
+		c:    c,
+		subs: map[string]*sharedSubscription{},
+	}
+}
+
+func (m *subscriptionManager) subscribe(ctx context.Context, method string, params []interface{}, handle func(buf []byte, closeCh <-chan struct{}) error) (*Subscription, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	key := method + string(data)
+
+	sub := &Subscription{
+		queue:     make(chan []byte, subscriptionQueueSize),
+		errCh:     make(chan error, 1),
+		resumedCh: make(chan struct{}, 1),
+		closeCh:   make(chan struct{}),
+		handle:    handle,
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	shared, ok := m.subs[key]
+	if !ok {
+		shared = &sharedSubscription{
+			key:  key,
+			m:    m,
+			subs: map[*Subscription]struct{}{},
+		}
+		cancel, err := m.c.SubscribeWithOpts(ctx, &transport.SubscribeOpts{
+			Method:   method,
+			Params:   params,
+			Callback: shared.dispatch,
+			Resumed:  shared.resumed,
+			Failed:   shared.failed,
+		})
+		if err != nil {
+			return nil, err
+		}
+		shared.cancel = cancel
+		m.subs[key] = shared
+	}
+
+	sub.shared = shared
+	shared.lock.Lock()
+	shared.subs[sub] = struct{}{}
+	shared.lock.Unlock()
+
+	go sub.run()
+	return sub, nil
+}
+
+// SubscribeNewHeads sends the new heads of the chain to the channel
+func (e *Eth) SubscribeNewHeads(ch chan<- *ethgo.Block) (*Subscription, error) {
+	return e.c.subs.subscribe(e.ctx, "newHeads", nil, func(buf []byte, closeCh <-chan struct{}) error {
+		block := new(ethgo.Block)
+		if err := block.UnmarshalJSON(buf); err != nil {
+			return err
+		}
+		select {
+		case ch <- block:
+		case <-closeCh:
+		}
+		return nil
+	})
+}
+
+// SubscribeLogs sends the new logs that match the filter to the channel.
+// Only the address and topics of the filter are used, a nil filter subscribes to all the logs.
+func (e *Eth) SubscribeLogs(filter *ethgo.LogFilter, ch chan<- *ethgo.Log) (*Subscription, error) {
+	subFilter := &ethgo.LogFilter{}
+	if filter != nil {
+		subFilter.Address = filter.Address
+		subFilter.Topics = filter.Topics
+	}
+	return e.c.subs.subscribe(e.ctx, "logs", []interface{}{subFilter}, func(buf []byte, closeCh <-chan struct{}) error {
+		log := new(ethgo.Log)
+		if err := log.UnmarshalJSON(buf); err != nil {
+			return err
+		}
+		select {
+		case ch <- log:
+		case <-closeCh:
+		}
+		return nil
+	})
+}
+
+// SubscribePendingTransactions sends the hashes of the new pending transactions to the channel
+func (e *Eth) SubscribePendingTransactions(ch chan<- ethgo.Hash) (*Subscription, error) {
+	return e.c.subs.subscribe(e.ctx, "newPendingTransactions", nil, func(buf []byte, closeCh <-chan struct{}) error {
+		var hash ethgo.Hash
+		if err := json.Unmarshal(buf, &hash); err != nil {
+			return err
+		}
+		select {
+		case ch <- hash:
+		case <-closeCh:
+		}
+		return nil
+	})
+}
+
+// SubscribeFullPendingTransactions sends the new pending transactions to the channel.
+// The node must support the full transactions flag of the subscription.
+func (e *Eth) SubscribeFullPendingTransactions(ch chan<- *ethgo.Transaction) (*Subscription, error) {
+	return e.c.subs.subscribe(e.ctx, "newPendingTransactions", []interface{}{true}, func(buf []byte, closeCh <-chan struct{}) error {
+		txn := new(ethgo.Transaction)
+		if err := txn.UnmarshalJSON(buf); err != nil {
+			return err
+		}
+		select {
+		case ch <- txn:
+		case <-closeCh:
+		}
+		return nil
+	})
+}
+
+// SyncStatus is the sync status of the node
+type SyncStatus struct {
+	Syncing       bool
+	StartingBlock uint64
+	CurrentBlock  uint64
+	HighestBlock  uint64
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The status is either
+// false or an object with the progress, which some nodes wrap in a status field.
+func (s *SyncStatus) UnmarshalJSON(data []byte) error {
+	var syncing bool
+	if err := json.Unmarshal(data, &syncing); err == nil {
+		*s = SyncStatus{Syncing: syncing}
+		return nil
+	}
+
+	var raw struct {
+		Syncing *bool            `json:"syncing"`
+		Status  *json.RawMessage `json:"status"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Status != nil {
+		data = *raw.Status
+	}
+
+	var progress struct {
+		StartingBlock json.RawMessage `json:"startingBlock"`
+		CurrentBlock  json.RawMessage `json:"currentBlock"`
+		HighestBlock  json.RawMessage `json:"highestBlock"`
+	}
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return err
+	}
+
+	var err error
+	if s.StartingBlock, err = parseJSONUint64(progress.StartingBlock); err != nil {
+		return err
+	}
+	if s.CurrentBlock, err = parseJSONUint64(progress.CurrentBlock); err != nil {
+		return err
+	}
+	if s.HighestBlock, err = parseJSONUint64(progress.HighestBlock); err != nil {
+		return err
+	}
+	s.Syncing = true
+	if raw.Syncing != nil {
+		s.Syncing = *raw.Syncing
+	}
+	return nil
+}
+
+// parseJSONUint64 parses a json number or a hex string
+func parseJSONUint64(data json.RawMessage) (uint64, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return 0, nil
+	}
+	str := strings.Trim(string(data), `"`)
+	if strings.HasPrefix(str, "0x") {
+		return parseUint64orHex(str)
+	}
+	return strconv.ParseUint(str, 10, 64)
+}
+
+// SubscribeSyncing sends the changes in the sync status of the node to the channel
+func (e *Eth) SubscribeSyncing(ch chan<- *SyncStatus) (*Subscription, error) {
+	return e.c.subs.subscribe(e.ctx, "syncing", nil, func(buf []byte, closeCh <-chan struct{}) error {
+		status := new(SyncStatus)
+		if err := json.Unmarshal(buf, status); err != nil {
+			return err
+		}
+		select {
+		case ch <- status:
+		case <-closeCh:
+		}
+		return nil
+	})
+}
@@ -0,0 +1,231 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+	"github.com/umbracle/ethgo/testutil"
+)
+
+func TestEthSubscribeNewHeads(t *testing.T) {
+	s := testutil.NewTestServer(t, nil)
+	defer s.Close()
+
+	c, _ := NewClient(s.WSAddr())
+	defer c.Close()
+
+	ch := make(chan *ethgo.Block)
+	sub, err := c.Eth().SubscribeNewHeads(ch)
+	assert.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	for i := uint64(1); i < 3; i++ {
+		assert.NoError(t, s.ProcessBlock())
+
+		select {
+		case block := <-ch:
+			assert.Equal(t, i, block.Number)
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
+
+// mockSubServer is a websocket server that counts the eth_subscribe requests
+type mockSubServer struct {
+	*httptest.Server
+
+	lock        sync.Mutex
+	conn        *websocket.Conn
+	subscribe   []json.RawMessage
+	unsubscribe int
+}
+
+func newMockSubServer(t *testing.T) *mockSubServer {
+	m := &mockSubServer{}
+
+	upgrader := websocket.Upgrader{}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		m.lock.Lock()
+		m.conn = conn
+		m.lock.Unlock()
+
+		for {
+			_, raw, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req codec.Request
+			assert.NoError(t, json.Unmarshal(raw, &req))
+
+			m.lock.Lock()
+			resp := &codec.Response{ID: req.ID}
+			switch req.Method {
+			case "eth_subscribe":
+				m.subscribe = append(m.subscribe, req.Params)
+				resp.Result, _ = json.Marshal(fmt.Sprintf("0x%d", len(m.subscribe)))
+			case "eth_unsubscribe":
+				m.unsubscribe++
+				resp.Result, _ = json.Marshal(true)
+			}
+			conn.WriteJSON(resp)
+			m.lock.Unlock()
+		}
+	}))
+	return m
+}
+
+func (m *mockSubServer) notify(id string, result interface{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	data, _ := json.Marshal(result)
+	params, _ := json.Marshal(&codec.Subscription{ID: id, Result: data})
+	m.conn.WriteJSON(&codec.Request{JsonRPC: "2.0", Method: "eth_subscription", Params: params})
+}
+
+func (m *mockSubServer) counts() (int, int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.subscribe), m.unsubscribe
+}
+
+func TestEthSubscribe_Shared(t *testing.T) {
+	srv := newMockSubServer(t)
+	defer srv.Close()
+
+	c, err := NewClient("ws" + strings.TrimPrefix(srv.URL, "http"))
+	assert.NoError(t, err)
+	defer c.Close()
+
+	ch1, ch2 := make(chan ethgo.Hash, 1), make(chan ethgo.Hash, 1)
+	sub1, err := c.Eth().SubscribePendingTransactions(ch1)
+	assert.NoError(t, err)
+	sub2, err := c.Eth().SubscribePendingTransactions(ch2)
+	assert.NoError(t, err)
+
+	// both subscribers share the same upstream subscription
+	subs, _ := srv.counts()
+	assert.Equal(t, 1, subs)
+
+	hash := ethgo.Hash{0x1}
+	srv.notify("0x1", hash)
+
+	for _, ch := range []chan ethgo.Hash{ch1, ch2} {
+		select {
+		case h := <-ch:
+			assert.Equal(t, hash, h)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	// the upstream subscription is cancelled with the last subscriber
+	assert.NoError(t, sub1.Unsubscribe())
+	_, unsubs := srv.counts()
+	assert.Equal(t, 0, unsubs)
+
+	assert.NoError(t, sub2.Unsubscribe())
+	_, unsubs = srv.counts()
+	assert.Equal(t, 1, unsubs)
+}
+
+func TestEthSubscribeLogs_Params(t *testing.T) {
+	srv := newMockSubServer(t)
+	defer srv.Close()
+
+	c, err := NewClient("ws" + strings.TrimPrefix(srv.URL, "http"))
+	assert.NoError(t, err)
+	defer c.Close()
+
+	filter := &ethgo.LogFilter{
+		Address: []ethgo.Address{{0x1}},
+	}
+	filter.SetFromUint64(10)
+
+	ch := make(chan *ethgo.Log, 1)
+	sub, err := c.Eth().SubscribeLogs(filter, ch)
+	assert.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	srv.lock.Lock()
+	params := string(srv.subscribe[0])
+	srv.lock.Unlock()
+
+	assert.Contains(t, params, `"logs"`)
+	assert.Contains(t, params, ethgo.Address{0x1}.String())
+	assert.NotContains(t, params, "fromBlock")
+
+	srv.notify("0x1", map[string]interface{}{
+		"address":          ethgo.Address{0x1}.String(),
+		"topics":           []string{},
+		"data":             "0x",
+		"blockNumber":      "0x1",
+		"transactionHash":  ethgo.Hash{}.String(),
+		"transactionIndex": "0x0",
+		"blockHash":        ethgo.Hash{}.String(),
+		"logIndex":         "0x0",
+		"removed":          false,
+	})
+
+	select {
+	case log := <-ch:
+		assert.Equal(t, uint64(1), log.BlockNumber)
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestEthSubscribeLogs_NilFilter(t *testing.T) {
+	srv := newMockSubServer(t)
+	defer srv.Close()
+
+	c, err := NewClient("ws" + strings.TrimPrefix(srv.URL, "http"))
+	assert.NoError(t, err)
+	defer c.Close()
+
+	sub, err := c.Eth().SubscribeLogs(nil, make(chan *ethgo.Log))
+	assert.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	srv.lock.Lock()
+	params := string(srv.subscribe[0])
+	srv.lock.Unlock()
+
+	assert.Contains(t, params, `"logs"`)
+	assert.NotContains(t, params, "address")
+}
+
+func TestSyncStatus_Unmarshal(t *testing.T) {
+	cases := []struct {
+		input  string
+		status SyncStatus
+	}{
+		{`false`, SyncStatus{}},
+		{`{"syncing":true,"status":{"startingBlock":1,"currentBlock":2,"highestBlock":3}}`, SyncStatus{true, 1, 2, 3}},
+		{`{"startingBlock":"0x1","currentBlock":"0x2","highestBlock":"0x3"}`, SyncStatus{true, 1, 2, 3}},
+	}
+	for _, c := range cases {
+		var status SyncStatus
+		assert.NoError(t, json.Unmarshal([]byte(c.input), &status))
+		assert.Equal(t, c.status, status)
+	}
+}
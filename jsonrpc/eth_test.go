@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/umbracle/ethgo"
 	"github.com/umbracle/ethgo/testutil"
+	"github.com/umbracle/ethgo/trie"
 	"github.com/umbracle/ethgo/wallet"
 )
 
@@ -368,6 +369,43 @@ func TestEthGetStorageAt(t *testing.T) {
 	}
 }
 
+func TestEthGetProof(t *testing.T) {
+	s := testutil.NewTestServer(t, nil)
+	defer s.Close()
+
+	c, _ := NewClient(s.HTTPAddr())
+
+	cc := &testutil.Contract{}
+	cc.AddCallback(func() string {
+		return "uint256 val;"
+	})
+	cc.AddCallback(func() string {
+		return `function setValue() public payable {
+			val = 10;
+		}`
+	})
+
+	_, addr := s.DeployContract(cc)
+	receipt := s.TxnTo(addr, "setValue")
+
+	block, err := c.Eth().GetBlockByNumber(ethgo.BlockNumber(receipt.BlockNumber), false)
+	assert.NoError(t, err)
+
+	slots := []ethgo.Hash{{}}
+	proof, err := c.Eth().GetProof(addr, slots, ethgo.BlockNumber(receipt.BlockNumber))
+	assert.NoError(t, err)
+
+	res, err := trie.VerifyBlock(block, addr, slots, proof)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), res.Nonce)
+	assert.Equal(t, uint64(10), res.Storage[ethgo.Hash{}].Uint64())
+
+	// the proof is not valid for another block
+	block.StateRoot = ethgo.Hash{0x1}
+	_, err = trie.VerifyBlock(block, addr, slots, proof)
+	assert.Error(t, err)
+}
+
 func TestEthFeeHistory(t *testing.T) {
 	c, _ := NewClient(testutil.TestInfuraEndpoint(t))
 
@@ -381,3 +419,71 @@ func TestEthFeeHistory(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, fee)
 }
+
+func TestEthFeeHistoryWithRewards(t *testing.T) {
+	c, _ := NewClient(testutil.TestInfuraEndpoint(t))
+
+	lastBlock, err := c.Eth().BlockNumber()
+	assert.NoError(t, err)
+
+	fee, err := c.Eth().FeeHistoryWithRewards(3, ethgo.BlockNumber(lastBlock), 25, 75)
+	assert.NoError(t, err)
+	assert.NotNil(t, fee)
+	assert.Len(t, fee.BaseFee, 4)
+	assert.Len(t, fee.Reward, 3)
+}
+
+func TestEth_FeeHistoryParams(t *testing.T) {
+	srv, params := newStubServer(t, map[string]string{
+		"eth_feeHistory": `{"oldestBlock":"0x1","baseFeePerGas":["0x1","0x2"],"gasUsedRatio":[0.5],"reward":[["0x1","0x2"]]}`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	// the deprecated method keeps its original parameters
+	_, err := c.Eth().FeeHistory(ethgo.BlockNumber(1), ethgo.BlockNumber(16))
+	assert.NoError(t, err)
+	assert.Equal(t, `["0x1","0x10",null]`, string(params["eth_feeHistory"]))
+
+	fee, err := c.Eth().FeeHistoryWithRewards(1, ethgo.BlockNumber(16), 25, 75)
+	assert.NoError(t, err)
+	assert.Equal(t, `["0x1","0x10",[25,75]]`, string(params["eth_feeHistory"]))
+	assert.Len(t, fee.Reward, 1)
+
+	_, err = c.Eth().FeeHistoryWithRewards(1, ethgo.Latest)
+	assert.NoError(t, err)
+	assert.Equal(t, `["0x1","latest",null]`, string(params["eth_feeHistory"]))
+}
+
+func TestEth_GetBlockReceipts(t *testing.T) {
+	srv, params := newStubServer(t, map[string]string{
+		"eth_getBlockReceipts": `[{
+			"type": "0x2",
+			"transactionHash": "0x0100000000000000000000000000000000000000000000000000000000000000",
+			"transactionIndex": "0x0",
+			"blockHash": "0x0200000000000000000000000000000000000000000000000000000000000000",
+			"blockNumber": "0x10",
+			"from": "0x0100000000000000000000000000000000000000",
+			"to": "0x0200000000000000000000000000000000000000",
+			"gasUsed": "0x5208",
+			"cumulativeGasUsed": "0x5208",
+			"effectiveGasPrice": "0x10",
+			"logsBloom": "0x` + strings.Repeat("00", 256) + `",
+			"logs": [],
+			"status": "0x1"
+		}]`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	receipts, err := c.Eth().GetBlockReceipts(ethgo.BlockNumber(16))
+	assert.NoError(t, err)
+	assert.Equal(t, `["0x10"]`, string(params["eth_getBlockReceipts"]))
+
+	assert.Len(t, receipts, 1)
+	assert.Equal(t, ethgo.TransactionDynamicFee, receipts[0].Type)
+	assert.Equal(t, addr1, *receipts[0].To)
+	assert.Equal(t, uint64(16), receipts[0].EffectiveGasPrice.Uint64())
+}
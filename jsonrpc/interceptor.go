@@ -0,0 +1,248 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Request is a jsonrpc request that goes through the interceptors of the client
+type Request struct {
+	// Method is the jsonrpc method. It is empty for batch requests.
+	Method string
+
+	// Params are the params of the request
+	Params []interface{}
+
+	// Batch are the elements of a batch request
+	Batch []*BatchElem
+
+	// Response is the raw result of the request. It is set once the
+	// request is sent and it is empty for batch requests.
+	Response json.RawMessage
+
+	// Duration is the time it took to send the request and receive the response
+	Duration time.Duration
+}
+
+// Methods returns the methods of the request. A batch request
+// returns the method of each element.
+func (r *Request) Methods() []string {
+	if r.Batch == nil {
+		return []string{r.Method}
+	}
+	methods := make([]string, len(r.Batch))
+	for indx, elem := range r.Batch {
+		methods[indx] = elem.Method
+	}
+	return methods
+}
+
+// Invoker sends the request to the next interceptor or to the transport
+type Invoker func(ctx context.Context, req *Request) error
+
+// Interceptor intercepts the requests of the client. It must call next
+// to continue with the request and it can modify the context (i.e. to
+// add headers with transport.ContextWithHeaders) before doing so.
+type Interceptor func(ctx context.Context, req *Request, next Invoker) error
+
+func WithInterceptors(interceptors ...Interceptor) ConfigOption {
+	return func(c *Config) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// chainInterceptors returns an invoker that calls the interceptors in
+// order before the final invoker
+func chainInterceptors(interceptors []Interceptor, final Invoker) Invoker {
+	invoker := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, req *Request) error {
+			return interceptor(ctx, req, next)
+		}
+	}
+	return invoker
+}
+
+// LogInterceptor logs each request with its methods, latency and error
+func LogInterceptor(logger *log.Logger) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) error {
+		err := next(ctx, req)
+
+		if req.Batch == nil {
+			if err != nil {
+				logger.Printf("jsonrpc request method=%s duration=%s err=%q", req.Method, req.Duration, err)
+			} else {
+				logger.Printf("jsonrpc request method=%s duration=%s size=%d", req.Method, req.Duration, len(req.Response))
+			}
+			return err
+		}
+
+		failed := 0
+		for _, elem := range req.Batch {
+			if elem.Error != nil {
+				failed++
+			}
+		}
+		if err != nil {
+			logger.Printf("jsonrpc batch requests=%d duration=%s err=%q", len(req.Batch), req.Duration, err)
+		} else {
+			logger.Printf("jsonrpc batch requests=%d duration=%s failed=%d", len(req.Batch), req.Duration, failed)
+		}
+		return err
+	}
+}
+
+// Tracer starts a span for each request of the client
+type Tracer interface {
+	// Start starts the span of the request. The returned context is used to
+	// send the request and it can include the headers that propagate the span
+	// (see transport.ContextWithHeaders). The returned function ends the span
+	// with the result of the request.
+	Start(ctx context.Context, req *Request) (context.Context, func(err error))
+}
+
+// TracingInterceptor creates a span with the tracer for each request
+func TracingInterceptor(tracer Tracer) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) error {
+		ctx, end := tracer.Start(ctx, req)
+		err := next(ctx, req)
+		end(err)
+		return err
+	}
+}
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram of the metrics
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// MethodStats are the metrics of a jsonrpc method
+type MethodStats struct {
+	// Calls is the number of requests of the method
+	Calls uint64
+
+	// Errors is the number of requests that failed
+	Errors uint64
+
+	// TotalLatency is the sum of the latency of all the requests
+	TotalLatency time.Duration
+
+	// Buckets is the number of requests with a latency lower or equal than
+	// each of the bounds of the metrics. The last element counts the requests
+	// above the highest bound.
+	Buckets []uint64
+}
+
+// AvgLatency returns the average latency of the requests
+func (m *MethodStats) AvgLatency() time.Duration {
+	if m.Calls == 0 {
+		return 0
+	}
+	return m.TotalLatency / time.Duration(m.Calls)
+}
+
+// Metrics counts the requests and measures the latency of each method.
+// Batch requests count once for each element with the latency of the batch.
+type Metrics struct {
+	bounds []time.Duration
+
+	lock    sync.Mutex
+	methods map[string]*MethodStats
+}
+
+// NewMetrics creates the metrics with the given latency buckets
+// or with DefaultLatencyBuckets if none is set
+func NewMetrics(bounds ...time.Duration) *Metrics {
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBuckets
+	}
+	bounds = append([]time.Duration{}, bounds...)
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i] < bounds[j]
+	})
+	return &Metrics{
+		bounds:  bounds,
+		methods: map[string]*MethodStats{},
+	}
+}
+
+// Bounds returns the upper bounds of the latency buckets
+func (m *Metrics) Bounds() []time.Duration {
+	return append([]time.Duration{}, m.bounds...)
+}
+
+// Interceptor returns the interceptor that records the metrics
+func (m *Metrics) Interceptor() Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) error {
+		err := next(ctx, req)
+
+		if req.Batch == nil {
+			m.record(req.Method, req.Duration, err != nil)
+			return err
+		}
+		for _, elem := range req.Batch {
+			m.record(elem.Method, req.Duration, err != nil || elem.Error != nil)
+		}
+		return err
+	}
+}
+
+func (m *Metrics) record(method string, latency time.Duration, failed bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	stats, ok := m.methods[method]
+	if !ok {
+		stats = &MethodStats{
+			Buckets: make([]uint64, len(m.bounds)+1),
+		}
+		m.methods[method] = stats
+	}
+	stats.Calls++
+	if failed {
+		stats.Errors++
+	}
+	stats.TotalLatency += latency
+
+	indx := sort.Search(len(m.bounds), func(i int) bool {
+		return latency <= m.bounds[i]
+	})
+	stats.Buckets[indx]++
+}
+
+// Stats returns a snapshot of the metrics of each method
+func (m *Metrics) Stats() map[string]MethodStats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	res := make(map[string]MethodStats, len(m.methods))
+	for method, stats := range m.methods {
+		snapshot := *stats
+		snapshot.Buckets = append([]uint64{}, stats.Buckets...)
+		res[method] = snapshot
+	}
+	return res
+}
+
+// Reset clears the metrics
+func (m *Metrics) Reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.methods = map[string]*MethodStats{}
+}
@@ -0,0 +1,228 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+	"github.com/umbracle/ethgo/jsonrpc/transport"
+)
+
+// newInterceptorServer returns an http server that replies the eth_chainId
+// requests and fails any other method. It records the traceparent header.
+func newInterceptorServer(t *testing.T) (*httptest.Server, func() []string) {
+	var lock sync.Mutex
+	traces := []string{}
+
+	reply := func(req *codec.Request) *codec.Response {
+		resp := &codec.Response{ID: req.ID}
+		if req.Method == "eth_chainId" {
+			resp.Result = json.RawMessage(`"0x1"`)
+		} else {
+			resp.Error = &codec.ErrorObject{Code: -32601, Message: "method not found"}
+		}
+		return resp
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		traces = append(traces, r.Header.Get("traceparent"))
+		lock.Unlock()
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var res interface{}
+		if bytes.HasPrefix(body, []byte("[")) {
+			var reqs []*codec.Request
+			assert.NoError(t, json.Unmarshal(body, &reqs))
+
+			resps := []*codec.Response{}
+			for _, req := range reqs {
+				resps = append(resps, reply(req))
+			}
+			res = resps
+		} else {
+			var req codec.Request
+			assert.NoError(t, json.Unmarshal(body, &req))
+			res = reply(&req)
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+
+	getTraces := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string{}, traces...)
+	}
+	return srv, getTraces
+}
+
+func TestInterceptor_Chain(t *testing.T) {
+	srv, _ := newInterceptorServer(t)
+	defer srv.Close()
+
+	order := []string{}
+	var last *Request
+
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, req *Request, next Invoker) error {
+			order = append(order, name)
+			err := next(ctx, req)
+			order = append(order, name)
+			last = req
+			return err
+		}
+	}
+
+	c, err := NewClient(srv.URL, WithInterceptors(record("a"), record("b")))
+	assert.NoError(t, err)
+
+	id, err := c.Eth().ChainID()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), id.Uint64())
+
+	assert.Equal(t, []string{"a", "b", "b", "a"}, order)
+	assert.Equal(t, "eth_chainId", last.Method)
+	assert.Equal(t, `"0x1"`, string(last.Response))
+	assert.NotZero(t, last.Duration)
+
+	// the interceptor can abort the request
+	abort := errors.New("abort")
+	c = NewClientWithTransport(c.transport, WithInterceptors(func(ctx context.Context, req *Request, next Invoker) error {
+		return abort
+	}))
+	_, err = c.Eth().ChainID()
+	assert.Equal(t, abort, err)
+}
+
+func TestInterceptor_Metrics(t *testing.T) {
+	srv, _ := newInterceptorServer(t)
+	defer srv.Close()
+
+	metrics := NewMetrics()
+	c, err := NewClient(srv.URL, WithInterceptors(metrics.Interceptor()))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = c.Eth().ChainID()
+		assert.NoError(t, err)
+	}
+	_, err = c.Eth().BlockNumber()
+	assert.Error(t, err)
+
+	var num string
+	var chainID string
+	elems := []*BatchElem{
+		{Method: "eth_chainId", Result: &chainID},
+		{Method: "eth_blockNumber", Result: &num},
+	}
+	assert.NoError(t, c.BatchCall(elems))
+	assert.Equal(t, "0x1", chainID)
+
+	stats := metrics.Stats()
+	assert.Len(t, stats, 2)
+
+	chainStats := stats["eth_chainId"]
+	assert.Equal(t, uint64(4), chainStats.Calls)
+	assert.Equal(t, uint64(0), chainStats.Errors)
+	assert.Len(t, chainStats.Buckets, len(DefaultLatencyBuckets)+1)
+
+	total := uint64(0)
+	for _, count := range chainStats.Buckets {
+		total += count
+	}
+	assert.Equal(t, chainStats.Calls, total)
+
+	numStats := stats["eth_blockNumber"]
+	assert.Equal(t, uint64(2), numStats.Calls)
+	assert.Equal(t, uint64(2), numStats.Errors)
+
+	metrics.Reset()
+	assert.Len(t, metrics.Stats(), 0)
+}
+
+func TestInterceptor_MetricsBuckets(t *testing.T) {
+	metrics := NewMetrics(100*time.Millisecond, 10*time.Millisecond)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}, metrics.Bounds())
+
+	metrics.record("a", 5*time.Millisecond, false)
+	metrics.record("a", 10*time.Millisecond, false)
+	metrics.record("a", 50*time.Millisecond, false)
+	metrics.record("a", time.Second, true)
+
+	stats := metrics.Stats()["a"]
+	assert.Equal(t, []uint64{2, 1, 1}, stats.Buckets)
+	assert.Equal(t, uint64(1), stats.Errors)
+	assert.Equal(t, 1065*time.Millisecond/4, stats.AvgLatency())
+}
+
+func TestInterceptor_Log(t *testing.T) {
+	srv, _ := newInterceptorServer(t)
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	c, err := NewClient(srv.URL, WithInterceptors(LogInterceptor(logger)))
+	assert.NoError(t, err)
+
+	_, err = c.Eth().ChainID()
+	assert.NoError(t, err)
+	_, err = c.Eth().BlockNumber()
+	assert.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "method=eth_chainId")
+	assert.Contains(t, lines[1], "method=eth_blockNumber")
+	assert.Contains(t, lines[1], "method not found")
+}
+
+type mockTracer struct {
+	spans []string
+	ended []error
+}
+
+func (m *mockTracer) Start(ctx context.Context, req *Request) (context.Context, func(err error)) {
+	m.spans = append(m.spans, strings.Join(req.Methods(), ","))
+	ctx = transport.ContextWithHeaders(ctx, map[string]string{
+		"traceparent": "00-trace-span-01",
+	})
+	return ctx, func(err error) {
+		m.ended = append(m.ended, err)
+	}
+}
+
+func TestInterceptor_Tracing(t *testing.T) {
+	srv, traces := newInterceptorServer(t)
+	defer srv.Close()
+
+	tracer := &mockTracer{}
+	c, err := NewClient(srv.URL, WithInterceptors(TracingInterceptor(tracer)))
+	assert.NoError(t, err)
+
+	_, err = c.Eth().ChainID()
+	assert.NoError(t, err)
+
+	var chainID string
+	assert.NoError(t, c.BatchCall([]*BatchElem{
+		{Method: "eth_chainId", Result: &chainID},
+		{Method: "eth_chainId", Result: &chainID},
+	}))
+
+	assert.Equal(t, []string{"eth_chainId", "eth_chainId,eth_chainId"}, tracer.spans)
+	assert.Equal(t, []error{nil, nil}, tracer.ended)
+	assert.Equal(t, []string{"00-trace-span-01", "00-trace-span-01"}, traces())
+}
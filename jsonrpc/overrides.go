@@ -0,0 +1,32 @@
+package jsonrpc
+
+import (
+	"github.com/umbracle/ethgo"
+)
+
+// OverrideAccount replaces the state of an account during the execution of a call.
+// State replaces the whole storage of the account while StateDiff only replaces
+// the given slots, they cannot be used at the same time.
+type OverrideAccount struct {
+	Nonce            *ethgo.ArgUint64          `json:"nonce,omitempty"`
+	Code             *ethgo.ArgBytes           `json:"code,omitempty"`
+	Balance          *ethgo.ArgBig             `json:"balance,omitempty"`
+	State            map[ethgo.Hash]ethgo.Hash `json:"state,omitempty"`
+	StateDiff        map[ethgo.Hash]ethgo.Hash `json:"stateDiff,omitempty"`
+	MovePrecompileTo *ethgo.Address            `json:"movePrecompileToAddress,omitempty"`
+}
+
+// StateOverride is the set of accounts replaced during the execution of a call
+type StateOverride map[ethgo.Address]*OverrideAccount
+
+// BlockOverrides replaces the fields of the block in which a call is executed
+type BlockOverrides struct {
+	Number        *ethgo.ArgBig    `json:"number,omitempty"`
+	Difficulty    *ethgo.ArgBig    `json:"difficulty,omitempty"`
+	Time          *ethgo.ArgUint64 `json:"time,omitempty"`
+	GasLimit      *ethgo.ArgUint64 `json:"gasLimit,omitempty"`
+	FeeRecipient  *ethgo.Address   `json:"feeRecipient,omitempty"`
+	PrevRandao    *ethgo.Hash      `json:"prevRandao,omitempty"`
+	BaseFeePerGas *ethgo.ArgBig    `json:"baseFeePerGas,omitempty"`
+	BlobBaseFee   *ethgo.ArgBig    `json:"blobBaseFee,omitempty"`
+}
@@ -0,0 +1,126 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+// SimulateBlock is a block simulated with eth_simulateV1. The calls are
+// executed in order on top of the state of the previous simulated block.
+type SimulateBlock struct {
+	BlockOverrides *BlockOverrides  `json:"blockOverrides,omitempty"`
+	StateOverrides StateOverride    `json:"stateOverrides,omitempty"`
+	Calls          []*ethgo.CallMsg `json:"calls"`
+}
+
+// SimulateConfig are the optional params of eth_simulateV1
+type SimulateConfig struct {
+	// TraceTransfers adds the ether transfers as ERC20 logs
+	// from the 0xeeee...eeee address
+	TraceTransfers bool
+
+	// Validation runs the same checks as a normal transaction
+	// (i.e. nonce, balance and base fee)
+	Validation bool
+
+	// ReturnFullTransactions returns the transactions of
+	// the simulated blocks instead of their hashes
+	ReturnFullTransactions bool
+}
+
+type SimulateOption func(*SimulateConfig)
+
+// WithTraceTransfers adds the ether transfers to the logs of the calls
+func WithTraceTransfers() SimulateOption {
+	return func(c *SimulateConfig) {
+		c.TraceTransfers = true
+	}
+}
+
+// WithValidation validates the calls as if they were transactions
+func WithValidation() SimulateOption {
+	return func(c *SimulateConfig) {
+		c.Validation = true
+	}
+}
+
+// WithReturnFullTransactions returns the transactions of the simulated blocks
+func WithReturnFullTransactions() SimulateOption {
+	return func(c *SimulateConfig) {
+		c.ReturnFullTransactions = true
+	}
+}
+
+type simulatePayload struct {
+	BlockStateCalls        []*SimulateBlock `json:"blockStateCalls"`
+	TraceTransfers         bool             `json:"traceTransfers,omitempty"`
+	Validation             bool             `json:"validation,omitempty"`
+	ReturnFullTransactions bool             `json:"returnFullTransactions,omitempty"`
+}
+
+// SimulatedBlock is the result of a simulated block
+type SimulatedBlock struct {
+	*ethgo.Block
+	Calls []*SimulatedCall
+}
+
+// UnmarshalJSON implements the unmarshal interface
+func (s *SimulatedBlock) UnmarshalJSON(data []byte) error {
+	block := new(ethgo.Block)
+	if err := json.Unmarshal(data, block); err != nil {
+		return err
+	}
+	var res struct {
+		Calls []*SimulatedCall `json:"calls"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	s.Block = block
+	s.Calls = res.Calls
+	return nil
+}
+
+// SimulatedCall is the result of a simulated call
+type SimulatedCall struct {
+	ReturnData ethgo.ArgBytes     `json:"returnData"`
+	Logs       []*ethgo.Log       `json:"logs"`
+	GasUsed    ethgo.ArgUint64    `json:"gasUsed"`
+	Status     ethgo.ArgUint64    `json:"status"`
+	Error      *codec.ErrorObject `json:"error,omitempty"`
+}
+
+// Err returns the error of the call if it failed. A reverted
+// call can be checked with errors.As and a codec.RevertError.
+func (s *SimulatedCall) Err() error {
+	if s.Status == 1 {
+		return nil
+	}
+	if s.Error != nil {
+		return s.Error
+	}
+	return codec.NewRevertError(s.ReturnData)
+}
+
+// SimulateV1 executes the calls in a sequence of blocks on top of the state of the
+// given block without creating any transaction (eth_simulateV1)
+func (e *Eth) SimulateV1(blocks []*SimulateBlock, block ethgo.BlockNumberOrHash, opts ...SimulateOption) ([]*SimulatedBlock, error) {
+	config := &SimulateConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	payload := &simulatePayload{
+		BlockStateCalls:        blocks,
+		TraceTransfers:         config.TraceTransfers,
+		Validation:             config.Validation,
+		ReturnFullTransactions: config.ReturnFullTransactions,
+	}
+
+	var out []*SimulatedBlock
+	if err := e.c.CallContext(e.ctx, "eth_simulateV1", &out, payload, blockParam(block)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
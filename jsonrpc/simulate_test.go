@@ -0,0 +1,177 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+func TestEth_CallOverrides(t *testing.T) {
+	srv, params := newStubServer(t, map[string]string{
+		"eth_call":        `"0x01"`,
+		"eth_estimateGas": `"0x5208"`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	msg := &ethgo.CallMsg{From: addr0, To: &addr1}
+
+	// without overrides only the message and the block are sent
+	_, err := c.Eth().Call(msg, ethgo.Latest)
+	assert.NoError(t, err)
+
+	var raw []json.RawMessage
+	assert.NoError(t, json.Unmarshal(params["eth_call"], &raw))
+	assert.Len(t, raw, 2)
+	assert.Equal(t, `"latest"`, string(raw[1]))
+
+	// eip-1898 block selector
+	_, err = c.Eth().Call(msg, ethgo.BlockHashSelector{Hash: ethgo.Hash{0x1}, RequireCanonical: true})
+	assert.NoError(t, err)
+
+	assert.NoError(t, json.Unmarshal(params["eth_call"], &raw))
+	assert.JSONEq(t, `{"blockHash": "0x0100000000000000000000000000000000000000000000000000000000000000", "requireCanonical": true}`, string(raw[1]))
+
+	// block overrides without state overrides
+	num := ethgo.ArgBig(*big.NewInt(16))
+	_, err = c.Eth().Call(msg, ethgo.Hash{0x1}, WithBlockOverrides(&BlockOverrides{Number: &num}))
+	assert.NoError(t, err)
+
+	assert.NoError(t, json.Unmarshal(params["eth_call"], &raw))
+	assert.Len(t, raw, 4)
+	assert.Equal(t, `"0x0100000000000000000000000000000000000000000000000000000000000000"`, string(raw[1]))
+	assert.Equal(t, `null`, string(raw[2]))
+	assert.JSONEq(t, `{"number": "0x10"}`, string(raw[3]))
+
+	// state overrides
+	nonce := ethgo.ArgUint64(1)
+	code := ethgo.ArgBytes{0x60, 0x00}
+	overrides := StateOverride{
+		addr1: {
+			Nonce:     &nonce,
+			Code:      &code,
+			StateDiff: map[ethgo.Hash]ethgo.Hash{{}: {0x1}},
+		},
+	}
+	_, err = c.Eth().Call(msg, ethgo.Latest, WithStateOverrides(overrides))
+	assert.NoError(t, err)
+
+	assert.NoError(t, json.Unmarshal(params["eth_call"], &raw))
+	assert.Len(t, raw, 3)
+	assert.JSONEq(t, `{"0x0200000000000000000000000000000000000000": {
+		"nonce": "0x1",
+		"code": "0x6000",
+		"stateDiff": {"0x0000000000000000000000000000000000000000000000000000000000000000": "0x0100000000000000000000000000000000000000000000000000000000000000"}
+	}}`, string(raw[2]))
+
+	// estimate gas does not send the block overrides
+	gas, err := c.Eth().EstimateGasAt(msg, ethgo.Pending, WithStateOverrides(overrides), WithBlockOverrides(&BlockOverrides{Number: &num}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(21000), gas)
+
+	assert.NoError(t, json.Unmarshal(params["eth_estimateGas"], &raw))
+	assert.Len(t, raw, 3)
+	assert.Equal(t, `"pending"`, string(raw[1]))
+}
+
+func TestEth_SimulateV1(t *testing.T) {
+	srv, params := newStubServer(t, map[string]string{
+		"eth_simulateV1": `[{
+			"number": "0x11",
+			"hash": "0x0100000000000000000000000000000000000000000000000000000000000000",
+			"parentHash": "0x0200000000000000000000000000000000000000000000000000000000000000",
+			"sha3Uncles": "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"transactionsRoot": "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"stateRoot": "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"receiptsRoot": "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"miner": "0x0000000000000000000000000000000000000000",
+			"difficulty": "0x0",
+			"extraData": "0x",
+			"gasLimit": "0x1c9c380",
+			"gasUsed": "0xa410",
+			"timestamp": "0x10",
+			"transactions": ["0x0300000000000000000000000000000000000000000000000000000000000000"],
+			"uncles": [],
+			"calls": [
+				{
+					"returnData": "0x01",
+					"logs": [{
+						"address": "0x0200000000000000000000000000000000000000",
+						"topics": ["0x0000000000000000000000000000000000000000000000000000000000000001"],
+						"data": "0x",
+						"blockNumber": "0x11",
+						"transactionHash": "0x0300000000000000000000000000000000000000000000000000000000000000",
+						"transactionIndex": "0x0",
+						"blockHash": "0x0100000000000000000000000000000000000000000000000000000000000000",
+						"logIndex": "0x0",
+						"removed": false
+					}],
+					"gasUsed": "0x5208",
+					"status": "0x1"
+				},
+				{
+					"returnData": "0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000a6e6f7420656e6f75676800000000000000000000000000000000000000000000",
+					"logs": [],
+					"gasUsed": "0x5208",
+					"status": "0x0",
+					"error": {"code": 3, "message": "execution reverted: not enough", "data": "0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000a6e6f7420656e6f75676800000000000000000000000000000000000000000000"}
+				}
+			]
+		}]`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	blocks := []*SimulateBlock{
+		{
+			StateOverrides: StateOverride{addr0: {Balance: &ethgo.ArgBig{}}},
+			Calls: []*ethgo.CallMsg{
+				{From: addr0, To: &addr1},
+				{From: addr0, To: &addr1, Data: []byte{0x1}},
+			},
+		},
+	}
+	res, err := c.Eth().SimulateV1(blocks, ethgo.Latest, WithTraceTransfers(), WithValidation())
+	assert.NoError(t, err)
+
+	var raw []json.RawMessage
+	assert.NoError(t, json.Unmarshal(params["eth_simulateV1"], &raw))
+	assert.Equal(t, `"latest"`, string(raw[1]))
+
+	var payload map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(raw[0], &payload))
+	assert.Equal(t, `true`, string(payload["traceTransfers"]))
+	assert.Equal(t, `true`, string(payload["validation"]))
+	assert.NotContains(t, payload, "returnFullTransactions")
+	assert.Contains(t, string(payload["blockStateCalls"]), `"stateOverrides":{"0x0100000000000000000000000000000000000000":{"balance":"0x0"}}`)
+
+	assert.Len(t, res, 1)
+	block := res[0]
+	assert.Equal(t, uint64(17), block.Number)
+	assert.Equal(t, uint64(42000), block.GasUsed)
+	assert.Equal(t, []ethgo.Hash{{0x3}}, block.TransactionsHashes)
+	assert.Len(t, block.Calls, 2)
+
+	call := block.Calls[0]
+	assert.NoError(t, call.Err())
+	assert.Equal(t, ethgo.ArgBytes{0x1}, call.ReturnData)
+	assert.Equal(t, uint64(21000), call.GasUsed.Uint64())
+	assert.Len(t, call.Logs, 1)
+	assert.Equal(t, addr1, call.Logs[0].Address)
+
+	var revert *codec.RevertError
+	assert.True(t, errors.As(block.Calls[1].Err(), &revert))
+	assert.Equal(t, "not enough", revert.Reason)
+
+	// a failed call without error object returns the revert data
+	failed := &SimulatedCall{ReturnData: ethgo.ArgBytes{0x1}}
+	assert.True(t, errors.As(failed.Err(), &revert))
+	assert.Equal(t, []byte{0x1}, revert.Data)
+}
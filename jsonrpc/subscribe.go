@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/umbracle/ethgo/jsonrpc/transport"
@@ -8,16 +9,37 @@ import (
 
 // SubscriptionEnabled returns true if the subscription endpoints are enabled
 func (c *Client) SubscriptionEnabled() bool {
-	_, ok := c.transport.(transport.PubSubTransport)
+	_, ok := c.pubsub.(transport.PubSubTransport)
 	return ok
 }
 
 // Subscribe starts a new subscription
 func (c *Client) Subscribe(method string, callback func(b []byte)) (func() error, error) {
-	pub, ok := c.transport.(transport.PubSubTransport)
+	pub, ok := c.pubsub.(transport.PubSubTransport)
 	if !ok {
 		return nil, fmt.Errorf("transport does not support the subscribe method")
 	}
 	close, err := pub.Subscribe(method, callback)
 	return close, err
 }
+
+// SubscribeWithOpts starts a new subscription with params. If the transport reconnects,
+// the subscription is started again and opts.Resumed is called since notifications
+// may have been lost while disconnected. If the transport does not implement
+// transport.PubSubOptsTransport, only the subscriptions without params are started.
+func (c *Client) SubscribeWithOpts(ctx context.Context, opts *transport.SubscribeOpts) (func() error, error) {
+	if pub, ok := c.pubsub.(transport.PubSubOptsTransport); ok {
+		return pub.SubscribeWithOpts(ctx, opts)
+	}
+	pub, ok := c.pubsub.(transport.PubSubTransport)
+	if !ok {
+		return nil, fmt.Errorf("transport does not support the subscribe method")
+	}
+	if len(opts.Params) != 0 {
+		return nil, fmt.Errorf("transport does not support subscriptions with params")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pub.Subscribe(opts.Method, opts.Callback)
+}
@@ -1,15 +1,48 @@
 package jsonrpc
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/jsonrpc/transport"
 	"github.com/umbracle/ethgo/testutil"
 )
 
+// mockPubSub only implements the Subscribe method of the pubsub transports
+type mockPubSub struct {
+	transport.Transport
+	methods []string
+}
+
+func (m *mockPubSub) Subscribe(method string, callback func(b []byte)) (func() error, error) {
+	m.methods = append(m.methods, method)
+	return func() error { return nil }, nil
+}
+
+func TestSubscribeWithOpts_Fallback(t *testing.T) {
+	tr := &mockPubSub{}
+	c := NewClientWithTransport(tr)
+
+	_, err := c.SubscribeWithOpts(context.Background(), &transport.SubscribeOpts{
+		Method:   "newHeads",
+		Callback: func(b []byte) {},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"newHeads"}, tr.methods)
+
+	// the params cannot be sent with Subscribe
+	_, err = c.SubscribeWithOpts(context.Background(), &transport.SubscribeOpts{
+		Method: "logs",
+		Params: []interface{}{&ethgo.LogFilter{}},
+	})
+	assert.Error(t, err)
+	assert.Len(t, tr.methods, 1)
+}
+
 func TestSubscribeNewHead(t *testing.T) {
 	s := testutil.NewTestServer(t, nil)
 	defer s.Close()
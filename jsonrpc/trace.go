@@ -0,0 +1,415 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+)
+
+// Trace is the trace namespace of OpenEthereum (Parity) implemented by Erigon,
+// Nethermind and Reth
+type Trace struct {
+	c   *Client
+	ctx context.Context
+}
+
+// Trace returns the reference to the trace namespace
+func (c *Client) Trace() *Trace {
+	return c.endpoints.t
+}
+
+// WithContext returns a copy of the trace namespace whose requests
+// are aborted when the context is done
+func (t *Trace) WithContext(ctx context.Context) *Trace {
+	return &Trace{c: t.c, ctx: ctx}
+}
+
+// TraceType is the type of trace returned by trace_call and trace_replayTransaction
+type TraceType string
+
+const (
+	// TraceTypeTrace returns the tree of calls
+	TraceTypeTrace TraceType = "trace"
+
+	// TraceTypeVMTrace returns the opcodes executed
+	TraceTypeVMTrace TraceType = "vmTrace"
+
+	// TraceTypeStateDiff returns the changes in the state
+	TraceTypeStateDiff TraceType = "stateDiff"
+)
+
+// CallAction is the action of a call trace
+type CallAction struct {
+	CallType string          `json:"callType"`
+	From     ethgo.Address   `json:"from"`
+	To       ethgo.Address   `json:"to"`
+	Value    ethgo.ArgBig    `json:"value"`
+	Gas      ethgo.ArgUint64 `json:"gas"`
+	Input    ethgo.ArgBytes  `json:"input"`
+}
+
+// CreateAction is the action of a create trace
+type CreateAction struct {
+	From           ethgo.Address   `json:"from"`
+	Value          ethgo.ArgBig    `json:"value"`
+	Gas            ethgo.ArgUint64 `json:"gas"`
+	Init           ethgo.ArgBytes  `json:"init"`
+	CreationMethod string          `json:"creationMethod,omitempty"`
+}
+
+// SuicideAction is the action of a selfdestruct trace
+type SuicideAction struct {
+	Address       ethgo.Address `json:"address"`
+	RefundAddress ethgo.Address `json:"refundAddress"`
+	Balance       ethgo.ArgBig  `json:"balance"`
+}
+
+// RewardAction is the action of a block or uncle reward trace
+type RewardAction struct {
+	Author     ethgo.Address `json:"author"`
+	RewardType string        `json:"rewardType"`
+	Value      ethgo.ArgBig  `json:"value"`
+}
+
+// CallResult is the result of a call trace
+type CallResult struct {
+	GasUsed ethgo.ArgUint64 `json:"gasUsed"`
+	Output  ethgo.ArgBytes  `json:"output"`
+}
+
+// CreateResult is the result of a create trace
+type CreateResult struct {
+	GasUsed ethgo.ArgUint64 `json:"gasUsed"`
+	Code    ethgo.ArgBytes  `json:"code"`
+	Address ethgo.Address   `json:"address"`
+}
+
+// LocalizedTrace is a call, create, suicide or reward trace. The block and
+// transaction fields are not set in the results of trace_call and trace_replayTransaction.
+type LocalizedTrace struct {
+	Type string
+
+	// Action is either a *CallAction, *CreateAction, *SuicideAction or *RewardAction
+	Action interface{}
+
+	// Result is either a *CallResult or a *CreateResult. It is nil if the trace
+	// failed and for the suicide and reward traces.
+	Result interface{}
+
+	Error               string
+	Subtraces           uint64
+	TraceAddress        []uint64
+	BlockHash           *ethgo.Hash
+	BlockNumber         uint64
+	TransactionHash     *ethgo.Hash
+	TransactionPosition *uint64
+}
+
+type localizedTrace struct {
+	Type                string          `json:"type"`
+	Action              json.RawMessage `json:"action"`
+	Result              json.RawMessage `json:"result,omitempty"`
+	Error               string          `json:"error,omitempty"`
+	Subtraces           uint64          `json:"subtraces"`
+	TraceAddress        []uint64        `json:"traceAddress"`
+	BlockHash           *ethgo.Hash     `json:"blockHash,omitempty"`
+	BlockNumber         uint64          `json:"blockNumber,omitempty"`
+	TransactionHash     *ethgo.Hash     `json:"transactionHash,omitempty"`
+	TransactionPosition *uint64         `json:"transactionPosition,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (l *LocalizedTrace) UnmarshalJSON(data []byte) error {
+	var raw localizedTrace
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var result interface{}
+	switch raw.Type {
+	case "call":
+		l.Action, result = new(CallAction), new(CallResult)
+	case "create", "create2":
+		l.Action, result = new(CreateAction), new(CreateResult)
+	case "suicide", "selfdestruct":
+		l.Action = new(SuicideAction)
+	case "reward":
+		l.Action = new(RewardAction)
+	default:
+		return fmt.Errorf("unknown trace type '%s'", raw.Type)
+	}
+	if err := json.Unmarshal(raw.Action, l.Action); err != nil {
+		return fmt.Errorf("failed to decode %s action: %v", raw.Type, err)
+	}
+
+	l.Result = nil
+	if result != nil && len(raw.Result) != 0 && string(raw.Result) != "null" {
+		if err := json.Unmarshal(raw.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %v", raw.Type, err)
+		}
+		l.Result = result
+	}
+
+	l.Type = raw.Type
+	l.Error = raw.Error
+	l.Subtraces = raw.Subtraces
+	l.TraceAddress = raw.TraceAddress
+	l.BlockHash = raw.BlockHash
+	l.BlockNumber = raw.BlockNumber
+	l.TransactionHash = raw.TransactionHash
+	l.TransactionPosition = raw.TransactionPosition
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (l *LocalizedTrace) MarshalJSON() ([]byte, error) {
+	raw := localizedTrace{
+		Type:                l.Type,
+		Error:               l.Error,
+		Subtraces:           l.Subtraces,
+		TraceAddress:        l.TraceAddress,
+		BlockHash:           l.BlockHash,
+		BlockNumber:         l.BlockNumber,
+		TransactionHash:     l.TransactionHash,
+		TransactionPosition: l.TransactionPosition,
+	}
+	var err error
+	if raw.Action, err = json.Marshal(l.Action); err != nil {
+		return nil, err
+	}
+	if l.Result != nil {
+		if raw.Result, err = json.Marshal(l.Result); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// VMTrace is the trace of the opcodes executed by a call
+type VMTrace struct {
+	Code ethgo.ArgBytes `json:"code"`
+	Ops  []*VMOperation `json:"ops"`
+}
+
+// VMOperation is an opcode executed. Sub is the trace of the
+// call or create executed by the opcode.
+type VMOperation struct {
+	Pc   uint64               `json:"pc"`
+	Op   string               `json:"op,omitempty"`
+	Cost uint64               `json:"cost"`
+	Ex   *VMExecutedOperation `json:"ex"`
+	Sub  *VMTrace             `json:"sub"`
+}
+
+// VMExecutedOperation are the changes of an opcode executed
+type VMExecutedOperation struct {
+	Used  uint64          `json:"used"`
+	Push  []*ethgo.ArgBig `json:"push"`
+	Mem   *VMMemoryDiff   `json:"mem"`
+	Store *VMStorageDiff  `json:"store"`
+}
+
+// VMMemoryDiff is a write in the memory
+type VMMemoryDiff struct {
+	Off  uint64         `json:"off"`
+	Data ethgo.ArgBytes `json:"data"`
+}
+
+// VMStorageDiff is a write in the storage
+type VMStorageDiff struct {
+	Key ethgo.ArgBig `json:"key"`
+	Val ethgo.ArgBig `json:"val"`
+}
+
+// Kinds of changes in a state diff
+const (
+	DiffSame    = "="
+	DiffBorn    = "+"
+	DiffDied    = "-"
+	DiffChanged = "*"
+)
+
+// Diff is the change of a value in the state. From is not set if the value
+// is created (DiffBorn) and To is not set if the value is removed (DiffDied).
+type Diff struct {
+	Kind string
+	From ethgo.ArgBytes
+	To   ethgo.ArgBytes
+}
+
+// FromInt returns the previous value as an integer (i.e. for the balance and the nonce)
+func (d *Diff) FromInt() *big.Int {
+	return new(big.Int).SetBytes(d.From)
+}
+
+// ToInt returns the new value as an integer (i.e. for the balance and the nonce)
+func (d *Diff) ToInt() *big.Int {
+	return new(big.Int).SetBytes(d.To)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (d *Diff) UnmarshalJSON(data []byte) error {
+	var same string
+	if err := json.Unmarshal(data, &same); err == nil {
+		if same != DiffSame {
+			return fmt.Errorf("unknown diff '%s'", same)
+		}
+		*d = Diff{Kind: DiffSame}
+		return nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 1 {
+		return fmt.Errorf("diff must have one kind but found %d", len(raw))
+	}
+	for kind, value := range raw {
+		*d = Diff{Kind: kind}
+		switch kind {
+		case DiffBorn:
+			return json.Unmarshal(value, &d.To)
+		case DiffDied:
+			return json.Unmarshal(value, &d.From)
+		case DiffChanged:
+			var changed struct {
+				From ethgo.ArgBytes `json:"from"`
+				To   ethgo.ArgBytes `json:"to"`
+			}
+			if err := json.Unmarshal(value, &changed); err != nil {
+				return err
+			}
+			d.From, d.To = changed.From, changed.To
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown diff kind in %s", string(data))
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (d *Diff) MarshalJSON() ([]byte, error) {
+	switch d.Kind {
+	case DiffSame:
+		return json.Marshal(DiffSame)
+	case DiffBorn:
+		return json.Marshal(map[string]ethgo.ArgBytes{DiffBorn: d.To})
+	case DiffDied:
+		return json.Marshal(map[string]ethgo.ArgBytes{DiffDied: d.From})
+	case DiffChanged:
+		return json.Marshal(map[string]map[string]ethgo.ArgBytes{DiffChanged: {"from": d.From, "to": d.To}})
+	}
+	return nil, fmt.Errorf("unknown diff kind '%s'", d.Kind)
+}
+
+// AccountDiff are the changes of an account in the state
+type AccountDiff struct {
+	Balance *Diff                `json:"balance"`
+	Nonce   *Diff                `json:"nonce"`
+	Code    *Diff                `json:"code"`
+	Storage map[ethgo.Hash]*Diff `json:"storage"`
+}
+
+// TraceResults is the result of trace_call and trace_replayTransaction. Only
+// the fields of the requested trace types are set.
+type TraceResults struct {
+	Output          ethgo.ArgBytes                 `json:"output"`
+	Trace           []*LocalizedTrace              `json:"trace"`
+	VMTrace         *VMTrace                       `json:"vmTrace"`
+	StateDiff       map[ethgo.Address]*AccountDiff `json:"stateDiff"`
+	TransactionHash *ethgo.Hash                    `json:"transactionHash,omitempty"`
+}
+
+// TraceFilter is the filter of trace_filter. The traces match any of the from addresses
+// and any of the to addresses. After and Count paginate the traces matched.
+type TraceFilter struct {
+	FromBlock   *ethgo.BlockNumber
+	ToBlock     *ethgo.BlockNumber
+	FromAddress []ethgo.Address
+	ToAddress   []ethgo.Address
+	After       *uint64
+	Count       *uint64
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (f *TraceFilter) MarshalJSON() ([]byte, error) {
+	obj := map[string]interface{}{}
+	if f.FromBlock != nil {
+		obj["fromBlock"] = f.FromBlock.String()
+	}
+	if f.ToBlock != nil {
+		obj["toBlock"] = f.ToBlock.String()
+	}
+	if len(f.FromAddress) != 0 {
+		obj["fromAddress"] = f.FromAddress
+	}
+	if len(f.ToAddress) != 0 {
+		obj["toAddress"] = f.ToAddress
+	}
+	if f.After != nil {
+		obj["after"] = *f.After
+	}
+	if f.Count != nil {
+		obj["count"] = *f.Count
+	}
+	return json.Marshal(obj)
+}
+
+// SetFromUint64 sets the first block of the filter
+func (f *TraceFilter) SetFromUint64(num uint64) {
+	b := ethgo.BlockNumber(num)
+	f.FromBlock = &b
+}
+
+// SetToUint64 sets the last block of the filter
+func (f *TraceFilter) SetToUint64(num uint64) {
+	b := ethgo.BlockNumber(num)
+	f.ToBlock = &b
+}
+
+func traceTypes(types []TraceType) []TraceType {
+	if len(types) == 0 {
+		return []TraceType{TraceTypeTrace}
+	}
+	return types
+}
+
+// Block returns the traces of all the transactions and rewards of the block
+func (t *Trace) Block(block ethgo.BlockNumber) ([]*LocalizedTrace, error) {
+	var res []*LocalizedTrace
+	err := t.c.CallContext(t.ctx, "trace_block", &res, block.String())
+	return res, err
+}
+
+// Transaction returns the traces of the transaction
+func (t *Trace) Transaction(hash ethgo.Hash) ([]*LocalizedTrace, error) {
+	var res []*LocalizedTrace
+	err := t.c.CallContext(t.ctx, "trace_transaction", &res, hash)
+	return res, err
+}
+
+// Filter returns the traces that match the filter
+func (t *Trace) Filter(filter *TraceFilter) ([]*LocalizedTrace, error) {
+	var res []*LocalizedTrace
+	err := t.c.CallContext(t.ctx, "trace_filter", &res, filter)
+	return res, err
+}
+
+// ReplayTransaction executes again the transaction and returns the trace types
+// requested (by default TraceTypeTrace)
+func (t *Trace) ReplayTransaction(hash ethgo.Hash, types ...TraceType) (*TraceResults, error) {
+	var res *TraceResults
+	err := t.c.CallContext(t.ctx, "trace_replayTransaction", &res, hash, traceTypes(types))
+	return res, err
+}
+
+// Call executes the call on top of the state of the block and returns the
+// trace types requested (by default TraceTypeTrace)
+func (t *Trace) Call(msg *ethgo.CallMsg, block ethgo.BlockNumberOrHash, types ...TraceType) (*TraceResults, error) {
+	var res *TraceResults
+	err := t.c.CallContext(t.ctx, "trace_call", &res, msg, traceTypes(types), blockParam(block))
+	return res, err
+}
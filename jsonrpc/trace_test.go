@@ -0,0 +1,191 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+)
+
+func TestTrace_Block(t *testing.T) {
+	srv, params := newStubServer(t, map[string]string{
+		"trace_block": `[
+			{
+				"action": {"callType": "call", "from": "0x0100000000000000000000000000000000000000", "to": "0x0200000000000000000000000000000000000000", "gas": "0x5208", "input": "0x", "value": "0x10"},
+				"blockHash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+				"blockNumber": 10,
+				"result": {"gasUsed": "0x0", "output": "0x"},
+				"subtraces": 1,
+				"traceAddress": [],
+				"transactionHash": "0x0000000000000000000000000000000000000000000000000000000000000002",
+				"transactionPosition": 0,
+				"type": "call"
+			},
+			{
+				"action": {"from": "0x0200000000000000000000000000000000000000", "gas": "0x100", "init": "0x6000", "value": "0x0"},
+				"error": "out of gas",
+				"result": null,
+				"subtraces": 0,
+				"traceAddress": [0],
+				"type": "create"
+			},
+			{
+				"action": {"address": "0x0200000000000000000000000000000000000000", "refundAddress": "0x0100000000000000000000000000000000000000", "balance": "0x1"},
+				"subtraces": 0,
+				"traceAddress": [1],
+				"type": "suicide"
+			},
+			{
+				"action": {"author": "0x0300000000000000000000000000000000000000", "rewardType": "block", "value": "0x1bc16d674ec80000"},
+				"blockNumber": 10,
+				"result": null,
+				"subtraces": 0,
+				"traceAddress": [],
+				"type": "reward"
+			}
+		]`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	traces, err := c.Trace().Block(ethgo.BlockNumber(10))
+	assert.NoError(t, err)
+	assert.Equal(t, `["0xa"]`, string(params["trace_block"]))
+	assert.Len(t, traces, 4)
+
+	call := traces[0]
+	assert.Equal(t, uint64(10), call.BlockNumber)
+	assert.Equal(t, uint64(0), *call.TransactionPosition)
+	assert.Equal(t, addr1, call.Action.(*CallAction).To)
+	assert.Equal(t, "16", (*big.Int)(&call.Action.(*CallAction).Value).String())
+	assert.NotNil(t, call.Result.(*CallResult))
+
+	create := traces[1]
+	assert.Equal(t, ethgo.ArgBytes{0x60, 0x00}, create.Action.(*CreateAction).Init)
+	assert.Equal(t, "out of gas", create.Error)
+	assert.Nil(t, create.Result)
+	assert.Equal(t, []uint64{0}, create.TraceAddress)
+
+	suicide := traces[2]
+	assert.Equal(t, addr0, suicide.Action.(*SuicideAction).RefundAddress)
+
+	reward := traces[3]
+	assert.Equal(t, "block", reward.Action.(*RewardAction).RewardType)
+	assert.Equal(t, "2000000000000000000", (*big.Int)(&reward.Action.(*RewardAction).Value).String())
+
+	// the traces can be encoded again
+	data, err := json.Marshal(traces)
+	assert.NoError(t, err)
+
+	var traces2 []*LocalizedTrace
+	assert.NoError(t, json.Unmarshal(data, &traces2))
+	assert.Equal(t, traces, traces2)
+}
+
+func TestTrace_Filter(t *testing.T) {
+	srv, params := newStubServer(t, map[string]string{
+		"trace_filter": `[]`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	count := uint64(100)
+	filter := &TraceFilter{
+		ToAddress: []ethgo.Address{addr0, addr1},
+		Count:     &count,
+	}
+	filter.SetFromUint64(1)
+	filter.SetToUint64(16)
+
+	traces, err := c.Trace().Filter(filter)
+	assert.NoError(t, err)
+	assert.Len(t, traces, 0)
+	assert.JSONEq(t, `[{
+		"fromBlock": "0x1",
+		"toBlock": "0x10",
+		"toAddress": ["0x0100000000000000000000000000000000000000", "0x0200000000000000000000000000000000000000"],
+		"count": 100
+	}]`, string(params["trace_filter"]))
+}
+
+func TestTrace_ReplayTransaction(t *testing.T) {
+	results := `{
+		"output": "0x01",
+		"trace": [{
+			"action": {"callType": "call", "from": "0x0100000000000000000000000000000000000000", "to": "0x0200000000000000000000000000000000000000", "gas": "0x5208", "input": "0x", "value": "0x0"},
+			"result": {"gasUsed": "0x10", "output": "0x01"},
+			"subtraces": 0,
+			"traceAddress": [],
+			"type": "call"
+		}],
+		"vmTrace": {
+			"code": "0x600160005500",
+			"ops": [
+				{"cost": 3, "ex": {"mem": null, "push": ["0x1"], "store": null, "used": 20997}, "pc": 0, "sub": null},
+				{"cost": 20000, "ex": {"mem": null, "push": [], "store": {"key": "0x0", "val": "0x1"}, "used": 997}, "pc": 4, "sub": null}
+			]
+		},
+		"stateDiff": {
+			"0x0100000000000000000000000000000000000000": {
+				"balance": {"*": {"from": "0x10", "to": "0x5"}},
+				"code": "=",
+				"nonce": {"*": {"from": "0x0", "to": "0x1"}},
+				"storage": {}
+			},
+			"0x0200000000000000000000000000000000000000": {
+				"balance": {"+": "0x0"},
+				"code": {"+": "0x6000"},
+				"nonce": {"+": "0x1"},
+				"storage": {"0x0000000000000000000000000000000000000000000000000000000000000000": {"+": "0x0000000000000000000000000000000000000000000000000000000000000001"}}
+			}
+		}
+	}`
+	srv, params := newStubServer(t, map[string]string{
+		"trace_replayTransaction": results,
+		"trace_call":              results,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	res, err := c.Trace().ReplayTransaction(ethgo.Hash{0x1})
+	assert.NoError(t, err)
+	assert.Equal(t, `["0x0100000000000000000000000000000000000000000000000000000000000000",["trace"]]`, string(params["trace_replayTransaction"]))
+	assert.Len(t, res.Trace, 1)
+
+	res, err = c.Trace().Call(&ethgo.CallMsg{From: addr0, To: &addr1}, ethgo.Latest, TraceTypeTrace, TraceTypeVMTrace, TraceTypeStateDiff)
+	assert.NoError(t, err)
+
+	var raw []json.RawMessage
+	assert.NoError(t, json.Unmarshal(params["trace_call"], &raw))
+	assert.Equal(t, `["trace","vmTrace","stateDiff"]`, string(raw[1]))
+	assert.Equal(t, `"latest"`, string(raw[2]))
+
+	assert.Equal(t, ethgo.ArgBytes{0x1}, res.Output)
+	assert.Len(t, res.VMTrace.Ops, 2)
+	assert.Equal(t, "1", (*big.Int)(res.VMTrace.Ops[0].Ex.Push[0]).String())
+	assert.Equal(t, "1", (*big.Int)(&res.VMTrace.Ops[1].Ex.Store.Val).String())
+
+	sender := res.StateDiff[addr0]
+	assert.Equal(t, DiffChanged, sender.Balance.Kind)
+	assert.Equal(t, "16", sender.Balance.FromInt().String())
+	assert.Equal(t, "5", sender.Balance.ToInt().String())
+	assert.Equal(t, DiffSame, sender.Code.Kind)
+
+	created := res.StateDiff[addr1]
+	assert.Equal(t, DiffBorn, created.Code.Kind)
+	assert.Equal(t, ethgo.ArgBytes{0x60, 0x00}, created.Code.To)
+	assert.Equal(t, "1", created.Storage[ethgo.Hash{}].ToInt().String())
+
+	// the diffs can be encoded again
+	data, err := json.Marshal(created)
+	assert.NoError(t, err)
+
+	var created2 *AccountDiff
+	assert.NoError(t, json.Unmarshal(data, &created2))
+	assert.Equal(t, created, created2)
+}
@@ -0,0 +1,226 @@
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+// newAuthServer returns an http and websocket server that replies
+// the Authorization header of the request (or the handshake)
+func newAuthServer(t *testing.T) *httptest.Server {
+	return httptest.NewUnstartedServer(authHandler(t))
+}
+
+func authHandler(t *testing.T) http.Handler {
+	upgrader := websocket.Upgrader{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "Bearer expired" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		result, _ := json.Marshal(authorization)
+
+		if r.Header.Get("Upgrade") != "websocket" {
+			var req codec.Request
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			json.NewEncoder(w).Encode(&codec.Response{ID: req.ID, Result: result})
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req codec.Request
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			conn.WriteJSON(&codec.Response{ID: req.ID, Result: result})
+		}
+	})
+}
+
+func callAuthorization(t *testing.T, url string, opts ...Option) string {
+	tt, err := NewTransport(url, nil, opts...)
+	assert.NoError(t, err)
+	defer tt.Close()
+
+	var out string
+	assert.NoError(t, tt.Call("eth_chainId", &out))
+	return out
+}
+
+func TestAuth_BasicFromURL(t *testing.T) {
+	srv := newAuthServer(t)
+	srv.Start()
+	defer srv.Close()
+
+	expected := "Basic " + "dXNlcjpwYXNz" // user:pass
+
+	for _, prefix := range []string{"http://", "ws://"} {
+		url := prefix + "user:pass@" + strings.TrimPrefix(srv.URL, "http://")
+		assert.Equal(t, expected, callAuthorization(t, url))
+
+		// an explicit auth has priority over the url
+		assert.Equal(t, "Bearer a", callAuthorization(t, url, WithAuth(NewStaticBearerAuth("a"))))
+	}
+}
+
+func TestAuth_BearerRefresh(t *testing.T) {
+	srv := newAuthServer(t)
+	srv.Start()
+	defer srv.Close()
+
+	now := time.Unix(1000, 0)
+	var count uint64
+
+	auth := NewBearerAuth(func(ctx context.Context) (string, time.Time, error) {
+		num := atomic.AddUint64(&count, 1)
+		return "token" + string(rune('0'+num)), now.Add(time.Minute), nil
+	})
+	auth.now = func() time.Time { return now }
+
+	tt, err := NewTransport(srv.URL, nil, WithAuth(auth))
+	assert.NoError(t, err)
+
+	var out string
+	assert.NoError(t, tt.Call("eth_chainId", &out))
+	assert.Equal(t, "Bearer token1", out)
+
+	// the token is reused until it is about to expire
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, tt.Call("eth_chainId", &out))
+	assert.Equal(t, "Bearer token1", out)
+
+	now = now.Add(25 * time.Second)
+	assert.NoError(t, tt.Call("eth_chainId", &out))
+	assert.Equal(t, "Bearer token2", out)
+
+	// the token is requested again if the server rejects it
+	tokens := []string{"expired", "fresh"}
+	auth = NewBearerAuth(func(ctx context.Context) (string, time.Time, error) {
+		token := tokens[0]
+		tokens = tokens[1:]
+		return token, time.Time{}, nil
+	})
+	tt, err = NewTransport(srv.URL, nil, WithAuth(auth))
+	assert.NoError(t, err)
+
+	assert.Error(t, tt.Call("eth_chainId", &out))
+	assert.NoError(t, tt.Call("eth_chainId", &out))
+	assert.Equal(t, "Bearer fresh", out)
+}
+
+func TestAuth_HTTPClient(t *testing.T) {
+	srv := newAuthServer(t)
+	srv.Start()
+	defer srv.Close()
+
+	var count uint64
+	client := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddUint64(&count, 1)
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+	callAuthorization(t, srv.URL, WithHTTPClient(client))
+	assert.Equal(t, uint64(1), atomic.LoadUint64(&count))
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// newTestCertificate creates a certificate signed by the parent (or self signed)
+func newTestCertificate(t *testing.T, name string, parent *tls.Certificate, server bool) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else if server {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		template.DNSNames = []string{"localhost"}
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	parentCert, parentKey := template, interface{}(key)
+	if parent != nil {
+		parentCert = parent.Leaf
+		parentKey = parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestAuth_ClientCertificate(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil, false)
+	serverCert := newTestCertificate(t, "server", &ca, true)
+	clientCert := newTestCertificate(t, "client", &ca, false)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	srv := newAuthServer(t)
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	addr := strings.TrimPrefix(srv.URL, "https://")
+	tlsConfig := &tls.Config{RootCAs: pool, ServerName: "localhost"}
+
+	for _, url := range []string{"https://" + addr, "wss://" + addr} {
+		// the server rejects the connection without a certificate
+		tt, err := NewTransport(url, nil, WithTLSConfig(tlsConfig))
+		if err == nil {
+			var out string
+			err = tt.Call("eth_chainId", &out)
+		}
+		assert.Error(t, err)
+
+		out := callAuthorization(t, url, WithTLSConfig(tlsConfig), WithAuth(&ClientCertAuth{Certificate: clientCert}))
+		assert.Equal(t, "", out)
+	}
+
+	// the tls config of the caller is not modified
+	assert.Len(t, tlsConfig.Certificates, 0)
+}
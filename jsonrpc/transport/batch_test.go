@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+// echoHandler replies to each request with its method name as result,
+// except for the 'fail' method which returns an error object
+func echoHandler(req *codec.Request) *codec.Response {
+	resp := &codec.Response{ID: req.ID}
+	if req.Method == "fail" {
+		resp.Error = &codec.ErrorObject{Code: -32000, Message: "failed"}
+	} else {
+		resp.Result, _ = json.Marshal(req.Method)
+	}
+	return resp
+}
+
+func handleRaw(t *testing.T, raw []byte) []byte {
+	var out interface{}
+	if strings.HasPrefix(string(raw), "[") {
+		var reqs []*codec.Request
+		assert.NoError(t, json.Unmarshal(raw, &reqs))
+
+		resps := []*codec.Response{}
+		for _, req := range reqs {
+			resps = append(resps, echoHandler(req))
+		}
+		out = resps
+	} else {
+		var req codec.Request
+		assert.NoError(t, json.Unmarshal(raw, &req))
+		out = echoHandler(&req)
+	}
+	data, err := json.Marshal(out)
+	assert.NoError(t, err)
+	return data
+}
+
+func testBatchCall(t *testing.T, tr Transport) {
+	var res1, res2 string
+	elems := []*BatchElem{
+		{Method: "a", Result: &res1},
+		{Method: "fail"},
+		{Method: "b", Params: []interface{}{1}, Result: &res2},
+	}
+	assert.NoError(t, tr.(BatchTransport).BatchCall(elems))
+
+	assert.NoError(t, elems[0].Error)
+	assert.Equal(t, "a", res1)
+
+	obj, ok := elems[1].Error.(*codec.ErrorObject)
+	assert.True(t, ok)
+	assert.Equal(t, -32000, obj.Code)
+
+	assert.NoError(t, elems[2].Error)
+	assert.Equal(t, "b", res2)
+}
+
+func TestBatchCall_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		w.Write(handleRaw(t, raw))
+	}))
+	defer srv.Close()
+
+	tr, err := NewTransport(srv.URL, nil)
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	testBatchCall(t, tr)
+}
+
+func TestBatchCall_Websocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, raw, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, handleRaw(t, raw)); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	tr, err := NewTransport("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	testBatchCall(t, tr)
+}
@@ -0,0 +1,484 @@
+package transport
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cacheRule int
+
+const (
+	// cacheAlways caches the result of the method since it is immutable
+	cacheAlways cacheRule = iota
+
+	// cacheBlockParam caches the result if the block selector param is a hash or a finalized number
+	cacheBlockParam
+
+	// cacheResultBlock caches the result if it is included in a finalized block
+	cacheResultBlock
+)
+
+type cacheMethod struct {
+	rule cacheRule
+
+	// param is the index of the block selector for the cacheBlockParam rule
+	param int
+
+	// memory is true if the response is only cached in memory since it
+	// identifies the chain of the node and not an immutable object
+	memory bool
+}
+
+// cacheMethods are the methods with immutable responses that can be cached
+var cacheMethods = map[string]cacheMethod{
+	"eth_chainId":                             {rule: cacheAlways, memory: true},
+	"net_version":                             {rule: cacheAlways, memory: true},
+	"eth_getBlockByHash":                      {rule: cacheAlways},
+	"eth_getBlockTransactionCountByHash":      {rule: cacheAlways},
+	"eth_getTransactionByBlockHashAndIndex":   {rule: cacheAlways},
+	"eth_getBlockByNumber":                    {rule: cacheBlockParam, param: 0},
+	"eth_getBlockTransactionCountByNumber":    {rule: cacheBlockParam, param: 0},
+	"eth_getTransactionByBlockNumberAndIndex": {rule: cacheBlockParam, param: 0},
+	"eth_getBalance":                          {rule: cacheBlockParam, param: 1},
+	"eth_getCode":                             {rule: cacheBlockParam, param: 1},
+	"eth_getTransactionCount":                 {rule: cacheBlockParam, param: 1},
+	"eth_call":                                {rule: cacheBlockParam, param: 1},
+	"eth_getStorageAt":                        {rule: cacheBlockParam, param: 2},
+	"eth_getProof":                            {rule: cacheBlockParam, param: 2},
+	"eth_getTransactionByHash":                {rule: cacheResultBlock},
+	"eth_getTransactionReceipt":               {rule: cacheResultBlock},
+}
+
+// CacheStore is a persistent store for the cached responses
+type CacheStore interface {
+	// Get returns the value of the key or nil if it does not exist
+	Get(key string) ([]byte, error)
+
+	// Put stores the value of the key
+	Put(key string, value []byte) error
+
+	// Close closes the store
+	Close() error
+}
+
+// CacheConfig is the configuration of the cache transport
+type CacheConfig struct {
+	// Size is the maximum number of responses kept in memory
+	Size int
+
+	// Store is an optional persistent store used after a miss in memory
+	Store CacheStore
+
+	// Namespace is the prefix of the keys in the persistent store. If it
+	// is empty, the chain id of the node is used.
+	Namespace string
+
+	// FinalizedDepth is the number of blocks behind the head that are considered
+	// final if the node does not support the finalized block tag
+	FinalizedDepth uint64
+
+	// FinalizedRefresh is the minimum time between queries for the finalized block
+	FinalizedRefresh time.Duration
+}
+
+// DefaultCacheConfig returns the default cache configuration
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{
+		Size:             10000,
+		FinalizedDepth:   64,
+		FinalizedRefresh: 12 * time.Second,
+	}
+}
+
+type CacheOption func(*CacheConfig)
+
+func WithCacheSize(size int) CacheOption {
+	return func(c *CacheConfig) {
+		c.Size = size
+	}
+}
+
+func WithCacheStore(store CacheStore) CacheOption {
+	return func(c *CacheConfig) {
+		c.Store = store
+	}
+}
+
+// WithCacheNamespace sets the prefix of the keys in the persistent store
+func WithCacheNamespace(namespace string) CacheOption {
+	return func(c *CacheConfig) {
+		c.Namespace = namespace
+	}
+}
+
+func WithFinalizedDepth(depth uint64) CacheOption {
+	return func(c *CacheConfig) {
+		c.FinalizedDepth = depth
+	}
+}
+
+func WithFinalizedRefresh(d time.Duration) CacheOption {
+	return func(c *CacheConfig) {
+		c.FinalizedRefresh = d
+	}
+}
+
+// CacheStats are the statistics of the cache
+type CacheStats struct {
+	// Hits is the number of requests served from the cache
+	Hits uint64
+
+	// Misses is the number of cacheable requests sent to the transport
+	Misses uint64
+
+	// Entries is the number of responses in memory
+	Entries int
+}
+
+type cacheEntry struct {
+	key   string
+	value []byte
+}
+
+// Cache is a transport that caches the responses that cannot change like
+// the queries by hash or by finalized block number. Queries with block
+// tags (i.e. latest or pending) are never cached.
+type Cache struct {
+	Transport
+
+	config *CacheConfig
+
+	lock  sync.Mutex
+	items map[string]*list.Element
+	lru   *list.List
+	stats CacheStats
+
+	finalizedLock sync.Mutex
+	finalized     uint64
+	lastRefresh   time.Time
+
+	namespaceLock sync.Mutex
+	namespace     string
+}
+
+// NewCache wraps the transport with a cache for the immutable responses
+func NewCache(t Transport, opts ...CacheOption) *Cache {
+	config := DefaultCacheConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return &Cache{
+		Transport: t,
+		config:    config,
+		items:     map[string]*list.Element{},
+		lru:       list.New(),
+		namespace: config.Namespace,
+	}
+}
+
+// Stats returns the statistics of the cache
+func (c *Cache) Stats() CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// Close closes the transport and the persistent store of the cache
+func (c *Cache) Close() error {
+	if c.config.Store != nil {
+		if err := c.config.Store.Close(); err != nil {
+			return err
+		}
+	}
+	return c.Transport.Close()
+}
+
+// Call implements the transport interface
+func (c *Cache) Call(method string, out interface{}, params ...interface{}) error {
+	return c.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext implements the transport interface
+func (c *Cache) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	key, ok := c.cacheKey(ctx, method, params)
+	if !ok {
+		return CallContext(ctx, c.Transport, method, out, params...)
+	}
+	if value, ok := c.get(ctx, method, key); ok {
+		return json.Unmarshal(value, out)
+	}
+
+	var raw json.RawMessage
+	if err := CallContext(ctx, c.Transport, method, &raw, params...); err != nil {
+		return err
+	}
+	c.put(ctx, method, key, raw)
+	return json.Unmarshal(raw, out)
+}
+
+// BatchCall implements the BatchTransport interface
+func (c *Cache) BatchCall(elems []*BatchElem) error {
+	return c.BatchCallContext(context.Background(), elems)
+}
+
+// BatchCallContext implements the BatchTransport interface. Only the
+// elements that are not cached are sent.
+func (c *Cache) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	batch, ok := c.Transport.(BatchTransport)
+	if !ok {
+		for _, elem := range elems {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			elem.Error = c.CallContext(ctx, elem.Method, elem.Result, elem.Params...)
+		}
+		return nil
+	}
+
+	keys := []string{}
+	pending := []*BatchElem{}
+	raws := []*BatchElem{}
+
+	for _, elem := range elems {
+		key, ok := c.cacheKey(ctx, elem.Method, elem.Params)
+		if ok {
+			if value, ok := c.get(ctx, elem.Method, key); ok {
+				elem.Error = json.Unmarshal(value, elem.Result)
+				continue
+			}
+		}
+		keys = append(keys, key)
+		pending = append(pending, elem)
+		raws = append(raws, &BatchElem{
+			Method: elem.Method,
+			Params: elem.Params,
+			Result: new(json.RawMessage),
+		})
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := batch.BatchCallContext(ctx, raws); err != nil {
+		return err
+	}
+	for indx, elem := range pending {
+		if raws[indx].Error != nil {
+			elem.Error = raws[indx].Error
+			continue
+		}
+		raw := *raws[indx].Result.(*json.RawMessage)
+		if keys[indx] != "" {
+			c.put(ctx, elem.Method, keys[indx], raw)
+		}
+		elem.Error = json.Unmarshal(raw, elem.Result)
+	}
+	return nil
+}
+
+// cacheKey returns the key of the request if it can be cached
+func (c *Cache) cacheKey(ctx context.Context, method string, params []interface{}) (string, bool) {
+	m, ok := cacheMethods[method]
+	if !ok {
+		return "", false
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", false
+	}
+	if m.rule == cacheBlockParam {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil || len(raw) <= m.param {
+			return "", false
+		}
+		if !c.isImmutableBlock(ctx, raw[m.param]) {
+			return "", false
+		}
+	}
+	return method + string(data), true
+}
+
+// isImmutableBlock returns true if the block selector is a hash or a finalized
+// block number (either as a string or as an EIP-1898 object)
+func (c *Cache) isImmutableBlock(ctx context.Context, raw json.RawMessage) bool {
+	var selector string
+	if err := json.Unmarshal(raw, &selector); err != nil {
+		var obj struct {
+			BlockHash   *string `json:"blockHash"`
+			BlockNumber *string `json:"blockNumber"`
+		}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return false
+		}
+		if obj.BlockHash != nil {
+			return true
+		}
+		if obj.BlockNumber == nil {
+			return false
+		}
+		selector = *obj.BlockNumber
+	}
+
+	if !strings.HasPrefix(selector, "0x") {
+		// block tags like latest, pending or finalized are not cached
+		return false
+	}
+	if len(selector) == 66 {
+		// block hash
+		return true
+	}
+	num, err := strconv.ParseUint(selector[2:], 16, 64)
+	if err != nil {
+		return false
+	}
+	return c.isFinalized(ctx, num)
+}
+
+// isFinalized returns true if the block number is finalized. The finalized
+// block is queried again if the number is ahead of the last known one.
+func (c *Cache) isFinalized(ctx context.Context, num uint64) bool {
+	c.finalizedLock.Lock()
+	defer c.finalizedLock.Unlock()
+
+	if num <= c.finalized && !c.lastRefresh.IsZero() {
+		return true
+	}
+	if time.Since(c.lastRefresh) < c.config.FinalizedRefresh {
+		return false
+	}
+	finalized, err := c.queryFinalized(ctx)
+	if err != nil {
+		return false
+	}
+	c.finalized = finalized
+	c.lastRefresh = time.Now()
+	return num <= c.finalized
+}
+
+func (c *Cache) queryFinalized(ctx context.Context) (uint64, error) {
+	var block *struct {
+		Number string `json:"number"`
+	}
+	if err := CallContext(ctx, c.Transport, "eth_getBlockByNumber", &block, "finalized", false); err == nil && block != nil {
+		return parseHexUint64(block.Number)
+	}
+
+	// the node does not support the finalized tag
+	var head string
+	if err := CallContext(ctx, c.Transport, "eth_blockNumber", &head); err != nil {
+		return 0, err
+	}
+	num, err := parseHexUint64(head)
+	if err != nil {
+		return 0, err
+	}
+	if num < c.config.FinalizedDepth {
+		return 0, nil
+	}
+	return num - c.config.FinalizedDepth, nil
+}
+
+func parseHexUint64(str string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(str, "0x"), 16, 64)
+}
+
+// storeKey returns the key of the request in the persistent store, prefixed
+// with the namespace so that a store is never shared by different chains
+func (c *Cache) storeKey(ctx context.Context, method, key string) (string, bool) {
+	if c.config.Store == nil || cacheMethods[method].memory {
+		return "", false
+	}
+
+	c.namespaceLock.Lock()
+	defer c.namespaceLock.Unlock()
+
+	if c.namespace == "" {
+		var chainID string
+		if err := CallContext(ctx, c.Transport, "eth_chainId", &chainID); err != nil || chainID == "" {
+			// try again on the next request
+			return "", false
+		}
+		c.namespace = chainID
+	}
+	return c.namespace + "/" + key, true
+}
+
+func (c *Cache) get(ctx context.Context, method, key string) ([]byte, bool) {
+	c.lock.Lock()
+	if elem, ok := c.items[key]; ok {
+		c.lru.MoveToFront(elem)
+		c.stats.Hits++
+		c.lock.Unlock()
+		return elem.Value.(*cacheEntry).value, true
+	}
+	c.lock.Unlock()
+
+	if storeKey, ok := c.storeKey(ctx, method, key); ok {
+		if value, err := c.config.Store.Get(storeKey); err == nil && value != nil {
+			c.lock.Lock()
+			c.stats.Hits++
+			c.add(key, value)
+			c.lock.Unlock()
+			return value, true
+		}
+	}
+
+	c.lock.Lock()
+	c.stats.Misses++
+	c.lock.Unlock()
+	return nil, false
+}
+
+// put caches the response if it is not empty and, for the responses
+// with a block number, if the block is finalized
+func (c *Cache) put(ctx context.Context, method, key string, value json.RawMessage) {
+	if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+		return
+	}
+	if cacheMethods[method].rule == cacheResultBlock {
+		var obj struct {
+			BlockNumber *string `json:"blockNumber"`
+		}
+		if err := json.Unmarshal(value, &obj); err != nil || obj.BlockNumber == nil {
+			// pending transaction
+			return
+		}
+		num, err := parseHexUint64(*obj.BlockNumber)
+		if err != nil || !c.isFinalized(ctx, num) {
+			return
+		}
+	}
+
+	c.lock.Lock()
+	c.add(key, value)
+	c.lock.Unlock()
+
+	if storeKey, ok := c.storeKey(ctx, method, key); ok {
+		// the response is still cached in memory if the store fails
+		c.config.Store.Put(storeKey, value)
+	}
+}
+
+// add inserts the value in the lru and evicts the oldest entry if it is full
+func (c *Cache) add(key string, value []byte) {
+	if elem, ok := c.items[key]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.lru.PushFront(&cacheEntry{key: key, value: value})
+
+	if c.config.Size > 0 && c.lru.Len() > c.config.Size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
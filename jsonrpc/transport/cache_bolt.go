@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+var _ CacheStore = (*BoltCacheStore)(nil)
+
+var (
+	dbCache = []byte("cache")
+	dbMeta  = []byte("meta")
+
+	namespaceKey = []byte("namespace")
+)
+
+// BoltCacheStore is a CacheStore that persists the responses in a boltdb file
+type BoltCacheStore struct {
+	conn *bolt.DB
+}
+
+// NewBoltCacheStore opens the boltdb cache store at path. The namespace (i.e. the
+// chain id) is stored when the file is created and the store cannot be opened
+// later with a different one.
+func NewBoltCacheStore(path string, namespace string) (*BoltCacheStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(txn *bolt.Tx) error {
+		if _, err := txn.CreateBucketIfNotExists(dbCache); err != nil {
+			return err
+		}
+		meta, err := txn.CreateBucketIfNotExists(dbMeta)
+		if err != nil {
+			return err
+		}
+		if val := meta.Get(namespaceKey); val != nil {
+			if string(val) != namespace {
+				return fmt.Errorf("cache store %s belongs to namespace '%s', not '%s'", path, string(val), namespace)
+			}
+			return nil
+		}
+		return meta.Put(namespaceKey, []byte(namespace))
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltCacheStore{conn: db}, nil
+}
+
+// Get implements the CacheStore interface
+func (b *BoltCacheStore) Get(key string) ([]byte, error) {
+	var value []byte
+	err := b.conn.View(func(txn *bolt.Tx) error {
+		if val := txn.Bucket(dbCache).Get([]byte(key)); val != nil {
+			// the value is only valid during the transaction
+			value = append([]byte{}, val...)
+		}
+		return nil
+	})
+	return value, err
+}
+
+// Put implements the CacheStore interface
+func (b *BoltCacheStore) Put(key string, value []byte) error {
+	return b.conn.Update(func(txn *bolt.Tx) error {
+		return txn.Bucket(dbCache).Put([]byte(key), value)
+	})
+}
+
+// Close implements the CacheStore interface
+func (b *BoltCacheStore) Close() error {
+	return b.conn.Close()
+}
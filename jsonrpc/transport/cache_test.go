@@ -0,0 +1,243 @@
+package transport
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newMockChain returns a transport with the finalized block 10
+func newMockChain() *mockTransport {
+	return &mockTransport{handler: func(method string) (interface{}, error) {
+		switch method {
+		case "eth_chainId":
+			return "0x1", nil
+		case "eth_getBlockByNumber":
+			return map[string]interface{}{"number": "0xa"}, nil
+		case "eth_getTransactionReceipt":
+			return map[string]interface{}{"blockNumber": "0x5"}, nil
+		case "eth_getTransactionByHash":
+			// pending transaction
+			return map[string]interface{}{"blockNumber": nil}, nil
+		case "eth_getBlockByHash":
+			return nil, nil
+		}
+		return "0x0", nil
+	}}
+}
+
+func TestCache_ChainID(t *testing.T) {
+	m := newMockChain()
+	c := NewCache(m)
+
+	for i := 0; i < 3; i++ {
+		var chainID string
+		assert.NoError(t, c.Call("eth_chainId", &chainID))
+		assert.Equal(t, "0x1", chainID)
+	}
+	assert.Equal(t, 1, m.numCalls())
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Entries: 1}, c.Stats())
+}
+
+func TestCache_BlockNumber(t *testing.T) {
+	m := newMockChain()
+	c := NewCache(m)
+
+	var block map[string]interface{}
+
+	// block tags are never cached
+	assert.NoError(t, c.Call("eth_getBlockByNumber", &block, "latest", false))
+	assert.NoError(t, c.Call("eth_getBlockByNumber", &block, "latest", false))
+	assert.Equal(t, 2, m.numCalls())
+
+	// the first query for a number fetches the finalized block
+	assert.NoError(t, c.Call("eth_getBlockByNumber", &block, "0x5", false))
+	assert.Equal(t, 4, m.numCalls())
+	assert.NoError(t, c.Call("eth_getBlockByNumber", &block, "0x5", false))
+	assert.Equal(t, 4, m.numCalls())
+
+	// a block after the finalized one is not cached and the
+	// finalized block is not queried again until the refresh time
+	assert.NoError(t, c.Call("eth_getBlockByNumber", &block, "0x20", false))
+	assert.NoError(t, c.Call("eth_getBlockByNumber", &block, "0x20", false))
+	assert.Equal(t, 6, m.numCalls())
+
+	// state queries by hash are always cached
+	hash := "0x" + "ab"
+	for len(hash) < 66 {
+		hash += "ab"
+	}
+	var balance string
+	assert.NoError(t, c.Call("eth_getBalance", &balance, "0x0", map[string]interface{}{"blockHash": hash}))
+	assert.NoError(t, c.Call("eth_getBalance", &balance, "0x0", map[string]interface{}{"blockHash": hash}))
+	assert.NoError(t, c.Call("eth_getCode", &balance, "0x0", hash))
+	assert.NoError(t, c.Call("eth_getCode", &balance, "0x0", hash))
+	assert.Equal(t, 8, m.numCalls())
+}
+
+func TestCache_ResultBlock(t *testing.T) {
+	m := newMockChain()
+	c := NewCache(m)
+
+	var obj map[string]interface{}
+
+	// receipt in a finalized block
+	assert.NoError(t, c.Call("eth_getTransactionReceipt", &obj, "0x1"))
+	assert.Equal(t, 2, m.numCalls())
+	assert.NoError(t, c.Call("eth_getTransactionReceipt", &obj, "0x1"))
+	assert.Equal(t, 2, m.numCalls())
+
+	// pending transaction
+	assert.NoError(t, c.Call("eth_getTransactionByHash", &obj, "0x1"))
+	assert.NoError(t, c.Call("eth_getTransactionByHash", &obj, "0x1"))
+	assert.Equal(t, 4, m.numCalls())
+
+	// empty responses
+	assert.NoError(t, c.Call("eth_getBlockByHash", &obj, "0x1", false))
+	assert.NoError(t, c.Call("eth_getBlockByHash", &obj, "0x1", false))
+	assert.Equal(t, 6, m.numCalls())
+}
+
+func TestCache_Eviction(t *testing.T) {
+	m := newMockChain()
+	c := NewCache(m, WithCacheSize(2))
+
+	var block map[string]interface{}
+	for _, num := range []string{"0x1", "0x2", "0x3"} {
+		assert.NoError(t, c.Call("eth_getBlockByNumber", &block, num, false))
+	}
+	assert.Equal(t, 2, c.Stats().Entries)
+
+	calls := m.numCalls()
+	assert.NoError(t, c.Call("eth_getBlockByNumber", &block, "0x3", false))
+	assert.Equal(t, calls, m.numCalls())
+	assert.NoError(t, c.Call("eth_getBlockByNumber", &block, "0x1", false))
+	assert.Equal(t, calls+1, m.numCalls())
+}
+
+// mockBatchTransport sends the batch elements one by one and counts the batches
+type mockBatchTransport struct {
+	*mockTransport
+	batches [][]string
+}
+
+func (m *mockBatchTransport) BatchCall(elems []*BatchElem) error {
+	return m.BatchCallContext(context.Background(), elems)
+}
+
+func (m *mockBatchTransport) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	methods := []string{}
+	for _, elem := range elems {
+		methods = append(methods, elem.Method)
+		elem.Error = m.CallContext(ctx, elem.Method, elem.Result, elem.Params...)
+	}
+	m.batches = append(m.batches, methods)
+	return nil
+}
+
+func TestCache_Batch(t *testing.T) {
+	m := &mockBatchTransport{mockTransport: newMockChain()}
+	c := NewCache(m)
+
+	var chainID string
+	assert.NoError(t, c.Call("eth_chainId", &chainID))
+
+	var num string
+	elems := []*BatchElem{
+		{Method: "eth_chainId", Result: &chainID},
+		{Method: "eth_blockNumber", Result: &num},
+	}
+	assert.NoError(t, c.BatchCall(elems))
+	assert.NoError(t, elems[0].Error)
+	assert.Equal(t, "0x1", chainID)
+	assert.Equal(t, [][]string{{"eth_blockNumber"}}, m.batches)
+}
+
+func TestCache_Store(t *testing.T) {
+	dir, err := ioutil.TempDir("/tmp", "ethgo-cache-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cache.db")
+
+	store, err := NewBoltCacheStore(path, "1")
+	assert.NoError(t, err)
+
+	m := newMockChain()
+	c := NewCache(m, WithCacheStore(store), WithCacheNamespace("1"))
+
+	var count, chainID string
+	assert.NoError(t, c.Call("eth_getBlockTransactionCountByHash", &count, "0x1"))
+	assert.NoError(t, c.Call("eth_chainId", &chainID))
+	assert.NoError(t, c.Close())
+
+	// a store cannot be opened with a different namespace
+	_, err = NewBoltCacheStore(path, "2")
+	assert.Error(t, err)
+
+	// a new cache reads the response from the store
+	store, err = NewBoltCacheStore(path, "1")
+	assert.NoError(t, err)
+
+	m = newMockChain()
+	c = NewCache(m, WithCacheStore(store), WithCacheNamespace("1"))
+	defer c.Close()
+
+	count = ""
+	assert.NoError(t, c.Call("eth_getBlockTransactionCountByHash", &count, "0x1"))
+	assert.Equal(t, "0x0", count)
+	assert.Equal(t, 0, m.numCalls())
+
+	// the chain id is never served from the store
+	assert.NoError(t, c.Call("eth_chainId", &chainID))
+	assert.Equal(t, 1, m.numCalls())
+}
+
+// memoryStore is an in-memory CacheStore
+type memoryStore map[string][]byte
+
+func (m memoryStore) Get(key string) ([]byte, error) {
+	return m[key], nil
+}
+
+func (m memoryStore) Put(key string, value []byte) error {
+	m[key] = value
+	return nil
+}
+
+func (m memoryStore) Close() error {
+	return nil
+}
+
+func TestCache_StoreChainID(t *testing.T) {
+	store := memoryStore{}
+
+	// every method returns the chain id
+	newChain := func(chainID string) *mockTransport {
+		return &mockTransport{handler: func(method string) (interface{}, error) {
+			return chainID, nil
+		}}
+	}
+
+	c := NewCache(newChain("0x1"), WithCacheStore(store))
+
+	var res string
+	assert.NoError(t, c.Call("eth_getBlockTransactionCountByHash", &res, "0x1"))
+	assert.Equal(t, "0x1", res)
+
+	// the keys are prefixed with the chain id of the node
+	_, ok := store["0x1/eth_getBlockTransactionCountByHash[\"0x1\"]"]
+	assert.True(t, ok)
+
+	// a node of another chain does not read the responses of the first one
+	m := newChain("0x2")
+	c = NewCache(m, WithCacheStore(store))
+
+	assert.NoError(t, c.Call("eth_getBlockTransactionCountByHash", &res, "0x1"))
+	assert.Equal(t, "0x2", res)
+	assert.Equal(t, 2, m.numCalls())
+}
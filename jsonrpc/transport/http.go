@@ -1,24 +1,32 @@
 package transport
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 
 	"github.com/umbracle/ethgo/jsonrpc/codec"
-	"github.com/valyala/fasthttp"
 )
 
 // HTTP is an http transport
 type HTTP struct {
-	addr    string
-	client  *fasthttp.Client
-	headers map[string]string
+	addr      string
+	client    *http.Client
+	transport *http.Transport
+	headers   map[string]string
+	auth      Auth
 }
 
 func newHTTP(addr string, headers map[string]string) *HTTP {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 	return &HTTP{
-		addr:    addr,
-		client:  &fasthttp.Client{},
-		headers: headers,
+		addr:      addr,
+		client:    &http.Client{Transport: transport},
+		transport: transport,
+		headers:   headers,
 	}
 }
 
@@ -29,44 +37,29 @@ func (h *HTTP) Close() error {
 
 // Call implements the transport interface
 func (h *HTTP) Call(method string, out interface{}, params ...interface{}) error {
+	return h.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext implements the transport interface
+func (h *HTTP) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
 	// Encode json-rpc request
-	request := codec.Request{
-		JsonRPC: "2.0",
-		Method:  method,
-	}
-	if len(params) > 0 {
-		data, err := json.Marshal(params)
-		if err != nil {
-			return err
-		}
-		request.Params = data
+	request, err := codec.NewRequest(0, method, params)
+	if err != nil {
+		return err
 	}
 	raw, err := json.Marshal(request)
 	if err != nil {
 		return err
 	}
 
-	req := fasthttp.AcquireRequest()
-	res := fasthttp.AcquireResponse()
-
-	defer fasthttp.ReleaseRequest(req)
-	defer fasthttp.ReleaseResponse(res)
-
-	req.SetRequestURI(h.addr)
-	req.Header.SetMethod("POST")
-	req.Header.SetContentType("application/json")
-	for k, v := range h.headers {
-		req.Header.Add(k, v)
-	}
-	req.SetBody(raw)
-
-	if err := h.client.Do(req, res); err != nil {
+	body, err := h.post(ctx, raw)
+	if err != nil {
 		return err
 	}
 
 	// Decode json-rpc response
 	var response codec.Response
-	if err := json.Unmarshal(res.Body(), &response); err != nil {
+	if err := json.Unmarshal(body, &response); err != nil {
 		return err
 	}
 	if response.Error != nil {
@@ -79,7 +72,144 @@ func (h *HTTP) Call(method string, out interface{}, params ...interface{}) error
 	return nil
 }
 
+// BatchCall implements the BatchTransport interface
+func (h *HTTP) BatchCall(elems []*BatchElem) error {
+	return h.BatchCallContext(context.Background(), elems)
+}
+
+// BatchCallContext implements the BatchTransport interface
+func (h *HTTP) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	// Encode json-rpc batch request. The ids are the position
+	// of the element in the batch starting from 1.
+	requests := make([]*codec.Request, len(elems))
+	for indx, elem := range elems {
+		request, err := codec.NewRequest(uint64(indx+1), elem.Method, elem.Params)
+		if err != nil {
+			return err
+		}
+		requests[indx] = request
+	}
+	raw, err := json.Marshal(requests)
+	if err != nil {
+		return err
+	}
+
+	body, err := h.post(ctx, raw)
+	if err != nil {
+		return err
+	}
+
+	// Decode json-rpc batch response
+	var responses []*codec.Response
+	if err := json.Unmarshal(body, &responses); err != nil {
+		// the server might reply with a single error object if
+		// it does not support batch requests
+		var response codec.Response
+		if err2 := json.Unmarshal(body, &response); err2 == nil && response.Error != nil {
+			return response.Error
+		}
+		return err
+	}
+	return resolveBatch(elems, responses)
+}
+
+func (h *HTTP) post(ctx context.Context, raw []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", h.addr, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.headers {
+		req.Header.Add(k, v)
+	}
+	for k, v := range HeadersFromContext(ctx) {
+		req.Header.Set(k, v)
+	}
+	if h.auth != nil {
+		authorization, err := h.auth.Authorization(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		if auth, ok := h.auth.(interface{ Invalidate() }); ok {
+			// request new credentials in the next request
+			auth.Invalidate()
+		}
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		// some servers reply jsonrpc errors with a non 2xx status code
+		var response codec.Response
+		if err := json.Unmarshal(body, &response); err == nil && response.Error != nil {
+			return body, nil
+		}
+		return nil, &HTTPError{StatusCode: res.StatusCode, Status: res.Status, Body: body}
+	}
+	return body, nil
+}
+
+// HTTPError is returned when the http server replies with a non 2xx status code
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	if len(e.Body) == 0 {
+		return e.Status
+	}
+	return fmt.Sprintf("%s: %s", e.Status, string(e.Body))
+}
+
 // SetMaxConnsPerHost sets the maximum number of connections that can be established with a host
 func (h *HTTP) SetMaxConnsPerHost(count int) {
-	h.client.MaxConnsPerHost = count
+	if h.transport != nil {
+		h.transport.MaxConnsPerHost = count
+	}
+}
+
+// resolveBatch sets the result or the error of each batch element
+// from the responses, which are matched by id.
+func resolveBatch(elems []*BatchElem, responses []*codec.Response) error {
+	byID := make(map[uint64]*codec.Response, len(responses))
+	for _, resp := range responses {
+		byID[resp.ID] = resp
+	}
+	for indx, elem := range elems {
+		resp, ok := byID[uint64(indx+1)]
+		if !ok {
+			elem.Error = fmt.Errorf("batch response for %s not found", elem.Method)
+			continue
+		}
+		if resp.Error != nil {
+			elem.Error = resp.Error
+			continue
+		}
+		if elem.Result != nil {
+			if err := json.Unmarshal(resp.Result, elem.Result); err != nil {
+				elem.Error = err
+			}
+		}
+	}
+	return nil
 }
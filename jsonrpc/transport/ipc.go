@@ -6,18 +6,23 @@ import (
 )
 
 func newIPC(addr string) (Transport, error) {
-	conn, err := net.Dial("unix", addr)
+	dial := func() (Codec, error) {
+		conn, err := net.Dial("unix", addr)
+		if err != nil {
+			return nil, err
+		}
+		codec := &ipcCodec{
+			buf:  json.RawMessage{},
+			conn: conn,
+			dec:  json.NewDecoder(conn),
+		}
+		return codec, nil
+	}
+	codec, err := dial()
 	if err != nil {
 		return nil, err
 	}
-
-	codec := &ipcCodec{
-		buf:  json.RawMessage{},
-		conn: conn,
-		dec:  json.NewDecoder(conn),
-	}
-
-	return newStream(codec)
+	return newStream(codec, dial)
 }
 
 type ipcCodec struct {
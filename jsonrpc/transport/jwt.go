@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// jwtSecretLength is the length of the secret of the engine api
+const jwtSecretLength = 32
+
+// jwtHeader is the encoded {"alg":"HS256","typ":"JWT"} header
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// JWTAuth authenticates the requests with a HS256 JWT token signed with a shared
+// secret (i.e. for the engine api). Each request has a new token with the current
+// time as the iat claim since the servers reject tokens issued more than a minute ago.
+type JWTAuth struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewJWTAuth creates a JWT authentication with the 32 bytes secret
+func NewJWTAuth(secret []byte) (*JWTAuth, error) {
+	if len(secret) != jwtSecretLength {
+		return nil, fmt.Errorf("jwt secret must be %d bytes but found %d", jwtSecretLength, len(secret))
+	}
+	return &JWTAuth{
+		secret: append([]byte{}, secret...),
+		now:    time.Now,
+	}, nil
+}
+
+// NewJWTAuthFromFile creates a JWT authentication with the hex encoded secret in the file
+func NewJWTAuthFromFile(path string) (*JWTAuth, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	str := strings.TrimPrefix(strings.TrimSpace(string(data)), "0x")
+	secret, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode jwt secret: %v", err)
+	}
+	return NewJWTAuth(secret)
+}
+
+// Token returns a new signed token
+func (j *JWTAuth) Token() (string, error) {
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": j.now().Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Authorization implements the Auth interface
+func (j *JWTAuth) Authorization(ctx context.Context) (string, error) {
+	token, err := j.Token()
+	if err != nil {
+		return "", err
+	}
+	return "Bearer " + token, nil
+}
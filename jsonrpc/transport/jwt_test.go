@@ -0,0 +1,128 @@
+package transport
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+var testJWTSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// verifyJWT checks the signature of the token and returns its iat claim
+func verifyJWT(t *testing.T, secret []byte, authorization string) int64 {
+	assert.True(t, strings.HasPrefix(authorization, "Bearer "))
+	parts := strings.Split(strings.TrimPrefix(authorization, "Bearer "), ".")
+	assert.Len(t, parts, 3)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.NoError(t, err)
+	assert.True(t, hmac.Equal(mac.Sum(nil), sig))
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"alg":"HS256","typ":"JWT"}`, string(header))
+
+	var claims struct {
+		Iat int64 `json:"iat"`
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &claims))
+	return claims.Iat
+}
+
+func TestJWTAuth_Token(t *testing.T) {
+	auth, err := NewJWTAuth(testJWTSecret)
+	assert.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	auth.now = func() time.Time { return now }
+
+	authorization, err := auth.Authorization(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), verifyJWT(t, testJWTSecret, authorization))
+
+	// the iat claim is updated on each token
+	now = now.Add(time.Minute)
+	authorization, err = auth.Authorization(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1060), verifyJWT(t, testJWTSecret, authorization))
+
+	_, err = NewJWTAuth([]byte{0x1})
+	assert.Error(t, err)
+}
+
+func TestJWTAuth_File(t *testing.T) {
+	dir, err := ioutil.TempDir("/tmp", "ethgo-jwt-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "jwt.hex")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("0x3031323334353637383961626364656630313233343536373839616263646566\n"), 0600))
+
+	auth, err := NewJWTAuthFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, testJWTSecret, auth.secret)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("0xzz"), 0600))
+	_, err = NewJWTAuthFromFile(path)
+	assert.Error(t, err)
+}
+
+func TestJWTAuth_Transports(t *testing.T) {
+	auth, err := NewJWTAuth(testJWTSecret)
+	assert.NoError(t, err)
+
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		iat := verifyJWT(t, testJWTSecret, r.Header.Get("Authorization"))
+		assert.InDelta(t, time.Now().Unix(), iat, 5)
+
+		if r.Header.Get("Upgrade") != "websocket" {
+			var req codec.Request
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			json.NewEncoder(w).Encode(&codec.Response{ID: req.ID, Result: []byte(`"ok"`)})
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req codec.Request
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			conn.WriteJSON(&codec.Response{ID: req.ID, Result: []byte(`"ok"`)})
+		}
+	}))
+	defer srv.Close()
+
+	for _, url := range []string{srv.URL, "ws" + strings.TrimPrefix(srv.URL, "http")} {
+		tt, err := NewTransport(url, nil, WithAuth(auth))
+		assert.NoError(t, err)
+
+		var out string
+		assert.NoError(t, tt.Call("engine_exchangeCapabilities", &out))
+		assert.Equal(t, "ok", out)
+		tt.Close()
+	}
+}
@@ -0,0 +1,525 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+// MultiMode is the strategy used by the Multi transport to
+// distribute the requests among the endpoints
+type MultiMode int
+
+const (
+	// ModeFailover sends the requests to the first healthy endpoint and
+	// moves to the next one on error or timeout
+	ModeFailover MultiMode = iota
+
+	// ModeRoundRobin spreads the requests across the healthy endpoints
+	ModeRoundRobin
+
+	// ModeQuorum sends the read requests to all the healthy endpoints and
+	// returns the answer of the majority. Other requests use failover.
+	ModeQuorum
+)
+
+func (m MultiMode) String() string {
+	switch m {
+	case ModeFailover:
+		return "failover"
+	case ModeRoundRobin:
+		return "round-robin"
+	case ModeQuorum:
+		return "quorum"
+	default:
+		return fmt.Sprintf("MultiMode(%d)", int(m))
+	}
+}
+
+// ErrNoQuorum is returned when the endpoints do not agree on a response
+var ErrNoQuorum = errors.New("endpoints did not reach quorum")
+
+// quorumMethods are the read methods that are sent to several endpoints in quorum mode
+var quorumMethods = map[string]struct{}{
+	"eth_call":                  {},
+	"eth_chainId":               {},
+	"eth_getBalance":            {},
+	"eth_getBlockByHash":        {},
+	"eth_getBlockByNumber":      {},
+	"eth_getCode":               {},
+	"eth_getLogs":               {},
+	"eth_getStorageAt":          {},
+	"eth_getTransactionByHash":  {},
+	"eth_getTransactionCount":   {},
+	"eth_getTransactionReceipt": {},
+}
+
+// MultiConfig is the configuration of the multi endpoint transport
+type MultiConfig struct {
+	// Mode is the strategy to distribute the requests
+	Mode MultiMode
+
+	// Quorum is the number of endpoints that have to agree on a response.
+	// By default it is the majority of the healthy endpoints.
+	Quorum int
+
+	// Timeout is the timeout of a request to a single endpoint
+	Timeout time.Duration
+
+	// HealthCheckInterval is the interval to query the head of the endpoints.
+	// The health check is disabled if it is zero.
+	HealthCheckInterval time.Duration
+
+	// MaxBlockLag is the number of blocks an endpoint can be behind
+	// the highest head before it is considered unhealthy
+	MaxBlockLag uint64
+}
+
+// DefaultMultiConfig returns the default multi endpoint configuration
+func DefaultMultiConfig() *MultiConfig {
+	return &MultiConfig{
+		Mode:                ModeFailover,
+		Timeout:             10 * time.Second,
+		HealthCheckInterval: 10 * time.Second,
+		MaxBlockLag:         5,
+	}
+}
+
+type MultiOption func(*MultiConfig)
+
+func WithMode(mode MultiMode) MultiOption {
+	return func(c *MultiConfig) {
+		c.Mode = mode
+	}
+}
+
+func WithQuorum(n int) MultiOption {
+	return func(c *MultiConfig) {
+		c.Mode = ModeQuorum
+		c.Quorum = n
+	}
+}
+
+func WithEndpointTimeout(d time.Duration) MultiOption {
+	return func(c *MultiConfig) {
+		c.Timeout = d
+	}
+}
+
+func WithHealthCheck(interval time.Duration, maxBlockLag uint64) MultiOption {
+	return func(c *MultiConfig) {
+		c.HealthCheckInterval = interval
+		c.MaxBlockLag = maxBlockLag
+	}
+}
+
+// EndpointStatus is the health status of an endpoint
+type EndpointStatus struct {
+	Index   int
+	Healthy bool
+	Head    uint64
+	Err     error
+}
+
+type endpoint struct {
+	indx      int
+	transport Transport
+
+	lock    sync.Mutex
+	healthy bool
+	head    uint64
+	err     error
+}
+
+func (e *endpoint) status() EndpointStatus {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return EndpointStatus{Index: e.indx, Healthy: e.healthy, Head: e.head, Err: e.err}
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.healthy
+}
+
+// Multi is a transport that sends the requests to several endpoints of the same chain
+type Multi struct {
+	config    *MultiConfig
+	endpoints []*endpoint
+	next      uint64
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMulti creates a transport over the given endpoints. All the endpoints
+// are considered healthy until the first health check.
+func NewMulti(transports []Transport, opts ...MultiOption) (*Multi, error) {
+	if len(transports) == 0 {
+		return nil, fmt.Errorf("no endpoints")
+	}
+
+	config := DefaultMultiConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	m := &Multi{
+		config:  config,
+		closeCh: make(chan struct{}),
+	}
+	for indx, t := range transports {
+		m.endpoints = append(m.endpoints, &endpoint{indx: indx, transport: t, healthy: true})
+	}
+
+	if config.HealthCheckInterval != 0 {
+		go m.runHealthCheck()
+	}
+	return m, nil
+}
+
+// NewMultiFromURLs creates a multi endpoint transport from a list of urls
+func NewMultiFromURLs(urls []string, headers map[string]string, opts ...MultiOption) (*Multi, error) {
+	transports := []Transport{}
+	for _, url := range urls {
+		t, err := NewTransport(url, headers)
+		if err != nil {
+			for _, t := range transports {
+				t.Close()
+			}
+			return nil, err
+		}
+		transports = append(transports, t)
+	}
+	return NewMulti(transports, opts...)
+}
+
+// Status returns the health status of the endpoints
+func (m *Multi) Status() []EndpointStatus {
+	res := []EndpointStatus{}
+	for _, e := range m.endpoints {
+		res = append(res, e.status())
+	}
+	return res
+}
+
+// Close implements the transport interface
+func (m *Multi) Close() error {
+	var err error
+	m.closeOnce.Do(func() {
+		close(m.closeCh)
+		for _, e := range m.endpoints {
+			if closeErr := e.transport.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+// SetMaxConnsPerHost implements the transport interface
+func (m *Multi) SetMaxConnsPerHost(count int) {
+	for _, e := range m.endpoints {
+		e.transport.SetMaxConnsPerHost(count)
+	}
+}
+
+// Call implements the transport interface
+func (m *Multi) Call(method string, out interface{}, params ...interface{}) error {
+	return m.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext implements the transport interface
+func (m *Multi) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	if m.config.Mode == ModeQuorum {
+		if _, ok := quorumMethods[method]; ok {
+			return m.quorumCall(ctx, method, out, params...)
+		}
+	}
+	return m.failover(ctx, isIdempotent(method), func(ctx context.Context, t Transport) error {
+		return CallContext(ctx, t, method, out, params...)
+	})
+}
+
+// BatchCall implements the BatchTransport interface
+func (m *Multi) BatchCall(elems []*BatchElem) error {
+	return m.BatchCallContext(context.Background(), elems)
+}
+
+// BatchCallContext implements the BatchTransport interface. The
+// batch is sent with failover in all the modes.
+func (m *Multi) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	idempotent := true
+	for _, elem := range elems {
+		if !isIdempotent(elem.Method) {
+			idempotent = false
+		}
+	}
+	err := m.failover(ctx, idempotent, func(ctx context.Context, t Transport) error {
+		for _, elem := range elems {
+			elem.Error = nil
+		}
+		if batch, ok := t.(BatchTransport); ok {
+			if err := batch.BatchCallContext(ctx, elems); err != nil {
+				return err
+			}
+		} else {
+			for _, elem := range elems {
+				elem.Error = CallContext(ctx, t, elem.Method, elem.Result, elem.Params...)
+			}
+		}
+		// failover if any of the elements failed because of the endpoint
+		for _, elem := range elems {
+			if elem.Error != nil && shouldFailover(elem.Error) {
+				return &batchElemError{elem.Error}
+			}
+		}
+		return nil
+	})
+
+	var elemErr *batchElemError
+	if errors.As(err, &elemErr) {
+		// the batch was sent, the errors are set in the elements
+		return nil
+	}
+	return err
+}
+
+// batchElemError signals a failover because of an error in a batch element
+type batchElemError struct {
+	err error
+}
+
+func (b *batchElemError) Error() string {
+	return b.err.Error()
+}
+
+// candidates returns the endpoints to use ordered by preference
+func (m *Multi) candidates() []*endpoint {
+	healthy := []*endpoint{}
+	for _, e := range m.endpoints {
+		if e.isHealthy() {
+			healthy = append(healthy, e)
+		}
+	}
+	if len(healthy) == 0 {
+		// try all the endpoints before failing
+		healthy = append(healthy, m.endpoints...)
+	}
+	if m.config.Mode == ModeRoundRobin {
+		start := int(atomic.AddUint64(&m.next, 1) % uint64(len(healthy)))
+		rotated := make([]*endpoint, 0, len(healthy))
+		rotated = append(rotated, healthy[start:]...)
+		healthy = append(rotated, healthy[:start]...)
+	}
+	return healthy
+}
+
+func (m *Multi) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.config.Timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, m.config.Timeout)
+}
+
+// shouldFailover returns true if the error is caused by the endpoint
+// and the request could succeed in another endpoint
+func shouldFailover(err error) bool {
+	var obj *codec.ErrorObject
+	if errors.As(err, &obj) {
+		// the endpoint replied, only failover if the error is transient
+		return isTransientError(err, defaultRetryableCodes, defaultRetryableMessages)
+	}
+	return true
+}
+
+// notSent returns true if the error shows that the request did not reach the endpoint
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// failover sends the request to the endpoints until one of them replies. The
+// requests that are not idempotent (i.e. eth_sendRawTransaction) are only sent
+// to the next endpoint if they did not reach the previous one.
+func (m *Multi) failover(ctx context.Context, idempotent bool, handler func(ctx context.Context, t Transport) error) error {
+	var err error
+	for _, e := range m.candidates() {
+		callCtx, cancel := m.withTimeout(ctx)
+		err = handler(callCtx, e.transport)
+		cancel()
+
+		if err == nil || !shouldFailover(err) {
+			return err
+		}
+		if !idempotent && !notSent(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+type quorumResult struct {
+	raw json.RawMessage
+	err error
+}
+
+// key returns the value used to compare the responses between endpoints
+func (q *quorumResult) key() (string, bool) {
+	if q.err != nil {
+		var obj *codec.ErrorObject
+		if errors.As(q.err, &obj) {
+			// endpoints can agree on a jsonrpc error (i.e. a revert)
+			return "error:" + obj.Error(), true
+		}
+		return "", false
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, q.raw); err != nil {
+		return "", false
+	}
+	return "result:" + buf.String(), true
+}
+
+func (m *Multi) quorumCall(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	candidates := m.candidates()
+
+	quorum := m.config.Quorum
+	if quorum == 0 {
+		quorum = len(candidates)/2 + 1
+	}
+	if quorum > len(candidates) {
+		return fmt.Errorf("%w: quorum %d is higher than the %d available endpoints", ErrNoQuorum, quorum, len(candidates))
+	}
+
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	resCh := make(chan *quorumResult, len(candidates))
+	for _, e := range candidates {
+		go func(t Transport) {
+			res := &quorumResult{}
+			res.err = CallContext(ctx, t, method, &res.raw, params...)
+			resCh <- res
+		}(e.transport)
+	}
+
+	votes := map[string]int{}
+	errs := []string{}
+	for i := 0; i < len(candidates); i++ {
+		var res *quorumResult
+		select {
+		case res = <-resCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		key, ok := res.key()
+		if !ok {
+			errs = append(errs, res.err.Error())
+			continue
+		}
+		votes[key]++
+		if votes[key] < quorum {
+			continue
+		}
+		if res.err != nil {
+			return res.err
+		}
+		return json.Unmarshal(res.raw, out)
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("%w: %s", ErrNoQuorum, strings.Join(errs, ", "))
+	}
+	return ErrNoQuorum
+}
+
+func (m *Multi) runHealthCheck() {
+	ticker := time.NewTicker(m.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		m.CheckHealth(context.Background())
+
+		select {
+		case <-ticker.C:
+		case <-m.closeCh:
+			return
+		}
+	}
+}
+
+// CheckHealth queries the head of all the endpoints and marks as unhealthy
+// the ones that fail or that lag behind the highest head
+func (m *Multi) CheckHealth(ctx context.Context) {
+	heads := make([]uint64, len(m.endpoints))
+	errs := make([]error, len(m.endpoints))
+
+	var wg sync.WaitGroup
+	for indx, e := range m.endpoints {
+		wg.Add(1)
+		go func(indx int, t Transport) {
+			defer wg.Done()
+
+			callCtx, cancel := m.withTimeout(ctx)
+			defer cancel()
+
+			var out string
+			if err := CallContext(callCtx, t, "eth_blockNumber", &out); err != nil {
+				errs[indx] = err
+				return
+			}
+			head, err := strconv.ParseUint(strings.TrimPrefix(out, "0x"), 16, 64)
+			if err != nil {
+				errs[indx] = err
+				return
+			}
+			heads[indx] = head
+		}(indx, e.transport)
+	}
+	wg.Wait()
+
+	maxHead := uint64(0)
+	for indx, head := range heads {
+		if errs[indx] == nil && head > maxHead {
+			maxHead = head
+		}
+	}
+
+	for indx, e := range m.endpoints {
+		e.lock.Lock()
+		e.err = errs[indx]
+		if e.err == nil {
+			e.head = heads[indx]
+			if maxHead-e.head > m.config.MaxBlockLag {
+				e.err = fmt.Errorf("endpoint is %d blocks behind", maxHead-e.head)
+			}
+		}
+		e.healthy = e.err == nil
+		e.lock.Unlock()
+	}
+}
@@ -0,0 +1,200 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+// mockTransport is an in memory transport that replies with the handler
+type mockTransport struct {
+	lock    sync.Mutex
+	calls   int
+	handler func(method string) (interface{}, error)
+}
+
+func (m *mockTransport) Call(method string, out interface{}, params ...interface{}) error {
+	return m.CallContext(context.Background(), method, out, params...)
+}
+
+func (m *mockTransport) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	m.lock.Lock()
+	m.calls++
+	m.lock.Unlock()
+
+	res, err := m.handler(method)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func (m *mockTransport) numCalls() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.calls
+}
+
+func (m *mockTransport) SetMaxConnsPerHost(count int) {}
+
+func (m *mockTransport) Close() error {
+	return nil
+}
+
+func newMockReply(result interface{}, err error) *mockTransport {
+	return &mockTransport{handler: func(method string) (interface{}, error) {
+		return result, err
+	}}
+}
+
+func newMockHead(head uint64) *mockTransport {
+	return &mockTransport{handler: func(method string) (interface{}, error) {
+		return fmt.Sprintf("0x%x", head), nil
+	}}
+}
+
+func TestMulti_Failover(t *testing.T) {
+	bad := newMockReply(nil, errors.New("connection reset"))
+	good := newMockReply("ok", nil)
+
+	m, err := NewMulti([]Transport{bad, good}, WithHealthCheck(0, 0))
+	assert.NoError(t, err)
+
+	var out string
+	assert.NoError(t, m.Call("eth_blockNumber", &out))
+	assert.Equal(t, "ok", out)
+	assert.Equal(t, 1, bad.numCalls())
+	assert.Equal(t, 1, good.numCalls())
+
+	// a jsonrpc error from the endpoint does not failover
+	reverted := newMockReply(nil, &codec.ErrorObject{Code: 3, Message: "execution reverted"})
+	m, _ = NewMulti([]Transport{reverted, good}, WithHealthCheck(0, 0))
+	assert.Error(t, m.Call("eth_call", &out))
+	assert.Equal(t, 1, good.numCalls())
+}
+
+func TestMulti_FailoverNonIdempotent(t *testing.T) {
+	timeout := newMockReply(nil, ErrTimeout)
+	good := newMockReply("0x1", nil)
+
+	// the transaction may have reached the first endpoint
+	m, _ := NewMulti([]Transport{timeout, good}, WithHealthCheck(0, 0))
+
+	var out string
+	assert.Equal(t, ErrTimeout, m.Call("eth_sendRawTransaction", &out, "0x"))
+	assert.Equal(t, 0, good.numCalls())
+
+	elems := []*BatchElem{{Method: "eth_sendRawTransaction", Result: &out}}
+	assert.NoError(t, m.BatchCall(elems))
+	assert.Equal(t, ErrTimeout, elems[0].Error)
+	assert.Equal(t, 0, good.numCalls())
+
+	// the transaction did not reach the first endpoint
+	refused := newMockReply(nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	m, _ = NewMulti([]Transport{refused, good}, WithHealthCheck(0, 0))
+
+	assert.NoError(t, m.Call("eth_sendRawTransaction", &out, "0x"))
+	assert.Equal(t, "0x1", out)
+	assert.Equal(t, 1, good.numCalls())
+}
+
+func TestMulti_RoundRobin(t *testing.T) {
+	a, b := newMockReply("a", nil), newMockReply("b", nil)
+
+	m, _ := NewMulti([]Transport{a, b}, WithMode(ModeRoundRobin), WithHealthCheck(0, 0))
+	for i := 0; i < 10; i++ {
+		var out string
+		assert.NoError(t, m.Call("eth_blockNumber", &out))
+	}
+	assert.Equal(t, 5, a.numCalls())
+	assert.Equal(t, 5, b.numCalls())
+}
+
+func TestMulti_Quorum(t *testing.T) {
+	a, b, c := newMockReply("a", nil), newMockReply("b", nil), newMockReply("a", nil)
+
+	m, _ := NewMulti([]Transport{a, b, c}, WithQuorum(0), WithHealthCheck(0, 0))
+
+	var out string
+	assert.NoError(t, m.Call("eth_call", &out))
+	assert.Equal(t, "a", out)
+
+	// all the endpoints disagree
+	m, _ = NewMulti([]Transport{a, b}, WithQuorum(2), WithHealthCheck(0, 0))
+	err := m.Call("eth_call", &out)
+	assert.True(t, errors.Is(err, ErrNoQuorum))
+
+	// write methods do not use quorum
+	assert.NoError(t, m.Call("eth_sendRawTransaction", &out))
+}
+
+func TestMulti_HealthCheck(t *testing.T) {
+	a, b, c := newMockHead(100), newMockHead(90), newMockReply(nil, errors.New("down"))
+
+	m, _ := NewMulti([]Transport{a, b, c}, WithHealthCheck(0, 5))
+	m.CheckHealth(context.Background())
+
+	status := m.Status()
+	assert.True(t, status[0].Healthy)
+	assert.Equal(t, uint64(100), status[0].Head)
+	assert.False(t, status[1].Healthy)
+	assert.False(t, status[2].Healthy)
+
+	// only the healthy endpoint is used
+	for i := 0; i < 3; i++ {
+		var out string
+		assert.NoError(t, m.Call("eth_blockNumber", &out))
+	}
+	assert.Equal(t, 1, b.numCalls())
+	assert.Equal(t, 4, a.numCalls())
+}
+
+func TestMulti_Timeout(t *testing.T) {
+	slow := &mockTransport{handler: func(method string) (interface{}, error) {
+		return "slow", nil
+	}}
+	hang := &hangTransport{mockTransport: slow}
+	good := newMockReply("ok", nil)
+
+	m, _ := NewMulti([]Transport{hang, good}, WithEndpointTimeout(50*time.Millisecond), WithHealthCheck(0, 0))
+
+	var out string
+	assert.NoError(t, m.Call("eth_blockNumber", &out))
+	assert.Equal(t, "ok", out)
+}
+
+// hangTransport blocks until the context is done
+type hangTransport struct {
+	*mockTransport
+}
+
+func (h *hangTransport) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestMulti_BatchCall(t *testing.T) {
+	bad := newMockReply(nil, errors.New("connection reset"))
+	good := &mockTransport{handler: func(method string) (interface{}, error) {
+		if method == "fail" {
+			return nil, &codec.ErrorObject{Code: -32000, Message: "failed"}
+		}
+		return method, nil
+	}}
+
+	m, _ := NewMulti([]Transport{bad, good}, WithHealthCheck(0, 0))
+	testBatchCall(t, m)
+}
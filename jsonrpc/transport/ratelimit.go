@@ -0,0 +1,232 @@
+package transport
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitConfig is the configuration of the rate limit transport
+type RateLimitConfig struct {
+	// Rate is the number of units per second that can be sent
+	Rate float64
+
+	// Burst is the maximum number of units that can be sent at once.
+	// By default it is the same as the rate.
+	Burst float64
+
+	// MethodWeights are the units consumed by each method (i.e. the compute
+	// units of the provider). Methods not in the map consume one unit.
+	MethodWeights map[string]float64
+}
+
+type RateLimitOption func(*RateLimitConfig)
+
+func WithBurst(burst float64) RateLimitOption {
+	return func(c *RateLimitConfig) {
+		c.Burst = burst
+	}
+}
+
+func WithMethodWeight(method string, weight float64) RateLimitOption {
+	return func(c *RateLimitConfig) {
+		c.MethodWeights[method] = weight
+	}
+}
+
+func WithMethodWeights(weights map[string]float64) RateLimitOption {
+	return func(c *RateLimitConfig) {
+		for method, weight := range weights {
+			c.MethodWeights[method] = weight
+		}
+	}
+}
+
+// RateLimitStats are the statistics of the requests sent through the rate limiter
+type RateLimitStats struct {
+	// Requests is the number of requests that went through the limiter
+	Requests uint64
+
+	// Delayed is the number of requests that had to wait for capacity
+	Delayed uint64
+
+	// WaitTime is the total time the requests spent waiting for capacity
+	WaitTime time.Duration
+
+	// MaxWaitTime is the longest time a single request waited for capacity
+	MaxWaitTime time.Duration
+}
+
+// RateLimit is a transport that limits the rate of requests with a token bucket.
+// Wrap each transport of a Multi transport to rate limit each endpoint.
+type RateLimit struct {
+	Transport
+
+	config *RateLimitConfig
+
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+	stats  RateLimitStats
+}
+
+// NewRateLimit wraps the transport with a rate limiter of rate units per second
+func NewRateLimit(t Transport, rate float64, opts ...RateLimitOption) *RateLimit {
+	config := &RateLimitConfig{
+		Rate:          rate,
+		MethodWeights: map[string]float64{},
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.Burst == 0 {
+		config.Burst = config.Rate
+	}
+	return &RateLimit{
+		Transport: t,
+		config:    config,
+		tokens:    config.Burst,
+		last:      time.Now(),
+	}
+}
+
+// Stats returns the statistics of the rate limiter
+func (r *RateLimit) Stats() RateLimitStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.stats
+}
+
+// Call implements the transport interface
+func (r *RateLimit) Call(method string, out interface{}, params ...interface{}) error {
+	return r.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext implements the transport interface
+func (r *RateLimit) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	if err := r.Wait(ctx, r.weight(method)); err != nil {
+		return err
+	}
+	return CallContext(ctx, r.Transport, method, out, params...)
+}
+
+// BatchCall implements the BatchTransport interface
+func (r *RateLimit) BatchCall(elems []*BatchElem) error {
+	return r.BatchCallContext(context.Background(), elems)
+}
+
+// BatchCallContext implements the BatchTransport interface. The
+// batch consumes the units of all its elements.
+func (r *RateLimit) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	batch, ok := r.Transport.(BatchTransport)
+	if !ok {
+		for _, elem := range elems {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			elem.Error = r.CallContext(ctx, elem.Method, elem.Result, elem.Params...)
+		}
+		return nil
+	}
+
+	weight := float64(0)
+	for _, elem := range elems {
+		weight += r.weight(elem.Method)
+	}
+	if err := r.Wait(ctx, weight); err != nil {
+		return err
+	}
+	return batch.BatchCallContext(ctx, elems)
+}
+
+func (r *RateLimit) weight(method string) float64 {
+	if weight, ok := r.config.MethodWeights[method]; ok {
+		return weight
+	}
+	return 1
+}
+
+// reserve takes n units from the bucket and returns the time to wait
+// until they are available
+func (r *RateLimit) reserve(now time.Time, n float64) time.Duration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// refill the bucket with the units since the last request
+	r.tokens += now.Sub(r.last).Seconds() * r.config.Rate
+	if r.tokens > r.config.Burst {
+		r.tokens = r.config.Burst
+	}
+	r.last = now
+
+	r.tokens -= n
+	if r.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-r.tokens / r.config.Rate * float64(time.Second))
+}
+
+// cancel returns n units to the bucket after an aborted wait
+func (r *RateLimit) cancel(n float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.tokens += n
+	if r.tokens > r.config.Burst {
+		r.tokens = r.config.Burst
+	}
+}
+
+func (r *RateLimit) record(wait time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.stats.Requests++
+	if wait == 0 {
+		return
+	}
+	r.stats.Delayed++
+	r.stats.WaitTime += wait
+	if wait > r.stats.MaxWaitTime {
+		r.stats.MaxWaitTime = wait
+	}
+}
+
+// Wait blocks until n units are available or the context is done
+func (r *RateLimit) Wait(ctx context.Context, n float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.config.Rate <= 0 {
+		// no limit
+		r.record(0)
+		return nil
+	}
+
+	now := time.Now()
+	wait := r.reserve(now, n)
+	if wait == 0 {
+		r.record(0)
+		return nil
+	}
+
+	// fail early if the capacity is not available before the deadline
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		r.cancel(n)
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		r.record(wait)
+		return nil
+	case <-ctx.Done():
+		r.cancel(n)
+		r.record(time.Since(now))
+		return ctx.Err()
+	}
+}
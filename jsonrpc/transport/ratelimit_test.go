@@ -0,0 +1,78 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit_Wait(t *testing.T) {
+	m := newMockReply("ok", nil)
+
+	// 20 units per second with a burst of 2
+	r := NewRateLimit(m, 20, WithBurst(2))
+
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		var out string
+		assert.NoError(t, r.Call("eth_blockNumber", &out))
+	}
+	// the first two calls use the burst and the other two wait 50ms each
+	assert.GreaterOrEqual(t, int64(time.Since(now)), int64(90*time.Millisecond))
+
+	stats := r.Stats()
+	assert.Equal(t, uint64(4), stats.Requests)
+	assert.Equal(t, uint64(2), stats.Delayed)
+	assert.Greater(t, int64(stats.WaitTime), int64(0))
+}
+
+func TestRateLimit_MethodWeight(t *testing.T) {
+	m := newMockReply("ok", nil)
+	r := NewRateLimit(m, 10, WithMethodWeight("eth_getLogs", 5))
+
+	var out string
+	assert.NoError(t, r.Call("eth_getLogs", &out))
+	assert.NoError(t, r.Call("eth_getLogs", &out))
+
+	// the bucket is empty and the next call needs to wait ~100ms
+	assert.Equal(t, uint64(0), r.Stats().Delayed)
+	assert.InDelta(t, float64(100*time.Millisecond), float64(r.reserve(r.last, 1)), float64(5*time.Millisecond))
+}
+
+func TestRateLimit_Context(t *testing.T) {
+	m := newMockReply("ok", nil)
+	r := NewRateLimit(m, 1)
+
+	var out string
+	assert.NoError(t, r.Call("eth_blockNumber", &out))
+
+	// the next unit is not available before the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, r.CallContext(ctx, "eth_blockNumber", &out))
+	assert.Equal(t, 1, m.numCalls())
+
+	// cancel while waiting
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	assert.Equal(t, context.Canceled, r.CallContext(ctx, "eth_blockNumber", &out))
+	assert.Equal(t, 1, m.numCalls())
+}
+
+func TestRateLimit_BatchCall(t *testing.T) {
+	m := newMockReply("ok", nil)
+	r := NewRateLimit(m, 100, WithMethodWeight("eth_getLogs", 10))
+
+	elems := []*BatchElem{
+		{Method: "eth_getLogs"},
+		{Method: "eth_blockNumber"},
+	}
+	assert.NoError(t, r.BatchCall(elems))
+	assert.Equal(t, uint64(2), r.Stats().Requests)
+}
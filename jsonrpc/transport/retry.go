@@ -0,0 +1,313 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+var (
+	// defaultRetryableCodes are the jsonrpc error codes that are retried by default
+	defaultRetryableCodes = []int{
+		-32005, // limit exceeded
+		429,    // too many requests (some providers use the http code)
+	}
+
+	// defaultRetryableMessages are the substrings of jsonrpc error messages
+	// that are retried by default
+	defaultRetryableMessages = []string{
+		"header not found",
+		"rate limit",
+		"too many requests",
+	}
+
+	// nonIdempotentMethods are the methods that are not retried
+	// unless it is explicitly enabled
+	nonIdempotentMethods = map[string]struct{}{
+		"eth_sendRawTransaction": {},
+		"eth_sendTransaction":    {},
+	}
+)
+
+// RetryConfig is the configuration of the retry transport
+type RetryConfig struct {
+	// MaxRetries is the maximum number of retries after the first attempt
+	MaxRetries int
+
+	// MinBackoff is the wait time before the first retry
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum wait time between retries
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction (between 0 and 1) of the backoff that is randomized
+	Jitter float64
+
+	// RetryableCodes are the jsonrpc error codes that can be retried
+	RetryableCodes []int
+
+	// RetryableMessages are substrings of the jsonrpc error messages that can be retried
+	RetryableMessages []string
+
+	// RetryNonIdempotent enables the retry of methods like eth_sendRawTransaction
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryConfig returns the default retry configuration
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxRetries:        5,
+		MinBackoff:        100 * time.Millisecond,
+		MaxBackoff:        5 * time.Second,
+		Jitter:            0.2,
+		RetryableCodes:    append([]int{}, defaultRetryableCodes...),
+		RetryableMessages: append([]string{}, defaultRetryableMessages...),
+	}
+}
+
+type RetryOption func(*RetryConfig)
+
+func WithMaxRetries(n int) RetryOption {
+	return func(c *RetryConfig) {
+		c.MaxRetries = n
+	}
+}
+
+func WithBackoff(min, max time.Duration) RetryOption {
+	return func(c *RetryConfig) {
+		c.MinBackoff = min
+		c.MaxBackoff = max
+	}
+}
+
+func WithJitter(jitter float64) RetryOption {
+	return func(c *RetryConfig) {
+		c.Jitter = jitter
+	}
+}
+
+func WithRetryableCodes(codes ...int) RetryOption {
+	return func(c *RetryConfig) {
+		c.RetryableCodes = append(c.RetryableCodes, codes...)
+	}
+}
+
+// WithRetryableMessages adds substrings of the jsonrpc error messages
+// that can be retried. They are matched without case.
+func WithRetryableMessages(msgs ...string) RetryOption {
+	return func(c *RetryConfig) {
+		for _, msg := range msgs {
+			c.RetryableMessages = append(c.RetryableMessages, strings.ToLower(msg))
+		}
+	}
+}
+
+func WithRetryNonIdempotent() RetryOption {
+	return func(c *RetryConfig) {
+		c.RetryNonIdempotent = true
+	}
+}
+
+// Retry is a transport that retries the failed requests with an
+// exponential backoff if the error is transient
+type Retry struct {
+	Transport
+
+	config *RetryConfig
+}
+
+// NewRetry wraps the transport with the retry logic
+func NewRetry(t Transport, opts ...RetryOption) *Retry {
+	config := DefaultRetryConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return &Retry{
+		Transport: t,
+		config:    config,
+	}
+}
+
+// Call implements the transport interface
+func (r *Retry) Call(method string, out interface{}, params ...interface{}) error {
+	return r.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext implements the transport interface
+func (r *Retry) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	canRetry := r.canRetryMethod(method)
+
+	for attempt := 0; ; attempt++ {
+		err := CallContext(ctx, r.Transport, method, out, params...)
+		if err == nil {
+			return nil
+		}
+		if !canRetry || attempt >= r.config.MaxRetries || !r.IsRetryable(ctx, err) {
+			return err
+		}
+		if err := r.wait(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// BatchCall implements the BatchTransport interface
+func (r *Retry) BatchCall(elems []*BatchElem) error {
+	return r.BatchCallContext(context.Background(), elems)
+}
+
+// BatchCallContext implements the BatchTransport interface. Only the
+// elements that failed with a retryable error are sent again.
+func (r *Retry) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	batch, ok := r.Transport.(BatchTransport)
+	if !ok {
+		for _, elem := range elems {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			elem.Error = r.CallContext(ctx, elem.Method, elem.Result, elem.Params...)
+		}
+		return nil
+	}
+
+	canRetry := true
+	for _, elem := range elems {
+		if !r.canRetryMethod(elem.Method) {
+			canRetry = false
+		}
+	}
+
+	pending := elems
+	for attempt := 0; ; attempt++ {
+		lastAttempt := !canRetry || attempt >= r.config.MaxRetries
+
+		if err := batch.BatchCallContext(ctx, pending); err != nil {
+			if lastAttempt || !r.IsRetryable(ctx, err) {
+				return err
+			}
+		} else {
+			retry := []*BatchElem{}
+			for _, elem := range pending {
+				if elem.Error != nil && r.IsRetryable(ctx, elem.Error) {
+					retry = append(retry, elem)
+				}
+			}
+			if len(retry) == 0 || lastAttempt {
+				return nil
+			}
+			for _, elem := range retry {
+				elem.Error = nil
+			}
+			pending = retry
+		}
+		if err := r.wait(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Retry) canRetryMethod(method string) bool {
+	if r.config.RetryNonIdempotent {
+		return true
+	}
+	return isIdempotent(method)
+}
+
+// isIdempotent returns true if the request can be sent again without side
+// effects (i.e. it does not broadcast a transaction)
+func isIdempotent(method string) bool {
+	_, ok := nonIdempotentMethods[method]
+	return !ok
+}
+
+// backoff returns the wait time before the retry number attempt
+func (r *Retry) backoff(attempt int) time.Duration {
+	delay := r.config.MinBackoff
+	for i := 0; i < attempt && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.config.MaxBackoff {
+		delay = r.config.MaxBackoff
+	}
+	if r.config.Jitter > 0 {
+		delay -= time.Duration(r.config.Jitter * rand.Float64() * float64(delay))
+	}
+	return delay
+}
+
+func (r *Retry) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(r.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsRetryable returns true if the error is transient and the request can be retried
+func (r *Retry) IsRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		// the caller aborted the request
+		return false
+	}
+	return isTransientError(err, r.config.RetryableCodes, r.config.RetryableMessages)
+}
+
+// isTransientError returns true if the error is caused by the network, by an
+// overloaded server or by a jsonrpc error with one of the given codes or messages
+func isTransientError(err error, codes []int, msgs []string) bool {
+	if errors.Is(err, ErrTimeout) {
+		return true
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+
+	var obj *codec.ErrorObject
+	if errors.As(err, &obj) {
+		var rangeErr *codec.RangeLimitError
+		if errors.As(err, &rangeErr) {
+			// the same query fails again, it needs a smaller range
+			return false
+		}
+		var revertErr *codec.RevertError
+		if errors.As(err, &revertErr) {
+			// the execution fails again, even if the reason looks transient
+			return false
+		}
+		for _, code := range codes {
+			if obj.Code == code {
+				return true
+			}
+		}
+		msg := strings.ToLower(obj.Message)
+		for _, str := range msgs {
+			if strings.Contains(msg, str) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
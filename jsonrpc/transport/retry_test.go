@@ -0,0 +1,158 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+func newFlakyServer(t *testing.T, failures int32, fail func(w http.ResponseWriter)) (*httptest.Server, *int32) {
+	calls := int32(0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		if atomic.AddInt32(&calls, 1) <= failures {
+			fail(w)
+			return
+		}
+		w.Write(handleRaw(t, raw))
+	}))
+	return srv, &calls
+}
+
+func writeErrorObject(code int, msg string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		data, _ := json.Marshal(&codec.Response{Error: &codec.ErrorObject{Code: code, Message: msg}})
+		w.Write(data)
+	}
+}
+
+func writeStatus(status int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(status)
+	}
+}
+
+func testRetry(t *testing.T, fail func(w http.ResponseWriter), opts ...RetryOption) (string, int32, error) {
+	srv, calls := newFlakyServer(t, 2, fail)
+	defer srv.Close()
+
+	tr, err := NewTransport(srv.URL, nil)
+	assert.NoError(t, err)
+
+	opts = append([]RetryOption{WithBackoff(time.Millisecond, 10*time.Millisecond)}, opts...)
+	r := NewRetry(tr, opts...)
+
+	var out string
+	err = r.Call("eth_blockNumber", &out)
+	return out, atomic.LoadInt32(calls), err
+}
+
+func TestRetry_Retryable(t *testing.T) {
+	cases := []func(w http.ResponseWriter){
+		writeStatus(http.StatusTooManyRequests),
+		writeStatus(http.StatusBadGateway),
+		writeErrorObject(-32000, "header not found"),
+		writeErrorObject(-32005, "limit exceeded"),
+	}
+	for _, c := range cases {
+		out, calls, err := testRetry(t, c)
+		assert.NoError(t, err)
+		assert.Equal(t, "eth_blockNumber", out)
+		assert.Equal(t, int32(3), calls)
+	}
+}
+
+func TestRetry_Fatal(t *testing.T) {
+	cases := []func(w http.ResponseWriter){
+		writeStatus(http.StatusUnauthorized),
+		writeErrorObject(3, "execution reverted"),
+		// the reason of the revert is not a transient error
+		writeErrorObject(3, "execution reverted: oracle timeout"),
+		writeErrorObject(-32000, "execution reverted: rate limit exceeded"),
+	}
+	for _, c := range cases {
+		_, calls, err := testRetry(t, c)
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), calls)
+	}
+}
+
+func TestRetry_RetryableMessages(t *testing.T) {
+	fail := writeErrorObject(-32000, "Backend Is Syncing")
+
+	_, calls, err := testRetry(t, fail)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls)
+
+	// the messages are matched without case
+	_, calls, err = testRetry(t, fail, WithRetryableMessages("Backend is SYNCING"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestRetry_MaxRetries(t *testing.T) {
+	_, calls, err := testRetry(t, writeStatus(http.StatusBadGateway), WithMaxRetries(1))
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestRetry_NonIdempotent(t *testing.T) {
+	srv, calls := newFlakyServer(t, 1, writeStatus(http.StatusBadGateway))
+	defer srv.Close()
+
+	tr, _ := NewTransport(srv.URL, nil)
+
+	var out string
+	r := NewRetry(tr, WithBackoff(time.Millisecond, time.Millisecond))
+	assert.Error(t, r.Call("eth_sendRawTransaction", &out, "0x"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	// opt in the retry of non idempotent methods
+	r = NewRetry(tr, WithBackoff(time.Millisecond, time.Millisecond), WithRetryNonIdempotent())
+	assert.NoError(t, r.Call("eth_sendRawTransaction", &out, "0x"))
+}
+
+func TestRetry_ContextCancel(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1000, writeStatus(http.StatusBadGateway))
+	defer srv.Close()
+
+	tr, _ := NewTransport(srv.URL, nil)
+	r := NewRetry(tr, WithBackoff(10*time.Millisecond, 10*time.Millisecond), WithMaxRetries(1000))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var out string
+	err := r.CallContext(ctx, "eth_blockNumber", &out)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRetry_BatchCall(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, writeStatus(http.StatusServiceUnavailable))
+	defer srv.Close()
+
+	tr, _ := NewTransport(srv.URL, nil)
+	r := NewRetry(tr, WithBackoff(time.Millisecond, time.Millisecond))
+
+	testBatchCall(t, r)
+}
+
+func TestRetry_Backoff(t *testing.T) {
+	r := NewRetry(nil, WithBackoff(100*time.Millisecond, time.Second), WithJitter(0))
+
+	assert.Equal(t, 100*time.Millisecond, r.backoff(0))
+	assert.Equal(t, 200*time.Millisecond, r.backoff(1))
+	assert.Equal(t, 800*time.Millisecond, r.backoff(3))
+	assert.Equal(t, time.Second, r.backoff(10))
+}
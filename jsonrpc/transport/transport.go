@@ -1,6 +1,9 @@
 package transport
 
 import (
+	"context"
+	"crypto/tls"
+	"net/http"
 	"os"
 	"strings"
 )
@@ -17,33 +20,199 @@ type Transport interface {
 	Close() error
 }
 
+// ContextTransport is a transport whose requests can be aborted with a context.
+// The transports of this package implement it, the client uses CallContext
+// instead of Call if it is available.
+type ContextTransport interface {
+	// CallContext makes a jsonrpc request that is aborted if the context is done
+	CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error
+}
+
+// CallContext makes the request with the context if the transport implements
+// ContextTransport. Otherwise, the context is only checked before the request.
+func CallContext(ctx context.Context, t Transport, method string, out interface{}, params ...interface{}) error {
+	if ctxTransport, ok := t.(ContextTransport); ok {
+		return ctxTransport.CallContext(ctx, method, out, params...)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return t.Call(method, out, params...)
+}
+
+// BatchTransport is a transport that can send several jsonrpc requests
+// in a single round trip
+type BatchTransport interface {
+	// BatchCall sends all the elements as a single jsonrpc batch request
+	BatchCall(elems []*BatchElem) error
+
+	// BatchCallContext sends the batch request and aborts it if the context is done
+	BatchCallContext(ctx context.Context, elems []*BatchElem) error
+}
+
+// BatchElem is a single jsonrpc request inside a batch. After the batch
+// call, Result holds the decoded response or Error is set with the
+// *codec.ErrorObject returned by the server for that element.
+type BatchElem struct {
+	Method string
+	Params []interface{}
+	Result interface{}
+	Error  error
+}
+
 // PubSubTransport is a transport that allows subscriptions
 type PubSubTransport interface {
 	// Subscribe starts a subscription to a new event
 	Subscribe(method string, callback func(b []byte)) (func() error, error)
 }
 
+// PubSubOptsTransport is a transport that allows subscriptions with params. The
+// client falls back to Subscribe for the subscriptions without params if the
+// transport does not implement it.
+type PubSubOptsTransport interface {
+	// SubscribeWithOpts starts a subscription with params and notifications
+	// about the reconnection of the transport
+	SubscribeWithOpts(ctx context.Context, opts *SubscribeOpts) (func() error, error)
+}
+
+// SubscribeOpts are the options to start a subscription
+type SubscribeOpts struct {
+	// Method is the subscription type (i.e. newHeads)
+	Method string
+
+	// Params are the extra params of the subscription (i.e. the logs filter)
+	Params []interface{}
+
+	// Callback is called with each notification of the subscription
+	Callback func(b []byte)
+
+	// Resumed is called after the subscription is started again on a new
+	// connection. Notifications sent while the transport was disconnected
+	// are lost and the caller may need to backfill them.
+	Resumed func()
+
+	// Failed is called if the subscription cannot be started again after
+	// a reconnection. The subscription is not active anymore.
+	Failed func(err error)
+}
+
+type headersKey struct{}
+
+// ContextWithHeaders returns a context with extra headers for the requests
+// made with it (i.e. to propagate a trace). The headers are only sent by the
+// http transport since the websocket and ipc requests share a connection.
+func ContextWithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := map[string]string{}
+	for k, v := range HeadersFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range headers {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// HeadersFromContext returns the extra headers of the context
+func HeadersFromContext(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(headersKey{}).(map[string]string)
+	return headers
+}
+
 const (
 	wsPrefix  = "ws://"
 	wssPrefix = "wss://"
 )
 
+// Config is the configuration of the transports created with NewTransport
+type Config struct {
+	// Auth sets the credentials of the http and websocket transports. By
+	// default, the userinfo of the url is used as a basic authentication.
+	Auth Auth
+
+	// HTTPClient is the client of the http transport
+	HTTPClient *http.Client
+
+	// TLSConfig is the tls configuration of the http and websocket transports.
+	// It is not used by the http transport if HTTPClient is set.
+	TLSConfig *tls.Config
+}
+
+type Option func(*Config)
+
+func WithAuth(auth Auth) Option {
+	return func(c *Config) {
+		c.Auth = auth
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Config) {
+		c.TLSConfig = config
+	}
+}
+
+// tlsConfig returns the tls configuration with the credentials of the auth
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	tlsAuth, ok := c.Auth.(TLSAuth)
+	if !ok {
+		return c.TLSConfig, nil
+	}
+	config := &tls.Config{}
+	if c.TLSConfig != nil {
+		config = c.TLSConfig.Clone()
+	}
+	if err := tlsAuth.ConfigureTLS(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // NewTransport creates a new transport object
-func NewTransport(url string, headers map[string]string) (Transport, error) {
-	if strings.HasPrefix(url, wsPrefix) || strings.HasPrefix(url, wssPrefix) {
-		t, err := newWebsocket(url, headers)
+func NewTransport(url string, headers map[string]string, opts ...Option) (Transport, error) {
+	config := &Config{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if _, err := os.Stat(url); err == nil {
+		// path exists, it could be an ipc path
+		t, err := newIPC(url)
 		if err != nil {
 			return nil, err
 		}
 		return t, nil
 	}
-	if _, err := os.Stat(url); err == nil {
-		// path exists, it could be an ipc path
-		t, err := newIPC(url)
+
+	url, basicAuth := basicAuthFromURL(url)
+	if config.Auth == nil && basicAuth != nil {
+		config.Auth = basicAuth
+	}
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(url, wsPrefix) || strings.HasPrefix(url, wssPrefix) {
+		t, err := newWebsocket(url, headers, config.Auth, tlsConfig)
 		if err != nil {
 			return nil, err
 		}
 		return t, nil
 	}
-	return newHTTP(url, headers), nil
+
+	t := newHTTP(url, headers)
+	t.auth = config.Auth
+	if config.HTTPClient != nil {
+		t.client = config.HTTPClient
+		t.transport, _ = config.HTTPClient.Transport.(*http.Transport)
+	} else if tlsConfig != nil {
+		t.transport.TLSClientConfig = tlsConfig
+	}
+	return t, nil
 }
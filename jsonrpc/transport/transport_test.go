@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallContext_HTTPCancel(t *testing.T) {
+	doneCh := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// hang until the client cancels the request
+		ioutil.ReadAll(r.Body)
+		<-r.Context().Done()
+		close(doneCh)
+	}))
+	defer srv.Close()
+
+	tr, err := NewTransport(srv.URL, nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var out string
+	err = CallContext(ctx, tr, "eth_blockNumber", &out)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	select {
+	case <-doneCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("request not aborted")
+	}
+}
+
+func TestCallContext_WebsocketCancel(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// read the requests but never reply
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	tr, err := NewTransport("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	var out string
+	err = CallContext(ctx, tr, "eth_blockNumber", &out)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// the handler is removed after the call is aborted
+	s := tr.(*stream)
+	s.handlerLock.Lock()
+	assert.Len(t, s.handler, 0)
+	s.handlerLock.Unlock()
+}
+
+// callOnlyTransport is a transport that does not implement ContextTransport
+type callOnlyTransport struct {
+	Transport
+	calls int
+}
+
+func (c *callOnlyTransport) Call(method string, out interface{}, params ...interface{}) error {
+	c.calls++
+	return nil
+}
+
+func TestCallContext_Fallback(t *testing.T) {
+	tr := &callOnlyTransport{}
+
+	var out string
+	assert.NoError(t, CallContext(context.Background(), tr, "eth_blockNumber", &out))
+	assert.Equal(t, 1, tr.calls)
+
+	// the context is checked before the call
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, CallContext(ctx, tr, "eth_blockNumber", &out))
+	assert.Equal(t, 1, tr.calls)
+}
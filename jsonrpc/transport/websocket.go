@@ -1,7 +1,10 @@
 package transport
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -12,24 +15,53 @@ import (
 	"github.com/umbracle/ethgo/jsonrpc/codec"
 )
 
-func newWebsocket(url string, headers map[string]string) (Transport, error) {
-	wsHeaders := http.Header{}
-	for k, v := range headers {
-		wsHeaders.Add(k, v)
+func newWebsocket(url string, headers map[string]string, auth Auth, tlsConfig *tls.Config) (Transport, error) {
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = tlsConfig
+
+	dial := func() (Codec, error) {
+		wsHeaders := http.Header{}
+		for k, v := range headers {
+			wsHeaders.Add(k, v)
+		}
+		if auth != nil {
+			// the credentials are set again on each reconnection (i.e. a new jwt token)
+			authorization, err := auth.Authorization(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			if authorization != "" {
+				wsHeaders.Set("Authorization", authorization)
+			}
+		}
+		wsConn, _, err := dialer.Dial(url, wsHeaders)
+		if err != nil {
+			return nil, err
+		}
+		return &websocketCodec{conn: wsConn}, nil
 	}
-	wsConn, _, err := websocket.DefaultDialer.Dial(url, wsHeaders)
+	codec, err := dial()
 	if err != nil {
 		return nil, err
 	}
-	codec := &websocketCodec{
-		conn: wsConn,
-	}
-	return newStream(codec)
+	return newStream(codec, dial)
 }
 
 // ErrTimeout happens when the websocket requests times out
 var ErrTimeout = fmt.Errorf("timeout")
 
+// ErrConnectionLost happens when the connection drops while the request is in-flight
+var ErrConnectionLost = fmt.Errorf("connection lost")
+
+const (
+	minReconnectBackoff = 100 * time.Millisecond
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// defaultTimeout is the timeout of a request if the context
+// does not have a deadline
+const defaultTimeout = 5 * time.Second
+
 type ackMessage struct {
 	buf []byte
 	err error
@@ -38,39 +70,70 @@ type ackMessage struct {
 type callback func(b []byte, err error)
 
 type stream struct {
-	seq   uint64
-	codec Codec
+	seq uint64
+
+	// codec is the current connection. It is replaced
+	// with a new one from dial when the connection drops.
+	codecLock sync.Mutex
+	codec     Codec
+	dial      func() (Codec, error)
 
 	// call handlers
 	handlerLock sync.Mutex
 	handler     map[uint64]callback
 
-	// subscriptions
+	// subs are the active subscriptions and subIDs the ones started in the
+	// current connection by the id assigned by the server. gen is the number
+	// of the current connection.
 	subsLock sync.Mutex
-	subs     map[string]func(b []byte)
+	subs     map[*subscription]struct{}
+	subIDs   map[string]*subscription
+	gen      uint64
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
 
-	closeCh chan struct{}
-	timer   *time.Timer
+// subscription is an active subscription that is
+// re-established if the connection drops
+type subscription struct {
+	id   string
+	opts *SubscribeOpts
 }
 
-func newStream(codec Codec) (*stream, error) {
+func newStream(codec Codec, dial func() (Codec, error)) (*stream, error) {
 	w := &stream{
 		codec:   codec,
+		dial:    dial,
 		closeCh: make(chan struct{}),
 		handler: map[uint64]callback{},
-		subs:    map[string]func(b []byte){},
+		subs:    map[*subscription]struct{}{},
+		subIDs:  map[string]*subscription{},
 	}
 
-	go w.listen()
+	go w.listen(codec)
 	return w, nil
 }
 
 // Close implements the the transport interface
 func (s *stream) Close() error {
-	close(s.closeCh)
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+	})
+
+	s.codecLock.Lock()
+	defer s.codecLock.Unlock()
+
 	return s.codec.Close()
 }
 
+func (s *stream) write(b []byte) error {
+	s.codecLock.Lock()
+	defer s.codecLock.Unlock()
+
+	return s.codec.Write(b)
+}
+
 func (s *stream) incSeq() uint64 {
 	return atomic.AddUint64(&s.seq, 1)
 }
@@ -84,22 +147,66 @@ func (s *stream) isClosed() bool {
 	}
 }
 
-func (s *stream) listen() {
+func (s *stream) listen(conn Codec) {
+	for {
+		s.readLoop(conn)
+
+		// release the dropped connection before dialing a new one
+		conn.Close()
+		if s.isClosed() {
+			return
+		}
+
+		// the connection dropped, fail the in-flight requests
+		s.failHandlers(ErrConnectionLost)
+		if s.dial == nil {
+			return
+		}
+
+		// the subscriptions are not active anymore in the server
+		s.subsLock.Lock()
+		s.gen++
+		gen := s.gen
+		subs := make([]*subscription, 0, len(s.subs))
+		for sub := range s.subs {
+			subs = append(subs, sub)
+		}
+		s.subIDs = map[string]*subscription{}
+		s.subsLock.Unlock()
+
+		conn = s.reconnect()
+		if conn == nil {
+			// the stream was closed while reconnecting
+			return
+		}
+		go s.resubscribe(gen, subs)
+	}
+}
+
+func (s *stream) readLoop(conn Codec) {
 	buf := []byte{}
 
 	for {
 		var err error
-		buf, err = s.codec.Read(buf[:0])
+		buf, err = conn.Read(buf[:0])
 		if err != nil {
-			if !s.isClosed() {
-				// log error
-			}
 			return
 		}
 
+		if isBatchResponse(buf) {
+			var resps []codec.Response
+			if err = json.Unmarshal(buf, &resps); err != nil {
+				continue
+			}
+			for _, resp := range resps {
+				go s.handleMsg(resp)
+			}
+			continue
+		}
+
 		var resp codec.Response
 		if err = json.Unmarshal(buf, &resp); err != nil {
-			return
+			continue
 		}
 
 		if resp.ID != 0 {
@@ -108,7 +215,7 @@ func (s *stream) listen() {
 			// handle subscription
 			var respSub codec.Request
 			if err = json.Unmarshal(buf, &respSub); err != nil {
-				return
+				continue
 			}
 
 			if respSub.Method == "eth_subscription" {
@@ -118,6 +225,141 @@ func (s *stream) listen() {
 	}
 }
 
+// reconnect dials a new connection with an exponential backoff until it
+// succeeds or the stream is closed
+func (s *stream) reconnect() Codec {
+	backoff := minReconnectBackoff
+	for {
+		select {
+		case <-time.After(backoff):
+		case <-s.closeCh:
+			return nil
+		}
+
+		conn, err := s.dial()
+		if err == nil {
+			s.codecLock.Lock()
+			defer s.codecLock.Unlock()
+
+			if s.isClosed() {
+				conn.Close()
+				return nil
+			}
+			s.codec = conn
+			return conn
+		}
+
+		backoff = nextBackoff(backoff)
+	}
+}
+
+func nextBackoff(backoff time.Duration) time.Duration {
+	if backoff *= 2; backoff > maxReconnectBackoff {
+		backoff = maxReconnectBackoff
+	}
+	return backoff
+}
+
+// resubscribeTimeout is the timeout of each attempt to start
+// again a subscription after a reconnection
+var resubscribeTimeout = defaultTimeout
+
+// resubscribe starts again the subscriptions in the new connection (number gen)
+// and maps the new subscription ids to the original callbacks. The failed attempts
+// are retried with the backoff of the reconnection unless the server rejects the
+// subscription. It stops if the connection drops again since the next connection
+// starts the subscriptions again.
+func (s *stream) resubscribe(gen uint64, subs []*subscription) {
+	for _, sub := range subs {
+		backoff := minReconnectBackoff
+		for {
+			current, active := s.subscriptionState(gen, sub)
+			if !current {
+				return
+			}
+			if !active {
+				// the subscription was cancelled
+				break
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), resubscribeTimeout)
+			id, err := s.subscribe(ctx, sub.opts)
+			cancel()
+
+			if err == nil {
+				if s.register(gen, sub, id) {
+					// notifications may have been lost while disconnected
+					if sub.opts.Resumed != nil {
+						go sub.opts.Resumed()
+					}
+				}
+				break
+			}
+
+			var obj *codec.ErrorObject
+			if errors.As(err, &obj) {
+				// the server rejects the subscription
+				s.subsLock.Lock()
+				delete(s.subs, sub)
+				s.subsLock.Unlock()
+
+				if sub.opts.Failed != nil {
+					go sub.opts.Failed(err)
+				}
+				break
+			}
+
+			select {
+			case <-time.After(backoff):
+			case <-s.closeCh:
+				return
+			}
+			backoff = nextBackoff(backoff)
+		}
+	}
+}
+
+// subscriptionState returns whether gen is the current connection
+// and the subscription is still active
+func (s *stream) subscriptionState(gen uint64, sub *subscription) (bool, bool) {
+	s.subsLock.Lock()
+	defer s.subsLock.Unlock()
+
+	_, active := s.subs[sub]
+	return s.gen == gen, active
+}
+
+// register maps the id of the subscription started in the connection number
+// gen to the subscription. It returns false if the subscription was cancelled
+// or the connection dropped while the subscription was started.
+func (s *stream) register(gen uint64, sub *subscription, id string) bool {
+	s.subsLock.Lock()
+	defer s.subsLock.Unlock()
+
+	if s.gen != gen {
+		return false
+	}
+	if _, ok := s.subs[sub]; !ok {
+		// cancel the subscription started in the server
+		go s.Call("eth_unsubscribe", new(bool), id)
+		return false
+	}
+	sub.id = id
+	s.subIDs[id] = sub
+	return true
+}
+
+func (s *stream) failHandlers(err error) {
+	s.handlerLock.Lock()
+	handlers := s.handler
+	s.handler = map[uint64]callback{}
+	s.handlerLock.Unlock()
+
+	for _, callback := range handlers {
+		callback(nil, err)
+	}
+}
+
 func (s *stream) handleSubscription(response codec.Request) {
 	var sub codec.Subscription
 	if err := json.Unmarshal(response.Params, &sub); err != nil {
@@ -125,7 +367,7 @@ func (s *stream) handleSubscription(response codec.Request) {
 	}
 
 	s.subsLock.Lock()
-	callback, ok := s.subs[sub.ID]
+	subscription, ok := s.subIDs[sub.ID]
 	s.subsLock.Unlock()
 
 	if !ok {
@@ -133,7 +375,7 @@ func (s *stream) handleSubscription(response codec.Request) {
 	}
 
 	// call the callback function
-	callback(sub.Result)
+	subscription.opts.Callback(sub.Result)
 }
 
 func (s *stream) handleMsg(response codec.Response) {
@@ -166,64 +408,173 @@ func (s *stream) setHandler(id uint64, ack chan *ackMessage) {
 	s.handlerLock.Lock()
 	s.handler[id] = callback
 	s.handlerLock.Unlock()
+}
 
-	s.timer = time.AfterFunc(5*time.Second, func() {
-		s.handlerLock.Lock()
+func (s *stream) removeHandlers(ids ...uint64) {
+	s.handlerLock.Lock()
+	for _, id := range ids {
 		delete(s.handler, id)
-		s.handlerLock.Unlock()
+	}
+	s.handlerLock.Unlock()
+}
 
-		select {
-		case ack <- &ackMessage{nil, ErrTimeout}:
-		default:
-		}
-	})
+// withTimeout sets the default request timeout if the context
+// does not have a deadline already
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc, bool) {
+	if _, ok := ctx.Deadline(); ok {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	return ctx, cancel, true
+}
+
+// ctxErr returns the error for a request whose context is done
+func ctxErr(ctx context.Context, defaultDeadline bool) error {
+	if defaultDeadline && ctx.Err() == context.DeadlineExceeded {
+		return ErrTimeout
+	}
+	return ctx.Err()
 }
 
 // Call implements the transport interface
 func (s *stream) Call(method string, out interface{}, params ...interface{}) error {
+	return s.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext implements the transport interface
+func (s *stream) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
 	seq := s.incSeq()
-	request := codec.Request{
-		JsonRPC: "2.0",
-		ID:      seq,
-		Method:  method,
+	request, err := codec.NewRequest(seq, method, params)
+	if err != nil {
+		return err
 	}
-	if len(params) > 0 {
-		data, err := json.Marshal(params)
-		if err != nil {
-			return err
-		}
-		request.Params = data
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return err
 	}
 
-	ack := make(chan *ackMessage)
+	ctx, cancel, defaultDeadline := withTimeout(ctx)
+	defer cancel()
+
+	ack := make(chan *ackMessage, 1)
 	s.setHandler(seq, ack)
+	defer s.removeHandlers(seq)
 
-	raw, err := json.Marshal(request)
-	if err != nil {
+	if err := s.write(raw); err != nil {
 		return err
 	}
-	if err := s.codec.Write(raw); err != nil {
+
+	select {
+	case resp := <-ack:
+		if resp.err != nil {
+			return resp.err
+		}
+		if err := json.Unmarshal(resp.buf, out); err != nil {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+		return ctxErr(ctx, defaultDeadline)
+	}
+}
+
+// BatchCall implements the BatchTransport interface
+func (s *stream) BatchCall(elems []*BatchElem) error {
+	return s.BatchCallContext(context.Background(), elems)
+}
+
+// BatchCallContext implements the BatchTransport interface
+func (s *stream) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(elems))
+	requests := make([]*codec.Request, len(elems))
+	acks := make([]chan *ackMessage, len(elems))
+	for indx, elem := range elems {
+		request, err := codec.NewRequest(s.incSeq(), elem.Method, elem.Params)
+		if err != nil {
+			return err
+		}
+		ids[indx] = request.ID
+		requests[indx] = request
+
+		// the responses are consumed in order, buffer the channel
+		// so that an early response is not lost
+		acks[indx] = make(chan *ackMessage, 1)
+	}
+
+	raw, err := json.Marshal(requests)
+	if err != nil {
 		return err
 	}
 
-	resp := <-ack
-	if resp.err != nil {
-		return resp.err
+	ctx, cancel, defaultDeadline := withTimeout(ctx)
+	defer cancel()
+
+	for indx, id := range ids {
+		s.setHandler(id, acks[indx])
 	}
-	if err := json.Unmarshal(resp.buf, out); err != nil {
+	defer s.removeHandlers(ids...)
+
+	if err := s.write(raw); err != nil {
 		return err
 	}
+
+	for indx, elem := range elems {
+		select {
+		case resp := <-acks[indx]:
+			if resp.err != nil {
+				elem.Error = resp.err
+				continue
+			}
+			if elem.Result != nil {
+				if err := json.Unmarshal(resp.buf, elem.Result); err != nil {
+					elem.Error = err
+				}
+			}
+		case <-ctx.Done():
+			return ctxErr(ctx, defaultDeadline)
+		}
+	}
 	return nil
 }
 
-func (s *stream) unsubscribe(id string) error {
+func isBatchResponse(buf []byte) bool {
+	for _, b := range buf {
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '[':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func (s *stream) unsubscribe(sub *subscription) error {
 	s.subsLock.Lock()
-	defer s.subsLock.Unlock()
+	if _, ok := s.subs[sub]; !ok {
+		s.subsLock.Unlock()
+		return fmt.Errorf("subscription %s not found", sub.id)
+	}
+	delete(s.subs, sub)
+
+	id := sub.id
+	started := s.subIDs[id] == sub
+	if started {
+		delete(s.subIDs, id)
+	}
+	s.subsLock.Unlock()
 
-	if _, ok := s.subs[id]; !ok {
-		return fmt.Errorf("subscription %s not found", id)
+	if !started {
+		// the subscription is not started in the current connection
+		return nil
 	}
-	delete(s.subs, id)
 
 	var result bool
 	if err := s.Call("eth_unsubscribe", &result, id); err != nil {
@@ -235,23 +586,51 @@ func (s *stream) unsubscribe(id string) error {
 	return nil
 }
 
-func (s *stream) setSubscription(id string, callback func(b []byte)) {
-	s.subsLock.Lock()
-	defer s.subsLock.Unlock()
+func (s *stream) subscribe(ctx context.Context, opts *SubscribeOpts) (string, error) {
+	params := append([]interface{}{opts.Method}, opts.Params...)
 
-	s.subs[id] = callback
+	var out string
+	if err := s.CallContext(ctx, "eth_subscribe", &out, params...); err != nil {
+		return "", err
+	}
+	return out, nil
 }
 
 // Subscribe implements the PubSubTransport interface
 func (s *stream) Subscribe(method string, callback func(b []byte)) (func() error, error) {
-	var out string
-	if err := s.Call("eth_subscribe", &out, method); err != nil {
+	return s.SubscribeWithOpts(context.Background(), &SubscribeOpts{
+		Method:   method,
+		Callback: callback,
+	})
+}
+
+// SubscribeWithOpts implements the PubSubOptsTransport interface
+func (s *stream) SubscribeWithOpts(ctx context.Context, opts *SubscribeOpts) (func() error, error) {
+	s.subsLock.Lock()
+	gen := s.gen
+	s.subsLock.Unlock()
+
+	id, err := s.subscribe(ctx, opts)
+	if err != nil {
 		return nil, err
 	}
 
-	s.setSubscription(out, callback)
+	sub := &subscription{
+		id:   id,
+		opts: opts,
+	}
+	s.subsLock.Lock()
+	if s.gen != gen {
+		// the connection dropped before the subscription was registered
+		s.subsLock.Unlock()
+		return nil, ErrConnectionLost
+	}
+	s.subs[sub] = struct{}{}
+	s.subIDs[id] = sub
+	s.subsLock.Unlock()
+
 	cancel := func() error {
-		return s.unsubscribe(out)
+		return s.unsubscribe(sub)
 	}
 	return cancel, nil
 }
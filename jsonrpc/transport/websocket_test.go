@@ -0,0 +1,298 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo/jsonrpc/codec"
+)
+
+// subServer is a websocket server that assigns a new id to each
+// eth_subscribe request and can drop the active connections
+type subServer struct {
+	*httptest.Server
+
+	lock  sync.Mutex
+	conns []*websocket.Conn
+	subs  map[string]*websocket.Conn
+	seq   int
+
+	// hang is the method that never gets a reply
+	hang string
+
+	// hangSubscribe is the number of eth_subscribe requests without reply
+	hangSubscribe int
+}
+
+func newSubServer(t *testing.T) *subServer {
+	s := &subServer{
+		subs: map[string]*websocket.Conn{},
+		hang: "hang",
+	}
+
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.lock.Lock()
+		s.conns = append(s.conns, conn)
+		s.lock.Unlock()
+
+		for {
+			_, raw, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req codec.Request
+			assert.NoError(t, json.Unmarshal(raw, &req))
+
+			if req.Method == s.hang {
+				continue
+			}
+			if req.Method == "eth_subscribe" {
+				s.lock.Lock()
+				hang := s.hangSubscribe > 0
+				if hang {
+					s.hangSubscribe--
+				}
+				s.lock.Unlock()
+				if hang {
+					continue
+				}
+			}
+
+			resp := &codec.Response{ID: req.ID}
+			if req.Method == "eth_subscribe" {
+				s.lock.Lock()
+				s.seq++
+				id := fmt.Sprintf("0x%d", s.seq)
+				s.subs[id] = conn
+				s.lock.Unlock()
+
+				resp.Result, _ = json.Marshal(id)
+			} else {
+				resp.Result, _ = json.Marshal(true)
+			}
+
+			s.lock.Lock()
+			err = conn.WriteJSON(resp)
+			s.lock.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}))
+	return s
+}
+
+func (s *subServer) wsAddr() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+// notify sends a notification to all the subscriptions
+func (s *subServer) notify(result string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, conn := range s.subs {
+		params, _ := json.Marshal(&codec.Subscription{ID: id, Result: json.RawMessage(`"` + result + `"`)})
+		conn.WriteJSON(&codec.Request{JsonRPC: "2.0", Method: "eth_subscription", Params: params})
+	}
+}
+
+// drop closes all the open connections
+func (s *subServer) drop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+	s.subs = map[string]*websocket.Conn{}
+}
+
+func TestWebsocket_Reconnect(t *testing.T) {
+	srv := newSubServer(t)
+	defer srv.Close()
+
+	tr, err := NewTransport(srv.wsAddr(), nil)
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	dataCh := make(chan string, 10)
+	resumedCh := make(chan struct{}, 1)
+
+	_, err = tr.(PubSubOptsTransport).SubscribeWithOpts(context.Background(), &SubscribeOpts{
+		Method: "newHeads",
+		Callback: func(b []byte) {
+			var res string
+			json.Unmarshal(b, &res)
+			dataCh <- res
+		},
+		Resumed: func() {
+			resumedCh <- struct{}{}
+		},
+	})
+	assert.NoError(t, err)
+
+	recv := func(expected string) {
+		select {
+		case res := <-dataCh:
+			assert.Equal(t, expected, res)
+		case <-time.After(2 * time.Second):
+			t.Fatal("notification not received")
+		}
+	}
+
+	srv.notify("a")
+	recv("a")
+
+	// in-flight requests fail when the connection drops
+	errCh := make(chan error)
+	go func() {
+		var out bool
+		errCh <- tr.Call("hang", &out)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	srv.drop()
+	assert.Equal(t, ErrConnectionLost, <-errCh)
+
+	select {
+	case <-resumedCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription not resumed")
+	}
+
+	// the new subscription id maps to the original callback
+	srv.notify("b")
+	recv("b")
+
+	var out bool
+	assert.NoError(t, tr.Call("eth_blockNumber", &out))
+}
+
+func TestWebsocket_ResubscribeRetry(t *testing.T) {
+	timeout := resubscribeTimeout
+	resubscribeTimeout = 100 * time.Millisecond
+	defer func() {
+		resubscribeTimeout = timeout
+	}()
+
+	srv := newSubServer(t)
+	defer srv.Close()
+
+	tr, err := NewTransport(srv.wsAddr(), nil)
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	dataCh := make(chan string, 10)
+	resumedCh := make(chan struct{}, 1)
+	failedCh := make(chan error, 1)
+
+	_, err = tr.(PubSubOptsTransport).SubscribeWithOpts(context.Background(), &SubscribeOpts{
+		Method: "newHeads",
+		Callback: func(b []byte) {
+			var res string
+			json.Unmarshal(b, &res)
+			dataCh <- res
+		},
+		Resumed: func() {
+			resumedCh <- struct{}{}
+		},
+		Failed: func(err error) {
+			failedCh <- err
+		},
+	})
+	assert.NoError(t, err)
+
+	// the first attempts to subscribe again time out
+	srv.lock.Lock()
+	srv.hangSubscribe = 2
+	srv.lock.Unlock()
+
+	srv.drop()
+
+	select {
+	case <-resumedCh:
+	case err := <-failedCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("subscription not resumed")
+	}
+
+	srv.notify("a")
+	select {
+	case res := <-dataCh:
+		assert.Equal(t, "a", res)
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not received")
+	}
+}
+
+// mockCodec is a codec whose reads fail once readErr is closed
+type mockCodec struct {
+	readErr chan struct{}
+
+	lock   sync.Mutex
+	closed bool
+}
+
+func (m *mockCodec) Read(b []byte) ([]byte, error) {
+	<-m.readErr
+	return nil, fmt.Errorf("connection dropped")
+}
+
+func (m *mockCodec) Write(b []byte) error {
+	return nil
+}
+
+func (m *mockCodec) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.closed = true
+	return nil
+}
+
+func (m *mockCodec) isClosed() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.closed
+}
+
+func TestWebsocket_CloseDroppedConnection(t *testing.T) {
+	first := &mockCodec{readErr: make(chan struct{})}
+	second := &mockCodec{readErr: make(chan struct{})}
+
+	dialCh := make(chan struct{})
+	s, err := newStream(first, func() (Codec, error) {
+		close(dialCh)
+		return second, nil
+	})
+	assert.NoError(t, err)
+	defer s.Close()
+
+	close(first.readErr)
+
+	select {
+	case <-dialCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("not reconnected")
+	}
+
+	// the dropped connection is closed before the new one is dialed
+	assert.True(t, first.isClosed())
+	assert.False(t, second.isClosed())
+}
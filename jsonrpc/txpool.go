@@ -0,0 +1,79 @@
+package jsonrpc
+
+import (
+	"context"
+
+	"github.com/umbracle/ethgo"
+)
+
+// TxPool is the txpool namespace to inspect the transactions in the mempool
+type TxPool struct {
+	c   *Client
+	ctx context.Context
+}
+
+// TxPool returns the reference to the txpool namespace
+func (c *Client) TxPool() *TxPool {
+	return c.endpoints.txpool
+}
+
+// WithContext returns a copy of the txpool namespace whose requests
+// are aborted when the context is done
+func (t *TxPool) WithContext(ctx context.Context) *TxPool {
+	return &TxPool{c: t.c, ctx: ctx}
+}
+
+// TxPoolContent are the transactions in the pool by sender and nonce.
+// Pending transactions can be included in the next block while the queued
+// transactions have a gap in the nonce or not enough balance.
+type TxPoolContent struct {
+	Pending map[ethgo.Address]map[uint64]*ethgo.Transaction `json:"pending"`
+	Queued  map[ethgo.Address]map[uint64]*ethgo.Transaction `json:"queued"`
+}
+
+// TxPoolContentFrom are the transactions in the pool of a sender by nonce
+type TxPoolContentFrom struct {
+	Pending map[uint64]*ethgo.Transaction `json:"pending"`
+	Queued  map[uint64]*ethgo.Transaction `json:"queued"`
+}
+
+// TxPoolInspect is the summary of the transactions in the pool by sender and nonce
+// (i.e. 0x0216d5032f356960cd3749c31ab34eeff21b3395: 1 wei + 21000 gas × 1000000000 wei)
+type TxPoolInspect struct {
+	Pending map[ethgo.Address]map[uint64]string `json:"pending"`
+	Queued  map[ethgo.Address]map[uint64]string `json:"queued"`
+}
+
+// TxPoolStatus is the number of transactions in the pool
+type TxPoolStatus struct {
+	Pending ethgo.ArgUint64 `json:"pending"`
+	Queued  ethgo.ArgUint64 `json:"queued"`
+}
+
+// Content returns the transactions in the pool
+func (t *TxPool) Content() (*TxPoolContent, error) {
+	var res *TxPoolContent
+	err := t.c.CallContext(t.ctx, "txpool_content", &res)
+	return res, err
+}
+
+// ContentFrom returns the transactions in the pool sent by the address
+func (t *TxPool) ContentFrom(addr ethgo.Address) (*TxPoolContentFrom, error) {
+	var res *TxPoolContentFrom
+	err := t.c.CallContext(t.ctx, "txpool_contentFrom", &res, addr)
+	return res, err
+}
+
+// Inspect returns a summary of the transactions in the pool
+func (t *TxPool) Inspect() (*TxPoolInspect, error) {
+	var res *TxPoolInspect
+	err := t.c.CallContext(t.ctx, "txpool_inspect", &res)
+	return res, err
+}
+
+// Status returns the number of transactions in the pool
+func (t *TxPool) Status() (*TxPoolStatus, error) {
+	var res *TxPoolStatus
+	err := t.c.CallContext(t.ctx, "txpool_status", &res)
+	return res, err
+}
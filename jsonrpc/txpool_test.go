@@ -0,0 +1,88 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+)
+
+func TestTxPool(t *testing.T) {
+	legacyTxn := `{
+		"blockHash": null,
+		"blockNumber": null,
+		"from": "0x0100000000000000000000000000000000000000",
+		"gas": "0x5208",
+		"gasPrice": "0x3b9aca00",
+		"hash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+		"input": "0x",
+		"nonce": "0x1",
+		"to": "0x0200000000000000000000000000000000000000",
+		"transactionIndex": null,
+		"value": "0x1",
+		"type": "0x0",
+		"v": "0x25",
+		"r": "0x1",
+		"s": "0x1"
+	}`
+
+	// dynamic fee transaction without gas price nor signature and with data instead of input
+	dynamicTxn := `{
+		"from": "0x0100000000000000000000000000000000000000",
+		"gas": "0x5208",
+		"maxFeePerGas": "0x2",
+		"maxPriorityFeePerGas": "0x1",
+		"hash": "0x0000000000000000000000000000000000000000000000000000000000000002",
+		"data": "0x01",
+		"nonce": "0x5",
+		"to": null,
+		"value": "0x0",
+		"type": "0x2"
+	}`
+
+	srv, params := newStubServer(t, map[string]string{
+		"txpool_content": `{
+			"pending": {"0x0100000000000000000000000000000000000000": {"1": ` + legacyTxn + `}},
+			"queued": {"0x0100000000000000000000000000000000000000": {"5": ` + dynamicTxn + `}}
+		}`,
+		"txpool_contentFrom": `{"pending": {"1": ` + legacyTxn + `}, "queued": {}}`,
+		"txpool_inspect": `{
+			"pending": {"0x0100000000000000000000000000000000000000": {"1": "0x0200000000000000000000000000000000000000: 1 wei + 21000 gas × 1000000000 wei"}},
+			"queued": {}
+		}`,
+		"txpool_status": `{"pending": "0x1", "queued": "0x1"}`,
+	})
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL)
+
+	content, err := c.TxPool().Content()
+	assert.NoError(t, err)
+
+	pending := content.Pending[addr0][1]
+	assert.Equal(t, ethgo.TransactionLegacy, pending.Type)
+	assert.Equal(t, uint64(1000000000), pending.GasPrice)
+	assert.Equal(t, addr1, *pending.To)
+
+	queued := content.Queued[addr0][5]
+	assert.Equal(t, ethgo.TransactionDynamicFee, queued.Type)
+	assert.Equal(t, uint64(5), queued.Nonce)
+	assert.Equal(t, uint64(2), queued.MaxFeePerGas.Uint64())
+	assert.Equal(t, []byte{0x1}, queued.Input)
+	assert.Nil(t, queued.To)
+
+	contentFrom, err := c.TxPool().ContentFrom(addr0)
+	assert.NoError(t, err)
+	assert.Equal(t, `["0x0100000000000000000000000000000000000000"]`, string(params["txpool_contentFrom"]))
+	assert.Equal(t, pending, contentFrom.Pending[1])
+	assert.Len(t, contentFrom.Queued, 0)
+
+	inspect, err := c.TxPool().Inspect()
+	assert.NoError(t, err)
+	assert.Contains(t, inspect.Pending[addr0][1], "21000 gas")
+
+	status, err := c.TxPool().Status()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), status.Pending.Uint64())
+	assert.Equal(t, uint64(1), status.Queued.Uint64())
+}
@@ -2,10 +2,13 @@ package jsonrpc
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
 	"strings"
+
+	"github.com/umbracle/ethgo"
 )
 
 type ArgBig big.Int
@@ -64,3 +67,12 @@ func parseHexBytes(str string) ([]byte, error) {
 	}
 	return buf, nil
 }
+
+// blockParam returns the block selector of a request. The selectors with
+// a json encoding (i.e. ethgo.BlockHashSelector) are sent as EIP-1898 objects.
+func blockParam(block ethgo.BlockNumberOrHash) interface{} {
+	if _, ok := block.(json.Marshaler); ok {
+		return block
+	}
+	return block.Location()
+}
@@ -2,6 +2,7 @@ package ethgo
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -19,7 +20,11 @@ var (
 // Address is an Ethereum address
 type Address [20]byte
 
-// HexToAddress converts an hex string value to an address object
+// ErrInvalidChecksum is returned if the checksum of a mixed case address is not valid
+var ErrInvalidChecksum = errors.New("invalid address checksum")
+
+// HexToAddress converts an hex string value to an address object. It does not
+// validate the input, use ParseAddress to check the length and the checksum.
 func HexToAddress(str string) Address {
 	a := Address{}
 	a.UnmarshalText(completeHex(str, 20))
@@ -47,7 +52,41 @@ func (a Address) Sign(hash []byte) ([]byte, error) {
 	panic("an address cannot sign messages")
 }
 
-// UnmarshalText implements the unmarshal interface
+// ParseAddress parses a 0x prefixed hex address. If the address has mixed
+// case it must have a valid checksum (EIP-55).
+func ParseAddress(str string) (Address, error) {
+	return parseAddress(str, 0)
+}
+
+// ParseAddressWithChainID parses a 0x prefixed hex address. If the address has
+// mixed case it must have a valid checksum for the chain (EIP-1191).
+func ParseAddressWithChainID(str string, chainID uint64) (Address, error) {
+	return parseAddress(str, chainID)
+}
+
+func parseAddress(str string, chainID uint64) (Address, error) {
+	var a Address
+	if !strings.HasPrefix(str, "0x") {
+		return a, fmt.Errorf("0x prefix not found in address '%s'", str)
+	}
+	if len(str) != 42 {
+		return a, fmt.Errorf("address '%s' has length %d, expected 42", str, len(str))
+	}
+	if _, err := hex.Decode(a[:], []byte(str[2:])); err != nil {
+		return Address{}, fmt.Errorf("address '%s' is not hex: %v", str, err)
+	}
+	// an address in lower or upper case does not have checksum
+	digits := str[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) {
+		if a.checksumEncode(chainID) != str {
+			return Address{}, ErrInvalidChecksum
+		}
+	}
+	return a, nil
+}
+
+// UnmarshalText implements the unmarshal interface. It does not
+// validate the checksum, use StrictAddress to validate it.
 func (a *Address) UnmarshalText(b []byte) error {
 	return unmarshalTextByte(a[:], b, 20)
 }
@@ -63,12 +102,51 @@ func (a Address) Bytes() []byte {
 }
 
 func (a Address) String() string {
-	return a.checksumEncode()
+	return a.checksumEncode(0)
+}
+
+// ChecksumString returns the address with the checksum of the chain (EIP-1191).
+// A zero chain id returns the checksum without chain (EIP-55).
+func (a Address) ChecksumString(chainID uint64) string {
+	return a.checksumEncode(chainID)
+}
+
+// StrictAddress is an address that is only decoded from text (and json) if it
+// has the 0x prefix and, for mixed case addresses, a valid checksum (EIP-55)
+type StrictAddress Address
+
+// Address returns the address
+func (s StrictAddress) Address() Address {
+	return Address(s)
+}
+
+func (s StrictAddress) String() string {
+	return Address(s).String()
+}
+
+// MarshalText implements the marshal interface
+func (s StrictAddress) MarshalText() ([]byte, error) {
+	return Address(s).MarshalText()
+}
+
+// UnmarshalText implements the unmarshal interface
+func (s *StrictAddress) UnmarshalText(b []byte) error {
+	addr, err := ParseAddress(strings.Trim(string(b), "\""))
+	if err != nil {
+		return err
+	}
+	*s = StrictAddress(addr)
+	return nil
 }
 
-func (a Address) checksumEncode() string {
+func (a Address) checksumEncode(chainID uint64) string {
 	address := strings.ToLower(hex.EncodeToString(a[:]))
-	hash := hex.EncodeToString(Keccak256([]byte(address)))
+
+	data := address
+	if chainID != 0 {
+		data = strconv.FormatUint(chainID, 10) + "0x" + address
+	}
+	hash := hex.EncodeToString(Keccak256([]byte(data)))
 
 	ret := "0x"
 	for i := 0; i < len(address); i++ {
@@ -143,9 +221,27 @@ type Block struct {
 	GasLimit           uint64
 	GasUsed            uint64
 	Timestamp          uint64
+	LogsBloom          []byte
+	MixHash            Hash
+	Nonce              [8]byte
 	Transactions       []*Transaction
 	TransactionsHashes []Hash
 	Uncles             []Hash
+
+	// eip-1559 values
+	BaseFeePerGas *big.Int
+
+	// eip-4895 values (shanghai)
+	WithdrawalsRoot *Hash
+	Withdrawals     []*Withdrawal
+
+	// eip-4844 and eip-4788 values (cancun)
+	BlobGasUsed           *uint64
+	ExcessBlobGas         *uint64
+	ParentBeaconBlockRoot *Hash
+
+	// eip-7685 values (prague)
+	RequestsHash *Hash
 }
 
 func (b *Block) Copy() *Block {
@@ -155,13 +251,53 @@ func (b *Block) Copy() *Block {
 		bb.Difficulty = new(big.Int).Set(b.Difficulty)
 	}
 	bb.ExtraData = append(bb.ExtraData[:0], b.ExtraData...)
+	bb.LogsBloom = append(bb.LogsBloom[:0], b.LogsBloom...)
 	bb.Transactions = make([]*Transaction, len(b.Transactions))
 	for indx, txn := range b.Transactions {
 		bb.Transactions[indx] = txn.Copy()
 	}
+	if b.BaseFeePerGas != nil {
+		bb.BaseFeePerGas = new(big.Int).Set(b.BaseFeePerGas)
+	}
+	if b.WithdrawalsRoot != nil {
+		root := *b.WithdrawalsRoot
+		bb.WithdrawalsRoot = &root
+	}
+	if b.Withdrawals != nil {
+		bb.Withdrawals = make([]*Withdrawal, len(b.Withdrawals))
+		for indx, w := range b.Withdrawals {
+			ww := *w
+			bb.Withdrawals[indx] = &ww
+		}
+	}
+	if b.BlobGasUsed != nil {
+		blobGasUsed := *b.BlobGasUsed
+		bb.BlobGasUsed = &blobGasUsed
+	}
+	if b.ExcessBlobGas != nil {
+		excessBlobGas := *b.ExcessBlobGas
+		bb.ExcessBlobGas = &excessBlobGas
+	}
+	if b.ParentBeaconBlockRoot != nil {
+		root := *b.ParentBeaconBlockRoot
+		bb.ParentBeaconBlockRoot = &root
+	}
+	if b.RequestsHash != nil {
+		hash := *b.RequestsHash
+		bb.RequestsHash = &hash
+	}
 	return bb
 }
 
+// Withdrawal is a withdrawal of the consensus layer (eip-4895).
+// The amount is in gwei.
+type Withdrawal struct {
+	Index          uint64
+	ValidatorIndex uint64
+	Address        Address
+	Amount         uint64
+}
+
 type TransactionType int
 
 const (
@@ -170,6 +306,10 @@ const (
 	TransactionAccessList TransactionType = 1
 	// eip-1559
 	TransactionDynamicFee TransactionType = 2
+	// eip-4844
+	TransactionBlob TransactionType = 3
+	// eip-7702
+	TransactionSetCode TransactionType = 4
 )
 
 type Transaction struct {
@@ -200,6 +340,17 @@ type Transaction struct {
 	// eip-1559 values
 	MaxPriorityFeePerGas *big.Int
 	MaxFeePerGas         *big.Int
+
+	// eip-4844 values
+	MaxFeePerBlobGas    *big.Int
+	BlobVersionedHashes []Hash
+
+	// Sidecar are the blobs of the transaction. It is only used to send
+	// the transaction and it is not part of the hash of the transaction.
+	Sidecar *BlobSidecar
+
+	// eip-7702 values
+	AuthorizationList []*Authorization
 }
 
 func (t *Transaction) Copy() *Transaction {
@@ -224,9 +375,46 @@ func (t *Transaction) Copy() *Transaction {
 	if t.MaxFeePerGas != nil {
 		tt.MaxFeePerGas = new(big.Int).Set(t.MaxFeePerGas)
 	}
+	if t.MaxFeePerBlobGas != nil {
+		tt.MaxFeePerBlobGas = new(big.Int).Set(t.MaxFeePerBlobGas)
+	}
+	if t.BlobVersionedHashes != nil {
+		tt.BlobVersionedHashes = append([]Hash{}, t.BlobVersionedHashes...)
+	}
+	if t.Sidecar != nil {
+		tt.Sidecar = t.Sidecar.Copy()
+	}
+	if t.AuthorizationList != nil {
+		tt.AuthorizationList = make([]*Authorization, len(t.AuthorizationList))
+		for indx, auth := range t.AuthorizationList {
+			tt.AuthorizationList[indx] = auth.Copy()
+		}
+	}
 	return tt
 }
 
+// Authorization allows the code of the address to be set as the code of the
+// signer account (eip-7702). A chain id of zero makes it valid on any chain.
+type Authorization struct {
+	ChainID *big.Int
+	Address Address
+	Nonce   uint64
+	YParity uint64
+	R       []byte
+	S       []byte
+}
+
+func (a *Authorization) Copy() *Authorization {
+	aa := new(Authorization)
+	*aa = *a
+	if a.ChainID != nil {
+		aa.ChainID = new(big.Int).Set(a.ChainID)
+	}
+	aa.R = append([]byte(nil), a.R...)
+	aa.S = append([]byte(nil), a.S...)
+	return aa
+}
+
 type AccessEntry struct {
 	Address Address `json:"address"`
 	Storage []Hash  `json:"storageKeys"`
@@ -241,6 +429,11 @@ type CallMsg struct {
 	GasPrice uint64
 	Gas      *big.Int
 	Value    *big.Int
+
+	// eip-1559 and eip-2930 values
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	AccessList           AccessList
 }
 
 type LogFilter struct {
@@ -265,23 +458,132 @@ func (l *LogFilter) SetTo(b BlockNumber) {
 	l.To = &b
 }
 
+// Match returns true if the log is emitted by one of the addresses of the filter (if any)
+// and, for each position of the topics of the filter, the topic of the log is one of the
+// topics in that position. An empty position (or a nil topic) matches any topic.
+// The block hash and the block range of the filter are not checked.
+func (l *LogFilter) Match(log *Log) bool {
+	if len(l.Address) != 0 {
+		found := false
+		for _, addr := range l.Address {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(l.Topics) > len(log.Topics) {
+		return false
+	}
+	for indx, topics := range l.Topics {
+		if isWildcard(topics) {
+			continue
+		}
+		found := false
+		for _, topic := range topics {
+			if topic != nil && *topic == log.Topics[indx] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchBloom returns false if the logs of the bloom cannot match the filter. It is
+// used to skip the blocks without logs for the filter before querying their logs.
+func (l *LogFilter) MatchBloom(bloom Bloom) bool {
+	if len(l.Address) != 0 {
+		found := false
+		for _, addr := range l.Address {
+			if bloom.Test(addr[:]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, topics := range l.Topics {
+		if isWildcard(topics) {
+			continue
+		}
+		found := false
+		for _, topic := range topics {
+			if topic != nil && bloom.Test(topic[:]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isWildcard returns true if the position of the topics matches any topic
+func isWildcard(topics []*Hash) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	for _, topic := range topics {
+		if topic == nil {
+			return true
+		}
+	}
+	return false
+}
+
 type Receipt struct {
+	Type              TransactionType
 	TransactionHash   Hash
 	TransactionIndex  uint64
 	ContractAddress   Address
 	BlockHash         Hash
 	From              Address
+	To                *Address
 	BlockNumber       uint64
 	GasUsed           uint64
 	CumulativeGasUsed uint64
+	EffectiveGasPrice *big.Int
 	LogsBloom         []byte
 	Logs              []*Log
 	Status            uint64
+
+	// Root is the state root after the transaction. It is only
+	// set in the receipts before byzantium instead of the status.
+	Root *Hash
+
+	// BlobGasUsed and BlobGasPrice are only set for blob transactions
+	BlobGasUsed  uint64
+	BlobGasPrice *big.Int
 }
 
 func (r *Receipt) Copy() *Receipt {
 	rr := new(Receipt)
 	*rr = *r
+	if r.To != nil {
+		to := *r.To
+		rr.To = &to
+	}
+	if r.Root != nil {
+		root := *r.Root
+		rr.Root = &root
+	}
+	if r.EffectiveGasPrice != nil {
+		rr.EffectiveGasPrice = new(big.Int).Set(r.EffectiveGasPrice)
+	}
+	if r.BlobGasPrice != nil {
+		rr.BlobGasPrice = new(big.Int).Set(r.BlobGasPrice)
+	}
 	rr.LogsBloom = append(rr.LogsBloom[:0], r.LogsBloom...)
 	rr.Logs = make([]*Log, len(r.Logs))
 	for indx, log := range r.Logs {
@@ -309,6 +611,25 @@ func (l *Log) Copy() *Log {
 	return ll
 }
 
+// AccountProof is the merkle proof of an account and of some of
+// its storage slots in the state trie (eth_getProof)
+type AccountProof struct {
+	Address      Address
+	AccountProof [][]byte
+	Balance      *big.Int
+	CodeHash     Hash
+	Nonce        uint64
+	StorageHash  Hash
+	StorageProof []*StorageProof
+}
+
+// StorageProof is the merkle proof of a storage slot in the storage trie of an account
+type StorageProof struct {
+	Key   Hash
+	Value *big.Int
+	Proof [][]byte
+}
+
 type BlockNumber int
 
 const (
@@ -347,6 +668,22 @@ type BlockNumberOrHash interface {
 	Location() string
 }
 
+// BlockHashSelector selects a block by hash as in EIP-1898. If RequireCanonical
+// is set the request fails if the block is not in the canonical chain.
+type BlockHashSelector struct {
+	Hash             Hash
+	RequireCanonical bool
+}
+
+func (b BlockHashSelector) Location() string {
+	return b.Hash.String()
+}
+
+// MarshalJSON implements the Marshal interface.
+func (b BlockHashSelector) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"blockHash":"%s","requireCanonical":%t}`, b.Hash, b.RequireCanonical)), nil
+}
+
 func min(i, j int) int {
 	if i < j {
 		return i
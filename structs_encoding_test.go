@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -52,6 +53,62 @@ func TestEncodingJSON_Transaction(t *testing.T) {
 	}
 }
 
+func TestEncodingJSON_Receipt(t *testing.T) {
+	receipt := `{
+		"type": "0x3",
+		"transactionHash": "0x0100000000000000000000000000000000000000000000000000000000000000",
+		"transactionIndex": "0x1",
+		"blockHash": "0x0200000000000000000000000000000000000000000000000000000000000000",
+		"blockNumber": "0x10",
+		"from": "0x0100000000000000000000000000000000000000",
+		"to": "0x0200000000000000000000000000000000000000",
+		"contractAddress": null,
+		"gasUsed": "0x5208",
+		"cumulativeGasUsed": "0xa410",
+		"effectiveGasPrice": "0x3b9aca00",
+		"blobGasUsed": "0x20000",
+		"blobGasPrice": "0x1",
+		"logsBloom": "0x` + strings.Repeat("00", 256) + `",
+		"logs": [],
+		"status": "0x1"
+	}`
+
+	r := new(Receipt)
+	assert.NoError(t, r.UnmarshalJSON([]byte(receipt)))
+	assert.Equal(t, TransactionBlob, r.Type)
+	assert.Equal(t, Address{0x2}, *r.To)
+	assert.Equal(t, uint64(1000000000), r.EffectiveGasPrice.Uint64())
+	assert.Equal(t, uint64(131072), r.BlobGasUsed)
+	assert.Equal(t, uint64(1), r.BlobGasPrice.Uint64())
+	assert.Equal(t, uint64(1), r.Status)
+	assert.Nil(t, r.Root)
+
+	// receipt before byzantium (and london) without status
+	receipt = `{
+		"transactionHash": "0x0100000000000000000000000000000000000000000000000000000000000000",
+		"transactionIndex": "0x0",
+		"blockHash": "0x0200000000000000000000000000000000000000000000000000000000000000",
+		"blockNumber": "0x10",
+		"from": "0x0100000000000000000000000000000000000000",
+		"to": null,
+		"contractAddress": "0x0300000000000000000000000000000000000000",
+		"gasUsed": "0x5208",
+		"cumulativeGasUsed": "0x5208",
+		"logsBloom": "0x` + strings.Repeat("00", 256) + `",
+		"logs": [],
+		"root": "0x0300000000000000000000000000000000000000000000000000000000000000"
+	}`
+
+	r = new(Receipt)
+	assert.NoError(t, r.UnmarshalJSON([]byte(receipt)))
+	assert.Equal(t, TransactionLegacy, r.Type)
+	assert.Nil(t, r.To)
+	assert.Nil(t, r.EffectiveGasPrice)
+	assert.Equal(t, Address{0x3}, r.ContractAddress)
+	assert.Equal(t, Hash{0x3}, *r.Root)
+	assert.Equal(t, uint64(0), r.Status)
+}
+
 type testFile struct {
 	name    string
 	content []byte
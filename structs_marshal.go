@@ -61,6 +61,36 @@ func (t *Block) MarshalJSON() ([]byte, error) {
 	o.Set("timestamp", a.NewString(fmt.Sprintf("0x%x", t.Timestamp)))
 	o.Set("difficulty", a.NewString(fmt.Sprintf("0x%x", t.Difficulty)))
 	o.Set("extraData", a.NewString("0x"+hex.EncodeToString(t.ExtraData)))
+	o.Set("logsBloom", a.NewString("0x"+hex.EncodeToString(t.logsBloom())))
+	o.Set("mixHash", a.NewString(t.MixHash.String()))
+	o.Set("nonce", a.NewString("0x"+hex.EncodeToString(t.Nonce[:])))
+
+	// fork values
+	if t.BaseFeePerGas != nil {
+		o.Set("baseFeePerGas", a.NewString(fmt.Sprintf("0x%x", t.BaseFeePerGas)))
+	}
+	if t.WithdrawalsRoot != nil {
+		o.Set("withdrawalsRoot", a.NewString(t.WithdrawalsRoot.String()))
+	}
+	if t.Withdrawals != nil {
+		withdrawals := a.NewArray()
+		for indx, w := range t.Withdrawals {
+			withdrawals.SetArrayItem(indx, w.marshalJSON(a))
+		}
+		o.Set("withdrawals", withdrawals)
+	}
+	if t.BlobGasUsed != nil {
+		o.Set("blobGasUsed", a.NewString(fmt.Sprintf("0x%x", *t.BlobGasUsed)))
+	}
+	if t.ExcessBlobGas != nil {
+		o.Set("excessBlobGas", a.NewString(fmt.Sprintf("0x%x", *t.ExcessBlobGas)))
+	}
+	if t.ParentBeaconBlockRoot != nil {
+		o.Set("parentBeaconBlockRoot", a.NewString(t.ParentBeaconBlockRoot.String()))
+	}
+	if t.RequestsHash != nil {
+		o.Set("requestsHash", a.NewString(t.RequestsHash.String()))
+	}
 
 	// uncles
 	if len(t.Uncles) != 0 {
@@ -92,6 +122,24 @@ func (t *Block) MarshalJSON() ([]byte, error) {
 	return res, nil
 }
 
+// MarshalJSON implements the Marshal interface.
+func (w *Withdrawal) MarshalJSON() ([]byte, error) {
+	a := defaultArena.Get()
+
+	res := w.marshalJSON(a).MarshalTo(nil)
+	defaultArena.Put(a)
+	return res, nil
+}
+
+func (w *Withdrawal) marshalJSON(a *fastjson.Arena) *fastjson.Value {
+	o := a.NewObject()
+	o.Set("index", a.NewString(fmt.Sprintf("0x%x", w.Index)))
+	o.Set("validatorIndex", a.NewString(fmt.Sprintf("0x%x", w.ValidatorIndex)))
+	o.Set("address", a.NewString(w.Address.String()))
+	o.Set("amount", a.NewString(fmt.Sprintf("0x%x", w.Amount)))
+	return o
+}
+
 // MarshalJSON implements the Marshal interface.
 func (t *Transaction) MarshalJSON() ([]byte, error) {
 	a := defaultArena.Get()
@@ -155,6 +203,46 @@ func (t *Transaction) marshalJSON(a *fastjson.Arena) *fastjson.Value {
 	if t.AccessList != nil {
 		o.Set("accessList", t.AccessList.marshalJSON(a))
 	}
+	if t.MaxFeePerBlobGas != nil {
+		o.Set("maxFeePerBlobGas", a.NewString(fmt.Sprintf("0x%x", t.MaxFeePerBlobGas)))
+	}
+	if t.BlobVersionedHashes != nil {
+		hashes := a.NewArray()
+		for indx, hash := range t.BlobVersionedHashes {
+			hashes.SetArrayItem(indx, a.NewString(hash.String()))
+		}
+		o.Set("blobVersionedHashes", hashes)
+	}
+	if t.AuthorizationList != nil {
+		list := a.NewArray()
+		for indx, auth := range t.AuthorizationList {
+			list.SetArrayItem(indx, auth.marshalJSON(a))
+		}
+		o.Set("authorizationList", list)
+	}
+	return o
+}
+
+// MarshalJSON implements the Marshal interface.
+func (a *Authorization) MarshalJSON() ([]byte, error) {
+	ar := defaultArena.Get()
+	res := a.marshalJSON(ar).MarshalTo(nil)
+	defaultArena.Put(ar)
+	return res, nil
+}
+
+func (a *Authorization) marshalJSON(ar *fastjson.Arena) *fastjson.Value {
+	chainID := a.ChainID
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+	o := ar.NewObject()
+	o.Set("chainId", ar.NewString(fmt.Sprintf("0x%x", chainID)))
+	o.Set("address", ar.NewString(a.Address.String()))
+	o.Set("nonce", ar.NewString(fmt.Sprintf("0x%x", a.Nonce)))
+	o.Set("yParity", ar.NewString(fmt.Sprintf("0x%x", a.YParity)))
+	o.Set("r", ar.NewString("0x"+hex.EncodeToString(a.R)))
+	o.Set("s", ar.NewString("0x"+hex.EncodeToString(a.S)))
 	return o
 }
 
@@ -195,6 +283,15 @@ func (c *CallMsg) MarshalJSON() ([]byte, error) {
 	if c.Gas != nil {
 		o.Set("gas", a.NewString(fmt.Sprintf("0x%x", c.Gas)))
 	}
+	if c.MaxFeePerGas != nil {
+		o.Set("maxFeePerGas", a.NewString(fmt.Sprintf("0x%x", c.MaxFeePerGas)))
+	}
+	if c.MaxPriorityFeePerGas != nil {
+		o.Set("maxPriorityFeePerGas", a.NewString(fmt.Sprintf("0x%x", c.MaxPriorityFeePerGas)))
+	}
+	if c.AccessList != nil {
+		o.Set("accessList", c.AccessList.marshalJSON(a))
+	}
 
 	res := o.MarshalTo(nil)
 	defaultArena.Put(a)
@@ -213,6 +310,7 @@ func (l *LogFilter) MarshalJSON() ([]byte, error) {
 		for indx, addr := range l.Address {
 			v.SetArrayItem(indx, a.NewString(addr.String()))
 		}
+		o.Set("address", v)
 	}
 
 	v := a.NewArray()
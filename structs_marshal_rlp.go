@@ -7,26 +7,142 @@ import (
 	"github.com/umbracle/fastrlp"
 )
 
+// ComputeHash returns the hash of the header of the block
+func (b *Block) ComputeHash() (Hash, error) {
+	raw, err := b.MarshalHeaderRLPTo(nil)
+	if err != nil {
+		return Hash{}, err
+	}
+	return BytesToHash(Keccak256(raw)), nil
+}
+
+// VerifyHash checks that the hash of the block matches the hash of its header
+func (b *Block) VerifyHash() error {
+	hash, err := b.ComputeHash()
+	if err != nil {
+		return err
+	}
+	if hash != b.Hash {
+		return fmt.Errorf("block hash mismatch, expected %s but the header hash is %s", b.Hash, hash)
+	}
+	return nil
+}
+
+// MarshalHeaderRLPTo marshals the header of the block to RLP. The fields
+// added by each fork are only encoded if they are set (i.e. the base fee
+// after London or the withdrawals root after Shanghai).
+func (b *Block) MarshalHeaderRLPTo(dst []byte) ([]byte, error) {
+	v, err := b.MarshalHeaderRLPWith(&fastrlp.Arena{})
+	if err != nil {
+		return nil, err
+	}
+	return v.MarshalTo(dst), nil
+}
+
+// MarshalHeaderRLPWith marshals the header of the block to RLP with a specific fastrlp.Arena
+func (b *Block) MarshalHeaderRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewBytes(b.ParentHash[:]))
+	vv.Set(arena.NewBytes(b.Sha3Uncles[:]))
+	vv.Set(arena.NewBytes(b.Miner[:]))
+	vv.Set(arena.NewBytes(b.StateRoot[:]))
+	vv.Set(arena.NewBytes(b.TransactionsRoot[:]))
+	vv.Set(arena.NewBytes(b.ReceiptsRoot[:]))
+	vv.Set(arena.NewBytes(b.logsBloom()))
+	vv.Set(arena.NewBigInt(b.Difficulty))
+	vv.Set(arena.NewUint(b.Number))
+	vv.Set(arena.NewUint(b.GasLimit))
+	vv.Set(arena.NewUint(b.GasUsed))
+	vv.Set(arena.NewUint(b.Timestamp))
+	vv.Set(arena.NewCopyBytes(b.ExtraData))
+	vv.Set(arena.NewBytes(b.MixHash[:]))
+	vv.Set(arena.NewBytes(b.Nonce[:]))
+
+	// the fields of each fork are appended in order and a field
+	// cannot be set unless the fields of the previous forks are set
+	forkFields := []struct {
+		name  string
+		isSet bool
+		value func() *fastrlp.Value
+	}{
+		{"baseFeePerGas", b.BaseFeePerGas != nil, func() *fastrlp.Value {
+			return arena.NewBigInt(b.BaseFeePerGas)
+		}},
+		{"withdrawalsRoot", b.WithdrawalsRoot != nil, func() *fastrlp.Value {
+			return arena.NewBytes(b.WithdrawalsRoot[:])
+		}},
+		{"blobGasUsed", b.BlobGasUsed != nil, func() *fastrlp.Value {
+			return arena.NewUint(*b.BlobGasUsed)
+		}},
+		{"excessBlobGas", b.ExcessBlobGas != nil, func() *fastrlp.Value {
+			return arena.NewUint(*b.ExcessBlobGas)
+		}},
+		{"parentBeaconBlockRoot", b.ParentBeaconBlockRoot != nil, func() *fastrlp.Value {
+			return arena.NewBytes(b.ParentBeaconBlockRoot[:])
+		}},
+		{"requestsHash", b.RequestsHash != nil, func() *fastrlp.Value {
+			return arena.NewBytes(b.RequestsHash[:])
+		}},
+	}
+
+	last := -1
+	for indx, field := range forkFields {
+		if field.isSet {
+			last = indx
+		}
+	}
+	for _, field := range forkFields[:last+1] {
+		if !field.isSet {
+			return nil, fmt.Errorf("header field '%s' is required by the fields of later forks", field.name)
+		}
+		vv.Set(field.value())
+	}
+	return vv, nil
+}
+
+// logsBloom returns the bloom of the block or an empty bloom if it is not set
+func (b *Block) logsBloom() []byte {
+	return bloomOrEmpty(b.LogsBloom)
+}
+
+func bloomOrEmpty(bloom []byte) []byte {
+	if len(bloom) == 0 {
+		return make([]byte, 256)
+	}
+	return bloom
+}
+
 // GetHash returns the Hash of the transaction
 func (t *Transaction) GetHash() (hash Hash, err error) {
 	var rlpEncode []byte
-	if rlpEncode, err = t.MarshalRLPTo(nil); err != nil {
+	if rlpEncode, err = t.marshalRLPTo(nil, false); err != nil {
 		return Hash{}, err
 	}
 	return BytesToHash(Keccak256(rlpEncode)), nil
 }
 
-// MarshalRLPTo marshals the transaction to a []byte destination
+// MarshalRLPTo marshals the transaction to a []byte destination. A blob transaction
+// with a sidecar is encoded with the blobs, commitments and proofs (network encoding).
 func (t *Transaction) MarshalRLPTo(dst []byte) ([]byte, error) {
-	raw, err := fastrlp.MarshalRLP(t)
+	return t.marshalRLPTo(dst, true)
+}
+
+func (t *Transaction) marshalRLPTo(dst []byte, withSidecar bool) ([]byte, error) {
+	ar := &fastrlp.Arena{}
+
+	v, err := t.MarshalRLPWith(ar)
 	if err != nil {
 		return nil, err
 	}
-	if t.Type == TransactionLegacy {
-		return raw, nil
+	if withSidecar && t.Type == TransactionBlob && t.Sidecar != nil {
+		v = t.Sidecar.marshalRLPWith(ar, v)
+	}
+	if t.Type != TransactionLegacy {
+		// append type byte
+		dst = append(dst, byte(t.Type))
 	}
-	// append type byte
-	return append([]byte{byte(t.Type)}, raw...), nil
+	return v.MarshalTo(dst), nil
 }
 
 // MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena
@@ -40,7 +156,7 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, erro
 
 	vv.Set(arena.NewUint(t.Nonce))
 
-	if t.Type == TransactionDynamicFee {
+	if t.Type >= TransactionDynamicFee {
 		// dynamic fee uses
 		vv.Set(arena.NewBigInt(t.MaxPriorityFeePerGas))
 		vv.Set(arena.NewBigInt(t.MaxFeePerGas))
@@ -70,14 +186,19 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, erro
 		vv.Set(accessList)
 	}
 
+	if t.Type == TransactionBlob {
+		vv.Set(arena.NewBigInt(t.MaxFeePerBlobGas))
+		vv.Set(marshalHashesRLPWith(arena, t.BlobVersionedHashes))
+	}
+	if t.Type == TransactionSetCode {
+		vv.Set(marshalAuthorizationsRLPWith(arena, t.AuthorizationList))
+	}
+
 	// signature values
 	vv.Set(arena.NewCopyBytes(t.V))
 	vv.Set(arena.NewCopyBytes(t.R))
 	vv.Set(arena.NewCopyBytes(t.S))
 
-	if t.Type == TransactionLegacy {
-		return vv, nil
-	}
 	return vv, nil
 }
 
@@ -94,6 +215,10 @@ func (t *Transaction) UnmarshalRLP(buf []byte) error {
 			t.Type = TransactionAccessList
 		case 2:
 			t.Type = TransactionDynamicFee
+		case 3:
+			t.Type = TransactionBlob
+		case 4:
+			t.Type = TransactionSetCode
 		default:
 			return fmt.Errorf("type byte %d not found", typ)
 		}
@@ -102,6 +227,14 @@ func (t *Transaction) UnmarshalRLP(buf []byte) error {
 	if err := fastrlp.UnmarshalRLP(buf, t); err != nil {
 		return err
 	}
+	if t.Sidecar != nil {
+		// the hash does not include the sidecar
+		hash, err := t.GetHash()
+		if err != nil {
+			return err
+		}
+		t.Hash = hash
+	}
 	return nil
 }
 
@@ -111,6 +244,18 @@ func (t *Transaction) UnmarshalRLPWith(v *fastrlp.Value) error {
 		return err
 	}
 
+	t.Sidecar = nil
+	if t.Type == TransactionBlob && len(elems) == 4 && elems[0].Type() == fastrlp.TypeArray {
+		// network encoding with the sidecar
+		t.Sidecar = new(BlobSidecar)
+		if err := t.Sidecar.unmarshalRLPWith(elems[1:]); err != nil {
+			return err
+		}
+		if elems, err = elems[0].GetElems(); err != nil {
+			return err
+		}
+	}
+
 	getElem := func() *fastrlp.Value {
 		v := elems[0]
 		elems = elems[1:]
@@ -127,6 +272,12 @@ func (t *Transaction) UnmarshalRLPWith(v *fastrlp.Value) error {
 	case TransactionDynamicFee:
 		// access list txn + gas fee 1 + gas fee 2 - gas price
 		num = 12
+	case TransactionBlob:
+		// dynamic fee txn + blob gas fee + versioned hashes
+		num = 14
+	case TransactionSetCode:
+		// dynamic fee txn + authorization list
+		num = 13
 	default:
 		return fmt.Errorf("transaction type %d not found", t.Type)
 	}
@@ -146,7 +297,7 @@ func (t *Transaction) UnmarshalRLPWith(v *fastrlp.Value) error {
 		return err
 	}
 
-	if t.Type == TransactionDynamicFee {
+	if t.Type >= TransactionDynamicFee {
 		// dynamic fee uses
 		t.MaxPriorityFeePerGas = new(big.Int)
 		if err := getElem().GetBigInt(t.MaxPriorityFeePerGas); err != nil {
@@ -193,6 +344,21 @@ func (t *Transaction) UnmarshalRLPWith(v *fastrlp.Value) error {
 		}
 	}
 
+	if t.Type == TransactionBlob {
+		t.MaxFeePerBlobGas = new(big.Int)
+		if err := getElem().GetBigInt(t.MaxFeePerBlobGas); err != nil {
+			return err
+		}
+		if t.BlobVersionedHashes, err = unmarshalHashesRLPWith(getElem()); err != nil {
+			return err
+		}
+	}
+	if t.Type == TransactionSetCode {
+		if t.AuthorizationList, err = unmarshalAuthorizationsRLPWith(getElem()); err != nil {
+			return err
+		}
+	}
+
 	// V
 	if t.V, err = getElem().GetBytes(t.V); err != nil {
 		return err
@@ -209,6 +375,173 @@ func (t *Transaction) UnmarshalRLPWith(v *fastrlp.Value) error {
 	return nil
 }
 
+func marshalHashesRLPWith(arena *fastrlp.Arena, hashes []Hash) *fastrlp.Value {
+	if len(hashes) == 0 {
+		return arena.NewNullArray()
+	}
+	v := arena.NewArray()
+	for _, h := range hashes {
+		v.Set(arena.NewCopyBytes(h[:]))
+	}
+	return v
+}
+
+func unmarshalHashesRLPWith(v *fastrlp.Value) ([]Hash, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]Hash, len(elems))
+	for indx, elem := range elems {
+		if err := elem.GetHash(hashes[indx][:]); err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
+
+// marshalRLPWith wraps the transaction with the sidecar (network encoding)
+func (b *BlobSidecar) marshalRLPWith(arena *fastrlp.Arena, txn *fastrlp.Value) *fastrlp.Value {
+	blobs := arena.NewArray()
+	for indx := range b.Blobs {
+		blobs.Set(arena.NewBytes(b.Blobs[indx][:]))
+	}
+	commitments := arena.NewArray()
+	for indx := range b.Commitments {
+		commitments.Set(arena.NewBytes(b.Commitments[indx][:]))
+	}
+	proofs := arena.NewArray()
+	for indx := range b.Proofs {
+		proofs.Set(arena.NewBytes(b.Proofs[indx][:]))
+	}
+
+	v := arena.NewArray()
+	v.Set(txn)
+	v.Set(blobs)
+	v.Set(commitments)
+	v.Set(proofs)
+	return v
+}
+
+func (b *BlobSidecar) unmarshalRLPWith(elems []*fastrlp.Value) error {
+	blobs, err := elems[0].GetElems()
+	if err != nil {
+		return err
+	}
+	b.Blobs = make([]Blob, len(blobs))
+	for indx, elem := range blobs {
+		if _, err := elem.GetBytes(b.Blobs[indx][:0], BlobSize); err != nil {
+			return err
+		}
+	}
+
+	commitments, err := elems[1].GetElems()
+	if err != nil {
+		return err
+	}
+	b.Commitments = make([]KZGCommitment, len(commitments))
+	for indx, elem := range commitments {
+		if _, err := elem.GetBytes(b.Commitments[indx][:0], 48); err != nil {
+			return err
+		}
+	}
+
+	proofs, err := elems[2].GetElems()
+	if err != nil {
+		return err
+	}
+	b.Proofs = make([]KZGProof, len(proofs))
+	for indx, elem := range proofs {
+		if _, err := elem.GetBytes(b.Proofs[indx][:0], 48); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalRLPTo marshals the authorization to a []byte destination
+func (a *Authorization) MarshalRLPTo(dst []byte) ([]byte, error) {
+	raw, err := fastrlp.MarshalRLP(a)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, raw...), nil
+}
+
+// MarshalRLPWith marshals the authorization to RLP with a specific fastrlp.Arena
+func (a *Authorization) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	v := arena.NewArray()
+	v.Set(arena.NewBigInt(a.ChainID))
+	v.Set(arena.NewCopyBytes(a.Address[:]))
+	v.Set(arena.NewUint(a.Nonce))
+	v.Set(arena.NewUint(a.YParity))
+	v.Set(arena.NewCopyBytes(a.R))
+	v.Set(arena.NewCopyBytes(a.S))
+	return v, nil
+}
+
+func (a *Authorization) UnmarshalRLP(buf []byte) error {
+	return fastrlp.UnmarshalRLP(buf, a)
+}
+
+func (a *Authorization) UnmarshalRLPWith(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 6 {
+		return fmt.Errorf("six elems expected but %d found", len(elems))
+	}
+
+	a.ChainID = new(big.Int)
+	if err := elems[0].GetBigInt(a.ChainID); err != nil {
+		return err
+	}
+	if err := elems[1].GetAddr(a.Address[:]); err != nil {
+		return err
+	}
+	if a.Nonce, err = elems[2].GetUint64(); err != nil {
+		return err
+	}
+	if a.YParity, err = elems[3].GetUint64(); err != nil {
+		return err
+	}
+	if a.R, err = elems[4].GetBytes(a.R[:0]); err != nil {
+		return err
+	}
+	if a.S, err = elems[5].GetBytes(a.S[:0]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func marshalAuthorizationsRLPWith(arena *fastrlp.Arena, list []*Authorization) *fastrlp.Value {
+	if len(list) == 0 {
+		return arena.NewNullArray()
+	}
+	v := arena.NewArray()
+	for _, auth := range list {
+		elem, _ := auth.MarshalRLPWith(arena)
+		v.Set(elem)
+	}
+	return v
+}
+
+func unmarshalAuthorizationsRLPWith(v *fastrlp.Value) ([]*Authorization, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Authorization, len(elems))
+	for indx, elem := range elems {
+		list[indx] = new(Authorization)
+		if err := list[indx].UnmarshalRLPWith(elem); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
 func (a *AccessList) MarshalRLPTo(dst []byte) ([]byte, error) {
 	return fastrlp.MarshalRLP(a)
 }
@@ -284,3 +617,157 @@ func (a *AccessList) UnmarshalRLPWith(v *fastrlp.Value) error {
 	}
 	return nil
 }
+
+// MarshalRLPTo marshals the receipt to a []byte destination with the encoding
+// of the receipts trie. A typed receipt is prefixed with the type byte.
+func (r *Receipt) MarshalRLPTo(dst []byte) ([]byte, error) {
+	ar := &fastrlp.Arena{}
+
+	v, err := r.MarshalRLPWith(ar)
+	if err != nil {
+		return nil, err
+	}
+	if r.Type != TransactionLegacy {
+		// append type byte
+		dst = append(dst, byte(r.Type))
+	}
+	return v.MarshalTo(dst), nil
+}
+
+// MarshalRLPWith marshals the receipt to RLP with a specific fastrlp.Arena
+func (r *Receipt) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	vv := arena.NewArray()
+	if r.Root != nil {
+		// receipts before byzantium
+		vv.Set(arena.NewCopyBytes(r.Root[:]))
+	} else {
+		if r.Status > 1 {
+			return nil, fmt.Errorf("invalid receipt status %d", r.Status)
+		}
+		vv.Set(arena.NewUint(r.Status))
+	}
+	vv.Set(arena.NewUint(r.CumulativeGasUsed))
+	vv.Set(arena.NewCopyBytes(r.logsBloom()))
+
+	if len(r.Logs) == 0 {
+		vv.Set(arena.NewNullArray())
+	} else {
+		logs := arena.NewArray()
+		for _, log := range r.Logs {
+			elem, err := log.MarshalRLPWith(arena)
+			if err != nil {
+				return nil, err
+			}
+			logs.Set(elem)
+		}
+		vv.Set(logs)
+	}
+	return vv, nil
+}
+
+// logsBloom returns the bloom of the receipt or the bloom of its logs if it is not set
+func (r *Receipt) logsBloom() []byte {
+	if len(r.LogsBloom) == 0 {
+		return CreateBloom(r.Logs).Bytes()
+	}
+	return r.LogsBloom
+}
+
+// UnmarshalRLP unmarshals a receipt in the encoding of the receipts trie.
+// Only the consensus fields of the receipt and its logs are set.
+func (r *Receipt) UnmarshalRLP(buf []byte) error {
+	if len(buf) < 1 {
+		return fmt.Errorf("expecting 1 byte but 0 byte provided")
+	}
+	r.Type = TransactionLegacy
+	if buf[0] <= 0x7f {
+		// it includes a type byte
+		typ := TransactionType(buf[0])
+		if typ < TransactionAccessList || typ > TransactionSetCode {
+			return fmt.Errorf("type byte %d not found", typ)
+		}
+		r.Type = typ
+		buf = buf[1:]
+	}
+	return fastrlp.UnmarshalRLP(buf, r)
+}
+
+// UnmarshalRLPWith unmarshals a receipt using a fastrlp.Value
+func (r *Receipt) UnmarshalRLPWith(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 4 {
+		return fmt.Errorf("incorrect number of elements to decode receipt, expected 4 but found %d", len(elems))
+	}
+
+	// either the post state root or the status
+	buf, err := elems[0].Bytes()
+	if err != nil {
+		return err
+	}
+	r.Root = nil
+	r.Status = 0
+	switch {
+	case len(buf) == 32:
+		root := BytesToHash(buf)
+		r.Root = &root
+	case len(buf) == 0:
+	case len(buf) == 1 && buf[0] == 1:
+		r.Status = 1
+	default:
+		return fmt.Errorf("invalid receipt status %x", buf)
+	}
+
+	if r.CumulativeGasUsed, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	if r.LogsBloom, err = elems[2].GetBytes(r.LogsBloom[:0], 256); err != nil {
+		return err
+	}
+
+	logs, err := elems[3].GetElems()
+	if err != nil {
+		return err
+	}
+	r.Logs = r.Logs[:0]
+	for _, elem := range logs {
+		log := new(Log)
+		if err := log.UnmarshalRLPWith(elem); err != nil {
+			return err
+		}
+		r.Logs = append(r.Logs, log)
+	}
+	return nil
+}
+
+// MarshalRLPWith marshals the address, topics and data of the log to RLP
+func (l *Log) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	vv := arena.NewArray()
+	vv.Set(arena.NewCopyBytes(l.Address[:]))
+	vv.Set(marshalHashesRLPWith(arena, l.Topics))
+	vv.Set(arena.NewCopyBytes(l.Data))
+	return vv, nil
+}
+
+// UnmarshalRLPWith unmarshals the address, topics and data of the log using a fastrlp.Value
+func (l *Log) UnmarshalRLPWith(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 3 {
+		return fmt.Errorf("incorrect number of elements to decode log, expected 3 but found %d", len(elems))
+	}
+	if err := elems[0].GetAddr(l.Address[:]); err != nil {
+		return err
+	}
+	if l.Topics, err = unmarshalHashesRLPWith(elems[1]); err != nil {
+		return err
+	}
+	if l.Data, err = elems[2].GetBytes(l.Data[:0]); err != nil {
+		return err
+	}
+	return nil
+}
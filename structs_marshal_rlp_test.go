@@ -2,6 +2,9 @@ package ethgo
 
 import (
 	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,8 +16,18 @@ func TestEncodingRLP_Transaction_Fuzz(t *testing.T) {
 		obj := &Transaction{}
 		err := fastrlp.Fuzz(100, obj,
 			fastrlp.WithDefaults(func(obj fastrlp.FuzzObject) {
-				obj.(*Transaction).Type = typ
+				txn := obj.(*Transaction)
+				txn.Type = typ
+				for indx, auth := range txn.AuthorizationList {
+					if auth == nil {
+						txn.AuthorizationList[indx] = &Authorization{}
+					}
+				}
 			}),
+			func(f *fastrlp.Fuzzer) {
+				// the sidecar is not part of the canonical encoding
+				f.SkipFieldsWithPattern(regexp.MustCompile("^Sidecar$"))
+			},
 			fastrlp.WithPostHook(func(obj fastrlp.FuzzObject) error {
 				// Test that the hash from unmarshal is the same as the one computed
 				txn := obj.(*Transaction)
@@ -40,6 +53,69 @@ func TestEncodingRLP_Transaction_Fuzz(t *testing.T) {
 	t.Run("dynamicfee", func(t *testing.T) {
 		testTransaction(t, TransactionDynamicFee)
 	})
+	t.Run("blob", func(t *testing.T) {
+		testTransaction(t, TransactionBlob)
+	})
+	t.Run("setcode", func(t *testing.T) {
+		testTransaction(t, TransactionSetCode)
+	})
+}
+
+func TestEncodingRLP_BlobTransaction_Sidecar(t *testing.T) {
+	to := Address{0x1}
+	txn := &Transaction{
+		Type:                 TransactionBlob,
+		ChainID:              big.NewInt(1),
+		Nonce:                1,
+		To:                   &to,
+		Value:                big.NewInt(0),
+		Gas:                  21000,
+		MaxFeePerGas:         big.NewInt(10),
+		MaxPriorityFeePerGas: big.NewInt(1),
+		MaxFeePerBlobGas:     big.NewInt(5),
+		V:                    []byte{0x1},
+		R:                    []byte{0x1},
+		S:                    []byte{0x1},
+	}
+	hash, err := txn.GetHash()
+	assert.NoError(t, err)
+
+	txn.Sidecar = &BlobSidecar{
+		Blobs:       EncodeBlobs([]byte{0x1, 0x2}),
+		Commitments: []KZGCommitment{{0xc0}},
+		Proofs:      []KZGProof{{0xc0}},
+	}
+	txn.BlobVersionedHashes = txn.Sidecar.VersionedHashes()
+
+	// the sidecar is not part of the hash
+	hash2, err := txn.GetHash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, hash2)
+
+	raw, err := txn.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+	assert.Greater(t, len(raw), BlobSize)
+	assert.Equal(t, byte(TransactionBlob), raw[0])
+
+	txn2 := new(Transaction)
+	assert.NoError(t, txn2.UnmarshalRLP(raw))
+	assert.Equal(t, hash2, txn2.Hash)
+	assert.Equal(t, txn.Sidecar, txn2.Sidecar)
+	assert.Equal(t, txn.BlobVersionedHashes, txn2.BlobVersionedHashes)
+
+	data, err := DecodeBlobs(txn2.Sidecar.Blobs)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x1, 0x2}, data)
+
+	// without the sidecar
+	txn.Sidecar = nil
+	raw, err = txn.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+
+	txn3 := new(Transaction)
+	assert.NoError(t, txn3.UnmarshalRLP(raw))
+	assert.Equal(t, hash2, txn3.Hash)
+	assert.Nil(t, txn3.Sidecar)
 }
 
 func TestEncodingRLP_AccessList_Fuzz(t *testing.T) {
@@ -48,3 +124,133 @@ func TestEncodingRLP_AccessList_Fuzz(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestEncodingRLP_Receipt(t *testing.T) {
+	r := &Receipt{
+		Status:            1,
+		CumulativeGasUsed: 21000,
+	}
+	raw, err := r.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+
+	// status, cumulative gas used, empty bloom and no logs
+	expected := append([]byte{0xf9, 0x01, 0x08, 0x01, 0x82, 0x52, 0x08, 0xb9, 0x01, 0x00}, make([]byte, 256)...)
+	expected = append(expected, 0xc0)
+	assert.Equal(t, expected, raw)
+
+	// typed receipt
+	r.Type = TransactionDynamicFee
+	raw, err = r.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{0x2}, expected...), raw)
+
+	cases := []*Receipt{
+		{
+			Type:              TransactionBlob,
+			Status:            1,
+			CumulativeGasUsed: 100,
+			LogsBloom:         append([]byte{0x1}, make([]byte, 255)...),
+			Logs: []*Log{
+				{Address: Address{0x1}, Topics: []Hash{{0x1}, {0x2}}, Data: []byte{0x1, 0x2}},
+				{Address: Address{0x2}, Topics: []Hash{}},
+			},
+		},
+		{
+			// failed
+			Type:              TransactionSetCode,
+			CumulativeGasUsed: 100,
+			LogsBloom:         make([]byte, 256),
+		},
+		{
+			// before byzantium
+			Root:              &Hash{0x1},
+			CumulativeGasUsed: 100,
+			LogsBloom:         make([]byte, 256),
+		},
+	}
+	for _, c := range cases {
+		raw, err := c.MarshalRLPTo(nil)
+		assert.NoError(t, err)
+
+		r := new(Receipt)
+		assert.NoError(t, r.UnmarshalRLP(raw))
+		assert.Equal(t, c, r)
+	}
+
+	// the bloom is created from the logs if it is not set
+	logs := []*Log{{Address: Address{0x1}, Topics: []Hash{{0x1}}}}
+	raw, err = (&Receipt{Status: 1, Logs: logs}).MarshalRLPTo(nil)
+	assert.NoError(t, err)
+
+	r = new(Receipt)
+	assert.NoError(t, r.UnmarshalRLP(raw))
+	assert.Equal(t, CreateBloom(logs).Bytes(), r.LogsBloom)
+	assert.True(t, BytesToBloom(r.LogsBloom).Test(Address{0x1}.Bytes()))
+
+	// the status is either success or failure
+	_, err = (&Receipt{Status: 2}).MarshalRLPTo(nil)
+	assert.Error(t, err)
+}
+
+func TestEncodingRLP_BlockHeader(t *testing.T) {
+	// mainnet genesis block
+	genesis := `{
+		"number": "0x0",
+		"hash": "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		"parentHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+		"transactionsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"stateRoot": "0xd7f8974fb5ac78d9ac099b9ad5018bedc2ce0a72dad1827a1709da30580f0544",
+		"receiptsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"miner": "0x0000000000000000000000000000000000000000",
+		"gasLimit": "0x1388",
+		"gasUsed": "0x0",
+		"timestamp": "0x0",
+		"difficulty": "0x400000000",
+		"extraData": "0x11bbe8db4e347b4e8c937c1c8370e4b5ed33adb3db69cbdb7a38e1e50b1b82fa",
+		"logsBloom": "0x` + strings.Repeat("00", 256) + `",
+		"mixHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"nonce": "0x0000000000000042",
+		"transactions": [],
+		"uncles": []
+	}`
+
+	block := new(Block)
+	assert.NoError(t, block.UnmarshalJSON([]byte(genesis)))
+	assert.NoError(t, block.VerifyHash())
+
+	block.Nonce[7] = 0x43
+	assert.Error(t, block.VerifyHash())
+
+	numFields := func(b *Block) int {
+		raw, err := b.MarshalHeaderRLPTo(nil)
+		assert.NoError(t, err)
+
+		v, err := (&fastrlp.Parser{}).Parse(raw)
+		assert.NoError(t, err)
+		return v.Elems()
+	}
+
+	hash := Hash{0x1}
+	num := uint64(1)
+
+	// each fork appends its fields to the header
+	assert.Equal(t, 15, numFields(block))
+
+	block.BaseFeePerGas = big.NewInt(1)
+	assert.Equal(t, 16, numFields(block))
+
+	block.WithdrawalsRoot = &hash
+	assert.Equal(t, 17, numFields(block))
+
+	block.BlobGasUsed, block.ExcessBlobGas, block.ParentBeaconBlockRoot = &num, &num, &hash
+	assert.Equal(t, 20, numFields(block))
+
+	block.RequestsHash = &hash
+	assert.Equal(t, 21, numFields(block))
+
+	// the fields of a fork require the ones of the previous forks
+	block.WithdrawalsRoot = nil
+	_, err := block.ComputeHash()
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package ethgo
 
 import (
+	"encoding/json"
 	"math/big"
 	"reflect"
 	"testing"
@@ -35,6 +36,88 @@ func TestAddress_Checksum(t *testing.T) {
 	}
 }
 
+func TestAddress_ChecksumChainID(t *testing.T) {
+	// eip-1191 test cases
+	cases := map[uint64][]string{
+		30: {
+			"0x5aaEB6053f3e94c9b9a09f33669435E7ef1bEAeD",
+			"0xFb6916095cA1Df60bb79ce92cE3EA74c37c5d359",
+			"0xDBF03B407c01E7CD3cBea99509D93F8Dddc8C6FB",
+			"0xD1220A0Cf47c7B9BE7a2e6ba89F429762E7B9adB",
+		},
+		31: {
+			"0x5aAeb6053F3e94c9b9A09F33669435E7EF1BEaEd",
+			"0xFb6916095CA1dF60bb79CE92ce3Ea74C37c5D359",
+			"0xdbF03B407C01E7cd3cbEa99509D93f8dDDc8C6fB",
+			"0xd1220a0CF47c7B9Be7A2E6Ba89f429762E7b9adB",
+		},
+	}
+	for chainID, addrs := range cases {
+		for _, str := range addrs {
+			addr, err := ParseAddressWithChainID(str, chainID)
+			assert.NoError(t, err)
+			assert.Equal(t, str, addr.ChecksumString(chainID))
+
+			// the checksum is not valid without the chain id
+			_, err = ParseAddress(str)
+			assert.Equal(t, ErrInvalidChecksum, err)
+		}
+	}
+}
+
+func TestAddress_Parse(t *testing.T) {
+	addr, err := ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	assert.NoError(t, err)
+	assert.Equal(t, HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"), addr)
+
+	// lower and upper case addresses do not have checksum
+	for _, str := range []string{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"} {
+		addr2, err := ParseAddress(str)
+		assert.NoError(t, err)
+		assert.Equal(t, addr, addr2)
+	}
+
+	// one letter with the wrong case
+	_, err = ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	assert.Equal(t, ErrInvalidChecksum, err)
+
+	invalid := []string{
+		"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaeg",
+		"0x1",
+		"",
+	}
+	for _, str := range invalid {
+		_, err := ParseAddress(str)
+		assert.Error(t, err, str)
+	}
+}
+
+func TestAddress_UnmarshalStrict(t *testing.T) {
+	wrong := []byte(`"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"`)
+
+	// the checksum is only validated by the strict address
+	var addr Address
+	assert.NoError(t, json.Unmarshal(wrong, &addr))
+	assert.Equal(t, HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"), addr)
+
+	var strict StrictAddress
+	assert.Equal(t, ErrInvalidChecksum, json.Unmarshal(wrong, &strict))
+	assert.Error(t, json.Unmarshal([]byte(`"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"`), &strict))
+
+	assert.NoError(t, json.Unmarshal([]byte(`"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"`), &strict))
+	assert.Equal(t, addr, strict.Address())
+
+	assert.NoError(t, json.Unmarshal([]byte(`"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"`), &strict))
+	assert.Equal(t, addr, strict.Address())
+
+	data, err := json.Marshal(strict)
+	assert.NoError(t, err)
+	assert.Equal(t, `"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"`, string(data))
+}
+
 func TestAddress_HexToString(t *testing.T) {
 	assert.Equal(t, HexToAddress("0x1").String(), "0x0000000000000000000000000000000000000001")
 	assert.Equal(t, HexToAddress("00000000000000000000000000000000000000001").String(), "0x0000000000000000000000000000000000000001")
@@ -72,13 +155,20 @@ func TestTransaction_Copy(t *testing.T) {
 
 func TestReceipt_Copy(t *testing.T) {
 	r := &Receipt{
-		LogsBloom: []byte{0x1, 0x2},
-		Logs:      []*Log{},
+		To:                &Address{0x1},
+		EffectiveGasPrice: big.NewInt(1),
+		LogsBloom:         []byte{0x1, 0x2},
+		Logs:              []*Log{},
+		Root:              &Hash{0x1},
+		BlobGasPrice:      big.NewInt(2),
 	}
 	rr := r.Copy()
 	if !reflect.DeepEqual(r, rr) {
 		t.Fatal("incorrect receipt")
 	}
+	if rr.To == r.To || rr.Root == r.Root || rr.EffectiveGasPrice == r.EffectiveGasPrice {
+		t.Fatal("the receipt is not copied")
+	}
 }
 
 func TestLog_Copy(t *testing.T) {
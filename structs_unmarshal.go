@@ -1,6 +1,7 @@
 package ethgo
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -62,6 +63,62 @@ func (b *Block) UnmarshalJSON(buf []byte) error {
 		return err
 	}
 
+	// some providers do not return the proof of work values
+	b.LogsBloom = b.LogsBloom[:0]
+	if fieldNotFull(v, "logsBloom") {
+		if b.LogsBloom, err = decodeBytes(b.LogsBloom, v, "logsBloom", 256); err != nil {
+			return err
+		}
+	}
+	b.MixHash = Hash{}
+	if fieldNotFull(v, "mixHash") {
+		if err := decodeHash(&b.MixHash, v, "mixHash"); err != nil {
+			return err
+		}
+	}
+	b.Nonce = [8]byte{}
+	if fieldNotFull(v, "nonce") {
+		nonce, err := decodeBytes(nil, v, "nonce", 8)
+		if err != nil {
+			return err
+		}
+		copy(b.Nonce[:], nonce)
+	}
+
+	// fork values
+	b.BaseFeePerGas = nil
+	if fieldNotFull(v, "baseFeePerGas") {
+		if b.BaseFeePerGas, err = decodeBigInt(b.BaseFeePerGas, v, "baseFeePerGas"); err != nil {
+			return err
+		}
+	}
+	if b.WithdrawalsRoot, err = decodeOptionalHash(v, "withdrawalsRoot"); err != nil {
+		return err
+	}
+	b.Withdrawals = nil
+	if fieldNotFull(v, "withdrawals") {
+		b.Withdrawals = []*Withdrawal{}
+		for _, elem := range v.GetArray("withdrawals") {
+			w := new(Withdrawal)
+			if err := w.unmarshalJSON(elem); err != nil {
+				return err
+			}
+			b.Withdrawals = append(b.Withdrawals, w)
+		}
+	}
+	if b.BlobGasUsed, err = decodeOptionalUint(v, "blobGasUsed"); err != nil {
+		return err
+	}
+	if b.ExcessBlobGas, err = decodeOptionalUint(v, "excessBlobGas"); err != nil {
+		return err
+	}
+	if b.ParentBeaconBlockRoot, err = decodeOptionalHash(v, "parentBeaconBlockRoot"); err != nil {
+		return err
+	}
+	if b.RequestsHash, err = decodeOptionalHash(v, "requestsHash"); err != nil {
+		return err
+	}
+
 	b.TransactionsHashes = b.TransactionsHashes[:0]
 	b.Transactions = b.Transactions[:0]
 
@@ -101,6 +158,35 @@ func (b *Block) UnmarshalJSON(buf []byte) error {
 	return nil
 }
 
+// UnmarshalJSON implements the unmarshal interface
+func (w *Withdrawal) UnmarshalJSON(buf []byte) error {
+	p := defaultPool.Get()
+	defer defaultPool.Put(p)
+
+	v, err := p.Parse(string(buf))
+	if err != nil {
+		return err
+	}
+	return w.unmarshalJSON(v)
+}
+
+func (w *Withdrawal) unmarshalJSON(v *fastjson.Value) error {
+	var err error
+	if w.Index, err = decodeUint(v, "index"); err != nil {
+		return err
+	}
+	if w.ValidatorIndex, err = decodeUint(v, "validatorIndex"); err != nil {
+		return err
+	}
+	if err := decodeAddr(&w.Address, v, "address"); err != nil {
+		return err
+	}
+	if w.Amount, err = decodeUint(v, "amount"); err != nil {
+		return err
+	}
+	return nil
+}
+
 // UnmarshalJSON implements the unmarshal interface
 func (t *Transaction) UnmarshalJSON(buf []byte) error {
 	p := defaultPool.Get()
@@ -132,8 +218,18 @@ func (t *Transaction) unmarshalJSON(v *fastjson.Value) error {
 
 	// detect transaction type
 	var typ TransactionType
-	if isKeySet(v, "chainId") {
-		if isKeySet(v, "maxFeePerGas") {
+	if isKeySet(v, "type") {
+		num, err := decodeUint(v, "type")
+		if err != nil {
+			return err
+		}
+		typ = TransactionType(num)
+	} else if isKeySet(v, "chainId") {
+		if isKeySet(v, "blobVersionedHashes") {
+			typ = TransactionBlob
+		} else if isKeySet(v, "authorizationList") {
+			typ = TransactionSetCode
+		} else if isKeySet(v, "maxFeePerGas") {
 			typ = TransactionDynamicFee
 		} else {
 			typ = TransactionAccessList
@@ -150,10 +246,18 @@ func (t *Transaction) unmarshalJSON(v *fastjson.Value) error {
 	if err = decodeAddr(&t.From, v, "from"); err != nil {
 		return err
 	}
-	if t.GasPrice, err = decodeUint(v, "gasPrice"); err != nil {
-		return err
+	// some clients do not return the gas price of the dynamic fee
+	// transactions nor the signature of the pending transactions
+	if isKeySet(v, "gasPrice") {
+		if t.GasPrice, err = decodeUint(v, "gasPrice"); err != nil {
+			return err
+		}
+	}
+	inputKey := "input"
+	if !isKeySet(v, inputKey) && isKeySet(v, "data") {
+		inputKey = "data"
 	}
-	if t.Input, err = decodeBytes(t.Input[:0], v, "input"); err != nil {
+	if t.Input, err = decodeBytes(t.Input[:0], v, inputKey); err != nil {
 		return err
 	}
 	if t.Value, err = decodeBigInt(t.Value, v, "value"); err != nil {
@@ -176,19 +280,23 @@ func (t *Transaction) unmarshalJSON(v *fastjson.Value) error {
 		}
 	}
 
-	if t.V, err = decodeBytes(t.V[:0], v, "v"); err != nil {
-		return err
-	}
-	if t.R, err = decodeBytes(t.R[:0], v, "r"); err != nil {
-		return err
-	}
-	if t.S, err = decodeBytes(t.S[:0], v, "s"); err != nil {
-		return err
+	if isKeySet(v, "v") {
+		if t.V, err = decodeBytes(t.V[:0], v, "v"); err != nil {
+			return err
+		}
+		if t.R, err = decodeBytes(t.R[:0], v, "r"); err != nil {
+			return err
+		}
+		if t.S, err = decodeBytes(t.S[:0], v, "s"); err != nil {
+			return err
+		}
 	}
 
 	if typ != TransactionLegacy {
-		if t.ChainID, err = decodeBigInt(t.ChainID, v, "chainId"); err != nil {
-			return err
+		if isKeySet(v, "chainId") {
+			if t.ChainID, err = decodeBigInt(t.ChainID, v, "chainId"); err != nil {
+				return err
+			}
 		}
 		if isKeySet(v, "accessList") {
 			if err := t.AccessList.unmarshalJSON(v.Get("accessList")); err != nil {
@@ -201,7 +309,7 @@ func (t *Transaction) unmarshalJSON(v *fastjson.Value) error {
 		return err
 	}
 
-	if typ == TransactionDynamicFee {
+	if typ == TransactionDynamicFee || (typ > TransactionDynamicFee && isKeySet(v, "maxFeePerGas")) {
 		if t.MaxPriorityFeePerGas, err = decodeBigInt(t.MaxPriorityFeePerGas, v, "maxPriorityFeePerGas"); err != nil {
 			return err
 		}
@@ -210,6 +318,33 @@ func (t *Transaction) unmarshalJSON(v *fastjson.Value) error {
 		}
 	}
 
+	t.MaxFeePerBlobGas, t.BlobVersionedHashes = nil, nil
+	if typ == TransactionBlob {
+		if t.MaxFeePerBlobGas, err = decodeBigInt(t.MaxFeePerBlobGas, v, "maxFeePerBlobGas"); err != nil {
+			return err
+		}
+		t.BlobVersionedHashes = []Hash{}
+		for _, elem := range v.GetArray("blobVersionedHashes") {
+			var h Hash
+			if err := h.UnmarshalText(elem.GetStringBytes()); err != nil {
+				return err
+			}
+			t.BlobVersionedHashes = append(t.BlobVersionedHashes, h)
+		}
+	}
+
+	t.AuthorizationList = nil
+	if typ == TransactionSetCode {
+		t.AuthorizationList = []*Authorization{}
+		for _, elem := range v.GetArray("authorizationList") {
+			auth := new(Authorization)
+			if err := auth.unmarshalJSON(elem); err != nil {
+				return err
+			}
+			t.AuthorizationList = append(t.AuthorizationList, auth)
+		}
+	}
+
 	// Check if the block hash field is set
 	// If it's not -> the transaction is a pending txn, so these fields should be omitted
 	// If it is -> the transaction is a sealed txn, so these fields should be included
@@ -235,6 +370,46 @@ func (t *Transaction) unmarshalJSON(v *fastjson.Value) error {
 	return nil
 }
 
+// UnmarshalJSON implements the unmarshal interface
+func (a *Authorization) UnmarshalJSON(buf []byte) error {
+	p := defaultPool.Get()
+	defer defaultPool.Put(p)
+
+	v, err := p.Parse(string(buf))
+	if err != nil {
+		return err
+	}
+	return a.unmarshalJSON(v)
+}
+
+func (a *Authorization) unmarshalJSON(v *fastjson.Value) error {
+	var err error
+	if a.ChainID, err = decodeBigInt(a.ChainID, v, "chainId"); err != nil {
+		return err
+	}
+	if err := decodeAddr(&a.Address, v, "address"); err != nil {
+		return err
+	}
+	if a.Nonce, err = decodeUint(v, "nonce"); err != nil {
+		return err
+	}
+	// some clients use 'v' instead of 'yParity'
+	parityKey := "yParity"
+	if !isKeySet(v, parityKey) && isKeySet(v, "v") {
+		parityKey = "v"
+	}
+	if a.YParity, err = decodeUint(v, parityKey); err != nil {
+		return err
+	}
+	if a.R, err = decodeBytes(a.R[:0], v, "r"); err != nil {
+		return err
+	}
+	if a.S, err = decodeBytes(a.S[:0], v, "s"); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (t *AccessList) unmarshalJSON(v *fastjson.Value) error {
 	elems, err := v.Array()
 	if err != nil {
@@ -275,9 +450,25 @@ func (r *Receipt) UnmarshalJSON(buf []byte) error {
 		return nil
 	}
 
+	r.Type = TransactionLegacy
+	if isKeySet(v, "type") {
+		typ, err := decodeUint(v, "type")
+		if err != nil {
+			return err
+		}
+		r.Type = TransactionType(typ)
+	}
 	if err := decodeAddr(&r.From, v, "from"); err != nil {
 		return err
 	}
+	r.To = nil
+	if fieldNotFull(v, "to") {
+		var to Address
+		if err := decodeAddr(&to, v, "to"); err != nil {
+			return err
+		}
+		r.To = &to
+	}
 	if fieldNotFull(v, "contractAddress") {
 		if err := decodeAddr(&r.ContractAddress, v, "contractAddress"); err != nil {
 			return err
@@ -304,9 +495,35 @@ func (r *Receipt) UnmarshalJSON(buf []byte) error {
 	if r.LogsBloom, err = decodeBytes(r.LogsBloom[:0], v, "logsBloom", 256); err != nil {
 		return err
 	}
-	if r.Status, err = decodeUint(v, "status"); err != nil {
+	// the receipts before byzantium have the state root instead of the status
+	if r.Root, err = decodeOptionalHash(v, "root"); err != nil {
 		return err
 	}
+	r.Status = 0
+	if r.Root == nil || isKeySet(v, "status") {
+		if r.Status, err = decodeUint(v, "status"); err != nil {
+			return err
+		}
+	}
+	// the effective gas price is not returned by the nodes before london
+	r.EffectiveGasPrice = nil
+	if isKeySet(v, "effectiveGasPrice") {
+		if r.EffectiveGasPrice, err = decodeBigInt(r.EffectiveGasPrice, v, "effectiveGasPrice"); err != nil {
+			return err
+		}
+	}
+	r.BlobGasUsed = 0
+	if isKeySet(v, "blobGasUsed") {
+		if r.BlobGasUsed, err = decodeUint(v, "blobGasUsed"); err != nil {
+			return err
+		}
+	}
+	r.BlobGasPrice = nil
+	if isKeySet(v, "blobGasPrice") {
+		if r.BlobGasPrice, err = decodeBigInt(r.BlobGasPrice, v, "blobGasPrice"); err != nil {
+			return err
+		}
+	}
 
 	// logs
 	r.Logs = r.Logs[:0]
@@ -497,6 +714,106 @@ func (r *Log) unmarshalJSON(v *fastjson.Value) error {
 	return nil
 }
 
+// UnmarshalJSON implements the unmarshal interface
+func (a *AccountProof) UnmarshalJSON(buf []byte) error {
+	p := defaultPool.Get()
+	defer defaultPool.Put(p)
+
+	v, err := p.Parse(string(buf))
+	if err != nil {
+		return err
+	}
+
+	if err := decodeAddr(&a.Address, v, "address"); err != nil {
+		return err
+	}
+	if a.AccountProof, err = decodeBytesArray(v, "accountProof"); err != nil {
+		return err
+	}
+	if a.Balance, err = decodeBigInt(a.Balance, v, "balance"); err != nil {
+		return err
+	}
+	if err := decodeHash(&a.CodeHash, v, "codeHash"); err != nil {
+		return err
+	}
+	if a.Nonce, err = decodeUint(v, "nonce"); err != nil {
+		return err
+	}
+	if err := decodeHash(&a.StorageHash, v, "storageHash"); err != nil {
+		return err
+	}
+
+	a.StorageProof = a.StorageProof[:0]
+	for _, elem := range v.GetArray("storageProof") {
+		proof := new(StorageProof)
+		if err := proof.unmarshalJSON(elem); err != nil {
+			return err
+		}
+		a.StorageProof = append(a.StorageProof, proof)
+	}
+	return nil
+}
+
+// UnmarshalJSON implements the unmarshal interface
+func (s *StorageProof) UnmarshalJSON(buf []byte) error {
+	p := defaultPool.Get()
+	defer defaultPool.Put(p)
+
+	v, err := p.Parse(string(buf))
+	if err != nil {
+		return err
+	}
+	return s.unmarshalJSON(v)
+}
+
+func (s *StorageProof) unmarshalJSON(v *fastjson.Value) error {
+	// some clients return the key with the leading zeros removed
+	key, err := decodeBytes(nil, v, "key")
+	if err != nil {
+		return err
+	}
+	if len(key) > 32 {
+		return fmt.Errorf("field 'key' invalid length, expected at most 32 but found %d", len(key))
+	}
+	s.Key = Hash{}
+	copy(s.Key[32-len(key):], key)
+
+	if s.Value, err = decodeBigInt(s.Value, v, "value"); err != nil {
+		return err
+	}
+	if s.Proof, err = decodeBytesArray(v, "proof"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func decodeBytesArray(v *fastjson.Value, key string) ([][]byte, error) {
+	vv := v.Get(key)
+	if vv == nil {
+		return nil, fmt.Errorf("field '%s' not found", key)
+	}
+	elems, err := vv.Array()
+	if err != nil {
+		return nil, fmt.Errorf("field '%s' is not an array: %v", key, err)
+	}
+	res := make([][]byte, 0, len(elems))
+	for _, elem := range elems {
+		str, err := elem.StringBytes()
+		if err != nil {
+			return nil, fmt.Errorf("field '%s' has a non string element: %v", key, err)
+		}
+		if !bytes.HasPrefix(str, []byte("0x")) {
+			return nil, fmt.Errorf("field '%s' does not have 0x prefix: '%s'", key, str)
+		}
+		buf, err := hex.DecodeString(string(str[2:]))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, buf)
+	}
+	return res, nil
+}
+
 func fieldNotFull(v *fastjson.Value, key string) bool {
 	vv := v.Get(key)
 	if vv == nil {
@@ -618,6 +935,30 @@ func decodeHash(h *Hash, v *fastjson.Value, key string) error {
 	return nil
 }
 
+// decodeOptionalHash decodes a hash that is only present after a fork
+func decodeOptionalHash(v *fastjson.Value, key string) (*Hash, error) {
+	if !fieldNotFull(v, key) {
+		return nil, nil
+	}
+	h := new(Hash)
+	if err := decodeHash(h, v, key); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// decodeOptionalUint decodes an uint that is only present after a fork
+func decodeOptionalUint(v *fastjson.Value, key string) (*uint64, error) {
+	if !fieldNotFull(v, key) {
+		return nil, nil
+	}
+	num, err := decodeUint(v, key)
+	if err != nil {
+		return nil, err
+	}
+	return &num, nil
+}
+
 func decodeAddr(a *Address, v *fastjson.Value, key string) error {
 	b := v.GetStringBytes(key)
 	if len(b) == 0 {
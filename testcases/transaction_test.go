@@ -143,7 +143,7 @@ func TestTypedTransactions(t *testing.T) {
 				txn.GasPrice = gasPrice
 			}
 
-			signer := wallet.NewEIP155Signer(chainID.Uint64())
+			signer := wallet.LatestSignerForChainID(chainID.Uint64())
 			signedTxn, err := signer.SignTx(txn, key)
 			assert.NoError(t, err)
 
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -334,15 +335,13 @@ func (t *Tracker) emitLogs(typ EventType, logs []*ethgo.Log) {
 	t.emitEvent(evnt)
 }
 
-func tooMuchDataRequestedError(err error) bool {
-	obj, ok := err.(*codec.ErrorObject)
-	if !ok {
-		return false
+// tooMuchDataRequestedError returns the range limit error of the provider (if any)
+func tooMuchDataRequestedError(err error) (*codec.RangeLimitError, bool) {
+	var rangeErr *codec.RangeLimitError
+	if errors.As(err, &rangeErr) {
+		return rangeErr, true
 	}
-	if obj.Message == "query returned more than 10000 results" {
-		return true
-	}
-	return false
+	return nil, false
 }
 
 func (t *Tracker) syncBatch(ctx context.Context, from, to uint64) error {
@@ -361,9 +360,21 @@ START:
 
 	logs, err := t.provider.GetLogs(query)
 	if err != nil {
-		if tooMuchDataRequestedError(err) {
-			// multiplicative decrease
-			batchSize = batchSize / 2
+		if rangeErr, ok := tooMuchDataRequestedError(err); ok {
+			if batchSize == 0 {
+				// the query of a single block cannot be split
+				return err
+			}
+			if rangeErr.From != nil && rangeErr.To != nil && *rangeErr.From == i && *rangeErr.To > i && *rangeErr.To < dst {
+				// use the range suggested by the provider
+				batchSize = *rangeErr.To - i
+			} else {
+				// multiplicative decrease
+				batchSize = batchSize / 2
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			goto START
 		}
 		return err
@@ -814,6 +825,11 @@ func (t *Tracker) doFilter(added []*ethgo.Block, removed []*ethgo.Block) (*Event
 		query := t.config.Filter.getFilterSearch()
 		query.BlockHash = &block.Hash
 
+		if len(block.LogsBloom) != 0 && !query.MatchBloom(block.Bloom()) {
+			// the bloom of the block cannot match the filter, there are no logs to query
+			continue
+		}
+
 		// We check the hash, we need to do a retry to let unsynced nodes get the block
 		var logs []*ethgo.Log
 		var err error
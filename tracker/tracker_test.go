@@ -2,6 +2,7 @@ package tracker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"math/rand"
@@ -816,3 +817,80 @@ func TestTooMuchDataRequested(t *testing.T) {
 		t.Fatal("not the same count")
 	}
 }
+
+type mockClientAlwaysLimit struct {
+	testutil.MockClient
+}
+
+func (m *mockClientAlwaysLimit) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	return nil, &codec.ErrorObject{Message: "query returned more than 10000 results"}
+}
+
+func TestTooMuchDataRequested_SingleBlock(t *testing.T) {
+	l := testutil.MockList{}
+	l.Create(0, 100, func(b *testutil.MockBlock) {
+		b.Log("0x1")
+	})
+
+	m := &mockClientAlwaysLimit{}
+	m.AddScenario(l)
+
+	tt, _ := NewTracker(m,
+		WithFilter(&FilterConfig{Async: true}),
+	)
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- tt.Sync(context.Background())
+	}()
+
+	// the sync fails once the query of a single block is rejected
+	select {
+	case err := <-errCh:
+		var rangeErr *codec.RangeLimitError
+		if !errors.As(err, &rangeErr) {
+			t.Fatalf("expected a range limit error but found %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+type mockClientWithCount struct {
+	testutil.MockClient
+	queries []ethgo.Hash
+}
+
+func (m *mockClientWithCount) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	m.queries = append(m.queries, *filter.BlockHash)
+	return nil, nil
+}
+
+func TestTrackerBloomSkip(t *testing.T) {
+	addr := ethgo.Address{0x1}
+
+	var match ethgo.Bloom
+	match.Add(addr[:])
+
+	var other ethgo.Bloom
+	other.Add(ethgo.Address{0x2}.Bytes())
+
+	blocks := []*ethgo.Block{
+		{Number: 1, Hash: ethgo.Hash{0x1}, LogsBloom: match.Bytes()},
+		{Number: 2, Hash: ethgo.Hash{0x2}, LogsBloom: other.Bytes()},
+		// the logs are queried if the block does not have a bloom
+		{Number: 3, Hash: ethgo.Hash{0x3}},
+	}
+
+	m := &mockClientWithCount{}
+	tt, err := NewTracker(m, WithFilter(&FilterConfig{Address: []ethgo.Address{addr}}))
+	assert.NoError(t, err)
+
+	_, err = tt.doFilter(blocks, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []ethgo.Hash{{0x1}, {0x3}}, m.queries)
+
+	last, err := tt.GetLastBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), last.Number)
+}
@@ -0,0 +1,218 @@
+package trie
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// EmptyCodeHash is the code hash of an account without code
+var EmptyCodeHash = ethgo.HexToHash("0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470")
+
+// Account is an account in the state trie
+type Account struct {
+	Nonce       uint64
+	Balance     *big.Int
+	StorageRoot ethgo.Hash
+	CodeHash    ethgo.Hash
+}
+
+// emptyAccount returns the account of an address that is not in the state
+func emptyAccount() *Account {
+	return &Account{
+		Balance:     big.NewInt(0),
+		StorageRoot: EmptyRoot,
+		CodeHash:    EmptyCodeHash,
+	}
+}
+
+// MarshalRLPTo marshals the account to a []byte destination
+func (a *Account) MarshalRLPTo(dst []byte) ([]byte, error) {
+	raw, err := fastrlp.MarshalRLP(a)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, raw...), nil
+}
+
+// MarshalRLPWith marshals the account to RLP with a specific fastrlp.Arena
+func (a *Account) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	vv := arena.NewArray()
+	vv.Set(arena.NewUint(a.Nonce))
+	vv.Set(arena.NewBigInt(a.Balance))
+	vv.Set(arena.NewCopyBytes(a.StorageRoot[:]))
+	vv.Set(arena.NewCopyBytes(a.CodeHash[:]))
+	return vv, nil
+}
+
+// UnmarshalRLP unmarshals an account in RLP format
+func (a *Account) UnmarshalRLP(buf []byte) error {
+	return fastrlp.UnmarshalRLP(buf, a)
+}
+
+// UnmarshalRLPWith unmarshals an account using a fastrlp.Value
+func (a *Account) UnmarshalRLPWith(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 4 {
+		return fmt.Errorf("incorrect number of elements to decode account, expected 4 but found %d", len(elems))
+	}
+	if a.Nonce, err = elems[0].GetUint64(); err != nil {
+		return err
+	}
+	a.Balance = new(big.Int)
+	if err := elems[1].GetBigInt(a.Balance); err != nil {
+		return err
+	}
+	if err := elems[2].GetHash(a.StorageRoot[:]); err != nil {
+		return err
+	}
+	if err := elems[3].GetHash(a.CodeHash[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MismatchError is returned when a value in the eth_getProof response does not
+// match the value verified with the proof
+type MismatchError struct {
+	Field    string
+	Verified string
+	Claimed  string
+}
+
+func (m *MismatchError) Error() string {
+	return fmt.Sprintf("%s mismatch: the proof has %s but the response claims %s", m.Field, m.Verified, m.Claimed)
+}
+
+// StorageError is returned when the proof of a storage slot is not valid
+type StorageError struct {
+	Key ethgo.Hash
+	Err error
+}
+
+func (s *StorageError) Error() string {
+	return fmt.Sprintf("storage slot %s: %v", s.Key, s.Err)
+}
+
+func (s *StorageError) Unwrap() error {
+	return s.Err
+}
+
+// VerifiedAccount is the state of an account verified with its proof
+type VerifiedAccount struct {
+	*Account
+	Address ethgo.Address
+	Storage map[ethgo.Hash]*big.Int
+}
+
+// VerifyBlock verifies the account and storage proofs of the address and the
+// slots requested with eth_getProof against the state root of the block
+func VerifyBlock(block *ethgo.Block, addr ethgo.Address, slots []ethgo.Hash, proof *ethgo.AccountProof) (*VerifiedAccount, error) {
+	return Verify(block.StateRoot, addr, slots, proof)
+}
+
+// Verify verifies the account and storage proofs of the address and the slots
+// requested with eth_getProof against the state root
+func Verify(stateRoot ethgo.Hash, addr ethgo.Address, slots []ethgo.Hash, proof *ethgo.AccountProof) (*VerifiedAccount, error) {
+	account, err := VerifyAccount(stateRoot, addr, proof)
+	if err != nil {
+		return nil, err
+	}
+	if len(proof.StorageProof) != len(slots) {
+		return nil, fmt.Errorf("expected %d storage proofs but found %d", len(slots), len(proof.StorageProof))
+	}
+	res := &VerifiedAccount{
+		Account: account,
+		Address: addr,
+		Storage: map[ethgo.Hash]*big.Int{},
+	}
+	for indx, slot := range slots {
+		value, err := VerifyStorage(account.StorageRoot, slot, proof.StorageProof[indx])
+		if err != nil {
+			return nil, &StorageError{Key: slot, Err: err}
+		}
+		res.Storage[slot] = value
+	}
+	return res, nil
+}
+
+// VerifyAccount verifies the account proof of the address against the state root and
+// checks that the values in the proof match. An address that is not in the state
+// returns an empty account.
+func VerifyAccount(stateRoot ethgo.Hash, addr ethgo.Address, proof *ethgo.AccountProof) (*Account, error) {
+	if proof.Address != addr {
+		// the proof could be valid but for another account
+		return nil, fmt.Errorf("the proof is for address %s but %s was requested", proof.Address, addr)
+	}
+	val, err := VerifyProof(stateRoot, ethgo.Keccak256(addr[:]), proof.AccountProof)
+	if err != nil {
+		return nil, err
+	}
+
+	account := emptyAccount()
+	if val != nil {
+		if err := account.UnmarshalRLP(val); err != nil {
+			return nil, &ProofError{Index: len(proof.AccountProof) - 1, Reason: fmt.Sprintf("failed to decode account: %v", err)}
+		}
+	}
+
+	if account.Nonce != proof.Nonce {
+		return nil, &MismatchError{Field: "nonce", Verified: fmt.Sprint(account.Nonce), Claimed: fmt.Sprint(proof.Nonce)}
+	}
+	if account.Balance.Cmp(bigOrZero(proof.Balance)) != 0 {
+		return nil, &MismatchError{Field: "balance", Verified: account.Balance.String(), Claimed: bigOrZero(proof.Balance).String()}
+	}
+
+	// some clients return zero hashes for the accounts that do not exist
+	missing := val == nil && proof.StorageHash == ethgo.ZeroHash
+	if account.StorageRoot != proof.StorageHash && !missing {
+		return nil, &MismatchError{Field: "storageHash", Verified: account.StorageRoot.String(), Claimed: proof.StorageHash.String()}
+	}
+	missing = val == nil && proof.CodeHash == ethgo.ZeroHash
+	if account.CodeHash != proof.CodeHash && !missing {
+		return nil, &MismatchError{Field: "codeHash", Verified: account.CodeHash.String(), Claimed: proof.CodeHash.String()}
+	}
+	return account, nil
+}
+
+// VerifyStorage verifies the storage proof of the slot against the storage root of the
+// account and checks that the value in the proof matches. It returns the value of the slot.
+func VerifyStorage(storageRoot ethgo.Hash, slot ethgo.Hash, proof *ethgo.StorageProof) (*big.Int, error) {
+	if proof.Key != slot {
+		return nil, fmt.Errorf("the proof is for slot %s but %s was requested", proof.Key, slot)
+	}
+	val, err := VerifyProof(storageRoot, ethgo.Keccak256(slot[:]), proof.Proof)
+	if err != nil {
+		return nil, err
+	}
+
+	value := new(big.Int)
+	if val != nil {
+		// the values are stored as rlp encoded bytes
+		var p fastrlp.Parser
+		v, err := p.Parse(val)
+		if err == nil {
+			err = v.GetBigInt(value)
+		}
+		if err != nil || v.Len() > 32 {
+			return nil, &ProofError{Index: len(proof.Proof) - 1, Reason: fmt.Sprintf("invalid storage value %x", val)}
+		}
+	}
+
+	if value.Cmp(bigOrZero(proof.Value)) != 0 {
+		return nil, &MismatchError{Field: "value", Verified: value.String(), Claimed: bigOrZero(proof.Value).String()}
+	}
+	return value, nil
+}
+
+func bigOrZero(b *big.Int) *big.Int {
+	if b == nil {
+		return new(big.Int)
+	}
+	return b
+}
@@ -0,0 +1,197 @@
+package trie
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// testState is a state trie with a single account with storage
+type testState struct {
+	state   *Trie
+	storage *Trie
+	addr    ethgo.Address
+	account *Account
+}
+
+func newTestState(t *testing.T) *testState {
+	s := &testState{
+		state:   New(),
+		storage: New(),
+		addr:    ethgo.Address{0x1},
+	}
+
+	a := &fastrlp.Arena{}
+	for i := 1; i <= 10; i++ {
+		slot := ethgo.Hash{31: byte(i)}
+		value := a.NewUint(uint64(i * 1000)).MarshalTo(nil)
+		s.storage.Put(ethgo.Keccak256(slot[:]), value)
+	}
+	s.account = &Account{
+		Nonce:       5,
+		Balance:     big.NewInt(1000000),
+		StorageRoot: s.storage.Hash(),
+		CodeHash:    ethgo.BytesToHash(ethgo.Keccak256([]byte{0x1})),
+	}
+
+	// other accounts in the state
+	for i := 2; i < 50; i++ {
+		addr := ethgo.Address{byte(i)}
+		raw, err := emptyAccount().MarshalRLPTo(nil)
+		assert.NoError(t, err)
+		s.state.Put(ethgo.Keccak256(addr[:]), raw)
+	}
+	raw, err := s.account.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+	s.state.Put(ethgo.Keccak256(s.addr[:]), raw)
+	return s
+}
+
+// proofJSON returns the eth_getProof response of the address and the slots
+func (s *testState) proofJSON(addr ethgo.Address, account *Account, slots ...uint64) string {
+	hexProof := func(proof [][]byte) string {
+		elems := []string{}
+		for _, node := range proof {
+			elems = append(elems, fmt.Sprintf(`"0x%x"`, node))
+		}
+		return "[" + strings.Join(elems, ",") + "]"
+	}
+
+	storage := []string{}
+	for _, slot := range slots {
+		key := ethgo.Hash{31: byte(slot)}
+		value := uint64(0)
+		if slot <= 10 {
+			value = slot * 1000
+		}
+		storage = append(storage, fmt.Sprintf(`{"key":"0x%x","value":"0x%x","proof":%s}`,
+			slot, value, hexProof(s.storage.Prove(ethgo.Keccak256(key[:])))))
+	}
+
+	return fmt.Sprintf(`{
+		"address": "%s",
+		"accountProof": %s,
+		"balance": "0x%x",
+		"codeHash": "%s",
+		"nonce": "0x%x",
+		"storageHash": "%s",
+		"storageProof": [%s]
+	}`, addr, hexProof(s.state.Prove(ethgo.Keccak256(addr[:]))), account.Balance,
+		account.CodeHash, account.Nonce, account.StorageRoot, strings.Join(storage, ","))
+}
+
+func (s *testState) proof(t *testing.T, addr ethgo.Address, account *Account, slots ...uint64) *ethgo.AccountProof {
+	var proof *ethgo.AccountProof
+	assert.NoError(t, json.Unmarshal([]byte(s.proofJSON(addr, account, slots...)), &proof))
+	return proof
+}
+
+func TestVerify(t *testing.T) {
+	s := newTestState(t)
+
+	block := &ethgo.Block{StateRoot: s.state.Hash()}
+	slots := []ethgo.Hash{{31: 1}, {31: 10}, {31: 11}}
+	res, err := VerifyBlock(block, s.addr, slots, s.proof(t, s.addr, s.account, 1, 10, 11))
+	assert.NoError(t, err)
+
+	assert.Equal(t, s.addr, res.Address)
+	assert.Equal(t, uint64(5), res.Nonce)
+	assert.Equal(t, "1000000", res.Balance.String())
+	assert.Equal(t, s.account.CodeHash, res.CodeHash)
+	assert.Equal(t, s.storage.Hash(), res.StorageRoot)
+
+	assert.Len(t, res.Storage, 3)
+	assert.Equal(t, "1000", res.Storage[ethgo.Hash{31: 1}].String())
+	assert.Equal(t, "10000", res.Storage[ethgo.Hash{31: 10}].String())
+	assert.Equal(t, "0", res.Storage[ethgo.Hash{31: 11}].String())
+}
+
+func TestVerify_MissingAccount(t *testing.T) {
+	s := newTestState(t)
+
+	// some clients return a zero code hash for the missing accounts
+	empty := emptyAccount()
+	empty.CodeHash = ethgo.ZeroHash
+
+	addr := ethgo.Address{0xff}
+	account, err := VerifyAccount(s.state.Hash(), addr, s.proof(t, addr, empty))
+	assert.NoError(t, err)
+	assert.Equal(t, emptyAccount(), account)
+
+	// the response claims that the account exists
+	_, err = VerifyAccount(s.state.Hash(), addr, s.proof(t, addr, s.account))
+	var mismatchErr *MismatchError
+	assert.True(t, errors.As(err, &mismatchErr))
+	assert.Equal(t, "nonce", mismatchErr.Field)
+}
+
+func TestVerify_Invalid(t *testing.T) {
+	s := newTestState(t)
+	root := s.state.Hash()
+
+	// the balance of the response does not match the proof
+	account := *s.account
+	account.Balance = big.NewInt(1)
+
+	_, err := Verify(root, s.addr, nil, s.proof(t, s.addr, &account))
+	var mismatchErr *MismatchError
+	assert.True(t, errors.As(err, &mismatchErr))
+	assert.Equal(t, "balance", mismatchErr.Field)
+	assert.Equal(t, "1000000", mismatchErr.Verified)
+	assert.Equal(t, "1", mismatchErr.Claimed)
+
+	// the value of the slot does not match the proof
+	proof := s.proof(t, s.addr, s.account, 1, 2)
+	proof.StorageProof[1].Value = big.NewInt(1)
+
+	_, err = Verify(root, s.addr, []ethgo.Hash{{31: 1}, {31: 2}}, proof)
+	var storageErr *StorageError
+	assert.True(t, errors.As(err, &storageErr))
+	assert.Equal(t, ethgo.Hash{31: 2}, storageErr.Key)
+	assert.True(t, errors.As(err, &mismatchErr))
+	assert.Equal(t, "value", mismatchErr.Field)
+
+	// the proof of the slot is from another account
+	proof = s.proof(t, s.addr, s.account, 1)
+	proof.StorageProof[0].Proof = proof.AccountProof
+
+	_, err = Verify(root, s.addr, []ethgo.Hash{{31: 1}}, proof)
+	var proofErr *ProofError
+	assert.True(t, errors.As(err, &proofErr))
+
+	// the proof is from another state
+	_, err = Verify(ethgo.Hash{0x1}, s.addr, nil, s.proof(t, s.addr, s.account))
+	assert.True(t, errors.As(err, &proofErr))
+}
+
+func TestVerify_Swapped(t *testing.T) {
+	s := newTestState(t)
+	root := s.state.Hash()
+
+	// the node returns the valid proof of another account
+	other := ethgo.Address{0x2}
+	_, err := Verify(root, s.addr, nil, s.proof(t, other, emptyAccount()))
+	assert.Error(t, err)
+
+	_, err = VerifyAccount(root, other, s.proof(t, other, emptyAccount()))
+	assert.NoError(t, err)
+
+	// the node returns the valid proof of another slot
+	proof := s.proof(t, s.addr, s.account, 2)
+	_, err = Verify(root, s.addr, []ethgo.Hash{{31: 1}}, proof)
+	var storageErr *StorageError
+	assert.True(t, errors.As(err, &storageErr))
+	assert.Equal(t, ethgo.Hash{31: 1}, storageErr.Key)
+
+	// the node does not return the proof of every slot
+	proof = s.proof(t, s.addr, s.account, 1)
+	_, err = Verify(root, s.addr, []ethgo.Hash{{31: 1}, {31: 2}}, proof)
+	assert.Error(t, err)
+}
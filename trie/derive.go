@@ -0,0 +1,86 @@
+package trie
+
+import (
+	"fmt"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// RootMismatchError is returned when the root computed from a list of items
+// does not match the root in the header of the block
+type RootMismatchError struct {
+	Field    string
+	Computed ethgo.Hash
+	Claimed  ethgo.Hash
+}
+
+func (r *RootMismatchError) Error() string {
+	return fmt.Sprintf("%s mismatch: the items have %s but the block claims %s", r.Field, r.Computed, r.Claimed)
+}
+
+// DeriveTransactionsRoot returns the root of the trie of the transactions
+// indexed by their position in the block
+func DeriveTransactionsRoot(txns []*ethgo.Transaction) (ethgo.Hash, error) {
+	return deriveRoot(len(txns), func(indx int, dst []byte) ([]byte, error) {
+		// the trie does not include the blob sidecar
+		txn := *txns[indx]
+		txn.Sidecar = nil
+		return txn.MarshalRLPTo(dst)
+	})
+}
+
+// DeriveReceiptsRoot returns the root of the trie of the receipts
+// indexed by the position of their transaction in the block
+func DeriveReceiptsRoot(receipts []*ethgo.Receipt) (ethgo.Hash, error) {
+	return deriveRoot(len(receipts), func(indx int, dst []byte) ([]byte, error) {
+		return receipts[indx].MarshalRLPTo(dst)
+	})
+}
+
+// VerifyTransactionsRoot checks that the transactions of the block match its
+// transactions root. The block must include the full transactions.
+func VerifyTransactionsRoot(block *ethgo.Block) error {
+	if len(block.Transactions) == 0 && len(block.TransactionsHashes) != 0 {
+		return fmt.Errorf("the block does not include the full transactions")
+	}
+	root, err := DeriveTransactionsRoot(block.Transactions)
+	if err != nil {
+		return err
+	}
+	if root != block.TransactionsRoot {
+		return &RootMismatchError{Field: "transactions root", Computed: root, Claimed: block.TransactionsRoot}
+	}
+	return nil
+}
+
+// VerifyReceiptsRoot checks that the receipts (in the order of the
+// transactions of the block) match the receipts root of the block
+func VerifyReceiptsRoot(block *ethgo.Block, receipts []*ethgo.Receipt) error {
+	root, err := DeriveReceiptsRoot(receipts)
+	if err != nil {
+		return err
+	}
+	if root != block.ReceiptsRoot {
+		return &RootMismatchError{Field: "receipts root", Computed: root, Claimed: block.ReceiptsRoot}
+	}
+	return nil
+}
+
+func deriveRoot(num int, encode func(indx int, dst []byte) ([]byte, error)) (ethgo.Hash, error) {
+	a := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(a)
+
+	tr := New()
+
+	var key, value []byte
+	for indx := 0; indx < num; indx++ {
+		var err error
+		if value, err = encode(indx, value[:0]); err != nil {
+			return ethgo.Hash{}, fmt.Errorf("failed to encode item %d: %v", indx, err)
+		}
+		key = a.NewUint(uint64(indx)).MarshalTo(key[:0])
+		tr.Put(key, value)
+	}
+	return tr.Hash(), nil
+}
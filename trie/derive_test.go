@@ -0,0 +1,105 @@
+package trie
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+func TestDeriveRoot_Single(t *testing.T) {
+	receipt := &ethgo.Receipt{
+		Type:              ethgo.TransactionDynamicFee,
+		Status:            1,
+		CumulativeGasUsed: 21000,
+	}
+	value, err := receipt.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+
+	// the trie has a single leaf with the key rlp(0) = 0x80
+	a := &fastrlp.Arena{}
+	leaf := a.NewArray()
+	leaf.Set(a.NewBytes([]byte{0x20, 0x80}))
+	leaf.Set(a.NewBytes(value))
+
+	root, err := DeriveReceiptsRoot([]*ethgo.Receipt{receipt})
+	assert.NoError(t, err)
+	assert.Equal(t, ethgo.BytesToHash(ethgo.Keccak256(leaf.MarshalTo(nil))), root)
+
+	root, err = DeriveReceiptsRoot(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, EmptyRoot, root)
+}
+
+func TestVerifyRoots(t *testing.T) {
+	to := ethgo.Address{0x1}
+
+	block := &ethgo.Block{}
+	receipts := []*ethgo.Receipt{}
+	for i := 0; i < 130; i++ {
+		txn := &ethgo.Transaction{
+			Type:                 ethgo.TransactionType(i % 5),
+			ChainID:              big.NewInt(1),
+			Nonce:                uint64(i),
+			GasPrice:             1,
+			MaxFeePerGas:         big.NewInt(1),
+			MaxPriorityFeePerGas: big.NewInt(1),
+			MaxFeePerBlobGas:     big.NewInt(1),
+			Gas:                  21000,
+			To:                   &to,
+			Value:                big.NewInt(int64(i)),
+			V:                    []byte{0x1},
+			R:                    []byte{0x1},
+			S:                    []byte{0x1},
+		}
+		if txn.Type == ethgo.TransactionBlob {
+			txn.BlobVersionedHashes = []ethgo.Hash{{0x1}}
+			txn.Sidecar = &ethgo.BlobSidecar{Blobs: make([]ethgo.Blob, 1), Commitments: make([]ethgo.KZGCommitment, 1), Proofs: make([]ethgo.KZGProof, 1)}
+		}
+		block.Transactions = append(block.Transactions, txn)
+
+		receipts = append(receipts, &ethgo.Receipt{
+			Type:              txn.Type,
+			Status:            uint64(i % 2),
+			CumulativeGasUsed: uint64(i+1) * 21000,
+			Logs: []*ethgo.Log{
+				{Address: to, Topics: []ethgo.Hash{{byte(i)}}, Data: []byte{byte(i)}},
+			},
+		})
+	}
+
+	var err error
+	block.TransactionsRoot, err = DeriveTransactionsRoot(block.Transactions)
+	assert.NoError(t, err)
+	block.ReceiptsRoot, err = DeriveReceiptsRoot(receipts)
+	assert.NoError(t, err)
+
+	assert.NoError(t, VerifyTransactionsRoot(block))
+	assert.NoError(t, VerifyReceiptsRoot(block, receipts))
+
+	// the sidecar is not part of the transactions root
+	for _, txn := range block.Transactions {
+		txn.Sidecar = nil
+	}
+	assert.NoError(t, VerifyTransactionsRoot(block))
+
+	// a different order does not match
+	receipts[0], receipts[1] = receipts[1], receipts[0]
+
+	var mismatch *RootMismatchError
+	assert.True(t, errors.As(VerifyReceiptsRoot(block, receipts), &mismatch))
+	assert.Equal(t, "receipts root", mismatch.Field)
+	assert.Equal(t, block.ReceiptsRoot, mismatch.Claimed)
+
+	block.Transactions[0].Nonce++
+	assert.True(t, errors.As(VerifyTransactionsRoot(block), &mismatch))
+	assert.Equal(t, "transactions root", mismatch.Field)
+
+	// the block only has the hashes of the transactions
+	block.TransactionsHashes = []ethgo.Hash{{0x1}}
+	block.Transactions = nil
+	assert.Error(t, VerifyTransactionsRoot(block))
+}
@@ -0,0 +1,122 @@
+package trie
+
+import (
+	"fmt"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// ProofError is returned when a merkle proof is not valid
+type ProofError struct {
+	// Index is the position of the invalid node in the proof
+	// or -1 if the node is not in the proof
+	Index  int
+	Reason string
+}
+
+func (p *ProofError) Error() string {
+	if p.Index < 0 {
+		return fmt.Sprintf("invalid proof: %s", p.Reason)
+	}
+	return fmt.Sprintf("invalid proof node %d: %s", p.Index, p.Reason)
+}
+
+// VerifyProof verifies the merkle proof of the key in the trie with the given root
+// and returns the value of the key. The value is nil if the proof shows that the key
+// is not in the trie.
+func VerifyProof(root ethgo.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	nodes := make(map[ethgo.Hash]int, len(proof))
+	for indx, raw := range proof {
+		nodes[ethgo.BytesToHash(ethgo.Keccak256(raw))] = indx
+	}
+
+	var p fastrlp.Parser
+	path := keybytesToHex(key)
+	hash := root
+
+	for {
+		indx, ok := nodes[hash]
+		if !ok {
+			return nil, &ProofError{Index: -1, Reason: fmt.Sprintf("node %s not found", hash)}
+		}
+		v, err := p.Parse(proof[indx])
+		if err != nil {
+			return nil, &ProofError{Index: indx, Reason: err.Error()}
+		}
+
+		// follow the path in the node and in its embedded children
+		// until it finds the value or a reference to the next node
+		for {
+			child, rest, err := resolveNode(v, path)
+			if err != nil {
+				return nil, &ProofError{Index: indx, Reason: err.Error()}
+			}
+			if child == nil {
+				// the key diverges from the path in the trie
+				return nil, nil
+			}
+			path = rest
+
+			if len(path) == 0 {
+				val, err := child.Bytes()
+				if err != nil {
+					return nil, &ProofError{Index: indx, Reason: "value is not bytes"}
+				}
+				if len(val) == 0 {
+					return nil, nil
+				}
+				return append([]byte{}, val...), nil
+			}
+
+			if child.Type() == fastrlp.TypeArray {
+				// embedded node
+				v = child
+				continue
+			}
+			ref, err := child.Bytes()
+			if err == nil && len(ref) == 0 {
+				// empty branch
+				return nil, nil
+			}
+			if err != nil || len(ref) != 32 {
+				return nil, &ProofError{Index: indx, Reason: "invalid child reference"}
+			}
+			copy(hash[:], ref)
+			break
+		}
+	}
+}
+
+// resolveNode returns the child of the node in the path and the rest of the path.
+// It returns a nil child if the path is not in the node.
+func resolveNode(v *fastrlp.Value, path []byte) (*fastrlp.Value, []byte, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, nil, fmt.Errorf("node is not a list")
+	}
+
+	switch len(elems) {
+	case 17:
+		// branch node
+		return elems[path[0]], path[1:], nil
+
+	case 2:
+		// leaf or extension node
+		compact, err := elems[0].Bytes()
+		if err != nil {
+			return nil, nil, fmt.Errorf("node key is not bytes")
+		}
+		key, ok := compactToHex(compact)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid node key %x", compact)
+		}
+		if len(path) < len(key) || string(path[:len(key)]) != string(key) {
+			return nil, nil, nil
+		}
+		return elems[1], path[len(key):], nil
+
+	default:
+		return nil, nil, fmt.Errorf("invalid node with %d elements", len(elems))
+	}
+}
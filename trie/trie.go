@@ -0,0 +1,238 @@
+package trie
+
+import (
+	"bytes"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// EmptyRoot is the root of a trie without entries
+var EmptyRoot = ethgo.HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+// terminator is the nibble appended to the keys of the leafs
+const terminator = 16
+
+type node interface{}
+
+type (
+	// shortNode is either a leaf (the key ends with the terminator) or an extension
+	shortNode struct {
+		key []byte
+		val node
+	}
+
+	// fullNode is a branch with a child for each nibble and the value in the last position
+	fullNode struct {
+		children [17]node
+	}
+
+	valueNode []byte
+)
+
+// Trie is an in memory Merkle-Patricia trie. It computes the root of a set of
+// key values (i.e. the transactions of a block) and the proofs of its keys.
+type Trie struct {
+	root node
+}
+
+// New creates an empty trie
+func New() *Trie {
+	return &Trie{}
+}
+
+// Put sets the value of the key. Empty values are not allowed since they
+// cannot be distinguished from a missing key.
+func (t *Trie) Put(key, value []byte) {
+	if len(value) == 0 {
+		panic("trie: empty value")
+	}
+	t.root = insert(t.root, keybytesToHex(key), valueNode(append([]byte{}, value...)))
+}
+
+// Get returns the value of the key
+func (t *Trie) Get(key []byte) ([]byte, bool) {
+	path := keybytesToHex(key)
+	n := t.root
+	for {
+		switch nn := n.(type) {
+		case nil:
+			return nil, false
+		case valueNode:
+			return []byte(nn), true
+		case *shortNode:
+			if !bytes.HasPrefix(path, nn.key) {
+				return nil, false
+			}
+			path, n = path[len(nn.key):], nn.val
+		case *fullNode:
+			path, n = path[1:], nn.children[path[0]]
+		}
+	}
+}
+
+// Hash returns the root of the trie
+func (t *Trie) Hash() ethgo.Hash {
+	if t.root == nil {
+		return EmptyRoot
+	}
+	a := &fastrlp.Arena{}
+	return ethgo.BytesToHash(ethgo.Keccak256(encodeNode(a, t.root).MarshalTo(nil)))
+}
+
+// Prove returns the proof of the key: the rlp encoded nodes in the path
+// from the root to the key. The proof of a missing key shows where the path diverges.
+func (t *Trie) Prove(key []byte) [][]byte {
+	a := &fastrlp.Arena{}
+	path := keybytesToHex(key)
+
+	proof := [][]byte{}
+	n := t.root
+	for {
+		if _, ok := n.(valueNode); ok || n == nil {
+			return proof
+		}
+		// the nodes smaller than a hash are embedded in its parent.
+		// The root is always included.
+		raw := encodeNode(a, n).MarshalTo(nil)
+		if len(proof) == 0 || len(raw) >= 32 {
+			proof = append(proof, raw)
+		}
+
+		switch nn := n.(type) {
+		case *shortNode:
+			if !bytes.HasPrefix(path, nn.key) {
+				return proof
+			}
+			path, n = path[len(nn.key):], nn.val
+		case *fullNode:
+			path, n = path[1:], nn.children[path[0]]
+		}
+	}
+}
+
+func insert(n node, key []byte, value valueNode) node {
+	if len(key) == 0 {
+		return value
+	}
+	switch n := n.(type) {
+	case nil:
+		return &shortNode{key: key, val: value}
+
+	case *shortNode:
+		match := prefixLen(key, n.key)
+		if match == len(n.key) {
+			return &shortNode{key: n.key, val: insert(n.val, key[match:], value)}
+		}
+		// split the node in a branch at the first different nibble
+		branch := &fullNode{}
+		if len(n.key) == match+1 {
+			branch.children[n.key[match]] = n.val
+		} else {
+			branch.children[n.key[match]] = &shortNode{key: n.key[match+1:], val: n.val}
+		}
+		branch.children[key[match]] = insert(nil, key[match+1:], value)
+		if match == 0 {
+			return branch
+		}
+		return &shortNode{key: key[:match], val: branch}
+
+	case *fullNode:
+		nn := &fullNode{children: n.children}
+		nn.children[key[0]] = insert(n.children[key[0]], key[1:], value)
+		return nn
+
+	default:
+		panic("trie: invalid node")
+	}
+}
+
+// encodeNode returns the rlp value of a short or full node
+func encodeNode(a *fastrlp.Arena, n node) *fastrlp.Value {
+	v := a.NewArray()
+	switch n := n.(type) {
+	case *shortNode:
+		v.Set(a.NewBytes(hexToCompact(n.key)))
+		v.Set(encodeRef(a, n.val))
+	case *fullNode:
+		for _, child := range n.children {
+			v.Set(encodeRef(a, child))
+		}
+	}
+	return v
+}
+
+// encodeRef returns the value of a child in its parent node. The child is embedded
+// if its encoding is smaller than a hash, otherwise, it is referenced by its hash.
+func encodeRef(a *fastrlp.Arena, n node) *fastrlp.Value {
+	switch n := n.(type) {
+	case nil:
+		return a.NewNull()
+	case valueNode:
+		return a.NewBytes(n)
+	}
+	v := encodeNode(a, n)
+	raw := v.MarshalTo(nil)
+	if len(raw) < 32 {
+		return v
+	}
+	return a.NewCopyBytes(ethgo.Keccak256(raw))
+}
+
+// keybytesToHex returns the nibbles of the key followed by the terminator
+func keybytesToHex(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2+1)
+	for i, b := range key {
+		nibbles[i*2] = b / 16
+		nibbles[i*2+1] = b % 16
+	}
+	nibbles[len(nibbles)-1] = terminator
+	return nibbles
+}
+
+// hexToCompact encodes the nibbles with the hex prefix encoding. The first nibble
+// of the prefix has the leaf flag (second bit) and the odd length flag (first bit).
+func hexToCompact(hex []byte) []byte {
+	var flag byte
+	if len(hex) > 0 && hex[len(hex)-1] == terminator {
+		flag = 2
+		hex = hex[:len(hex)-1]
+	}
+	buf := make([]byte, len(hex)/2+1)
+	if len(hex)%2 == 1 {
+		flag |= 1
+		buf[0] = hex[0]
+		hex = hex[1:]
+	}
+	buf[0] |= flag << 4
+	for i := 0; i < len(hex); i += 2 {
+		buf[i/2+1] = hex[i]<<4 | hex[i+1]
+	}
+	return buf
+}
+
+// compactToHex decodes a hex prefix encoded key
+func compactToHex(compact []byte) ([]byte, bool) {
+	if len(compact) == 0 {
+		return nil, false
+	}
+	flag := compact[0] >> 4
+	if flag > 3 || (flag&1 == 0 && compact[0]&0xf != 0) {
+		return nil, false
+	}
+	hex := keybytesToHex(compact)
+	if flag&2 == 0 {
+		// extension nodes do not have terminator
+		hex = hex[:len(hex)-1]
+	}
+	// remove the flag nibble and the padding nibble if the length is even
+	return hex[2-flag&1:], true
+}
+
+func prefixLen(a, b []byte) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,106 @@
+package trie
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/ethgo"
+)
+
+func TestTrie_Hash(t *testing.T) {
+	assert.Equal(t, EmptyRoot, New().Hash())
+
+	tr := New()
+	tr.Put([]byte("doe"), []byte("reindeer"))
+	tr.Put([]byte("dog"), []byte("puppy"))
+	tr.Put([]byte("dogglesworth"), []byte("cat"))
+	assert.Equal(t, "0x8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3", tr.Hash().String())
+
+	// the root node is hashed even if it is smaller than a hash
+	tr = New()
+	tr.Put([]byte("A"), []byte("b"))
+	assert.Equal(t, ethgo.BytesToHash(ethgo.Keccak256([]byte{0xc4, 0x82, 0x20, 0x41, 0x62})), tr.Hash())
+
+	// the root does not depend on the insertion order
+	tr2 := New()
+	tr2.Put([]byte("dogglesworth"), []byte("cat"))
+	tr2.Put([]byte("dog"), []byte("puppy"))
+	tr2.Put([]byte("doe"), []byte("reindeer"))
+	tr2.Put([]byte("dog"), []byte("puppy"))
+	val, ok := tr2.Get([]byte("dog"))
+	assert.True(t, ok)
+	assert.Equal(t, []byte("puppy"), val)
+	_, ok = tr2.Get([]byte("do"))
+	assert.False(t, ok)
+	assert.Equal(t, "0x8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3", tr2.Hash().String())
+}
+
+func TestTrie_Proof(t *testing.T) {
+	tr := New()
+	keys := [][]byte{}
+	for i := 0; i < 200; i++ {
+		key := make([]byte, 1+i%40)
+		rand.Read(key)
+		keys = append(keys, key)
+		tr.Put(key, []byte(fmt.Sprintf("value-%d", i)))
+	}
+	root := tr.Hash()
+
+	for i, key := range keys {
+		val, err := VerifyProof(root, key, tr.Prove(key))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(fmt.Sprintf("value-%d", i)), val)
+	}
+
+	// proof of absence
+	missing := []byte("missing key")
+	val, err := VerifyProof(root, missing, tr.Prove(missing))
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+
+	// the proof is not valid for another root
+	_, err = VerifyProof(ethgo.Hash{0x1}, keys[0], tr.Prove(keys[0]))
+	assert.Error(t, err)
+
+	// tampered node
+	proof := tr.Prove(keys[0])
+	proof[len(proof)-1] = append([]byte{}, proof[len(proof)-1]...)
+	proof[len(proof)-1][len(proof[len(proof)-1])-1] ^= 0x1
+
+	_, err = VerifyProof(root, keys[0], proof)
+	var proofErr *ProofError
+	assert.True(t, errors.As(err, &proofErr))
+
+	// missing node
+	proof = tr.Prove(keys[0])
+	_, err = VerifyProof(root, keys[0], proof[:len(proof)-1])
+	assert.True(t, errors.As(err, &proofErr))
+	assert.Equal(t, -1, proofErr.Index)
+}
+
+func TestTrie_Compact(t *testing.T) {
+	cases := []struct {
+		hex     []byte
+		compact []byte
+	}{
+		{[]byte{}, []byte{0x00}},
+		{[]byte{terminator}, []byte{0x20}},
+		{[]byte{1, 2, 3, 4, 5}, []byte{0x11, 0x23, 0x45}},
+		{[]byte{0, 1, 2, 3, 4, 5}, []byte{0x00, 0x01, 0x23, 0x45}},
+		{[]byte{15, 1, 12, 11, 8, terminator}, []byte{0x3f, 0x1c, 0xb8}},
+		{[]byte{0, 15, 1, 12, 11, 8, terminator}, []byte{0x20, 0x0f, 0x1c, 0xb8}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.compact, hexToCompact(c.hex))
+
+		hex, ok := compactToHex(c.compact)
+		assert.True(t, ok)
+		assert.Equal(t, c.hex, hex)
+	}
+
+	_, ok := compactToHex([]byte{0x4f})
+	assert.False(t, ok)
+}
@@ -1,6 +1,11 @@
 package ethgo
 
-import "math/big"
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+)
 
 func convert(val uint64, decimals int64) *big.Int {
 	v := big.NewInt(int64(val))
@@ -17,3 +22,287 @@ func Ether(i uint64) *big.Int {
 func Gwei(i uint64) *big.Int {
 	return convert(i, 9)
 }
+
+const (
+	// GweiDecimals is the number of decimals of gwei
+	GweiDecimals = 9
+
+	// EtherDecimals is the number of decimals of ether
+	EtherDecimals = 18
+)
+
+// etherUnits are the decimals of the denominations of ether
+var etherUnits = map[string]uint8{
+	"wei":      0,
+	"kwei":     3,
+	"babbage":  3,
+	"mwei":     6,
+	"lovelace": 6,
+	"gwei":     9,
+	"shannon":  9,
+	"szabo":    12,
+	"finney":   15,
+	"ether":    18,
+}
+
+// ParseUnits parses a decimal amount (i.e. "12.345678") with the given number
+// of decimals to its integer value (i.e. 12345678 with 6 decimals). It fails if
+// the amount has more (non zero) decimals than the given number of decimals.
+func ParseUnits(s string, decimals uint8) (*big.Int, error) {
+	str := strings.TrimSpace(s)
+
+	neg := strings.HasPrefix(str, "-")
+	if neg {
+		str = str[1:]
+	}
+	integer, fraction := str, ""
+	if indx := strings.Index(str, "."); indx != -1 {
+		integer, fraction = str[:indx], str[indx+1:]
+		if fraction == "" {
+			return nil, fmt.Errorf("invalid amount '%s'", s)
+		}
+	}
+	if integer == "" || !isDigits(integer) || !isDigits(fraction) {
+		return nil, fmt.Errorf("invalid amount '%s'", s)
+	}
+
+	if len(fraction) > int(decimals) {
+		if strings.Trim(fraction[decimals:], "0") != "" {
+			return nil, fmt.Errorf("amount '%s' has more than %d decimals", s, decimals)
+		}
+		fraction = fraction[:decimals]
+	}
+	fraction += strings.Repeat("0", int(decimals)-len(fraction))
+
+	v, ok := new(big.Int).SetString(integer+fraction, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount '%s'", s)
+	}
+	if neg {
+		v.Neg(v)
+	}
+	return v, nil
+}
+
+// ParseEther parses an amount of ether with an optional unit (i.e. "1.5 gwei",
+// "0.001ether" or "100 wei") to wei. An amount without unit is in ether.
+func ParseEther(s string) (*big.Int, error) {
+	str := strings.TrimSpace(s)
+
+	decimals := uint8(EtherDecimals)
+	if indx := strings.IndexFunc(str, isLetter); indx != -1 {
+		unit, ok := etherUnits[strings.ToLower(strings.TrimSpace(str[indx:]))]
+		if !ok {
+			return nil, fmt.Errorf("unknown unit in amount '%s'", s)
+		}
+		decimals = unit
+		str = strings.TrimSpace(str[:indx])
+	}
+	v, err := ParseUnits(str, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amount '%s': %v", s, err)
+	}
+	return v, nil
+}
+
+// FormatUnits formats an integer value with the given number of decimals
+// (i.e. 1500000 with 6 decimals is "1.5"). It does not round the value.
+func FormatUnits(v *big.Int, decimals uint8) string {
+	integer, fraction, neg := splitUnits(v, decimals)
+
+	fraction = strings.TrimRight(fraction, "0")
+	return joinUnits(integer, fraction, neg)
+}
+
+// FormatEther formats an amount of wei in ether
+func FormatEther(v *big.Int) string {
+	return FormatUnits(v, EtherDecimals)
+}
+
+// RoundingMode is the rounding used to format an amount with less decimals
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value and away from zero if it is halfway
+	RoundHalfUp RoundingMode = iota
+
+	// RoundHalfEven rounds to the nearest value and to the even value if it is halfway
+	RoundHalfEven
+
+	// RoundDown rounds toward zero (truncates the value)
+	RoundDown
+
+	// RoundUp rounds away from zero
+	RoundUp
+)
+
+// FormatUnitsRound formats an integer value with the given number of decimals rounded
+// to precision decimals (i.e. 1234567 with 6 decimals and precision 2 is "1.23").
+// Unlike FormatUnits, the result always has precision decimals.
+func FormatUnitsRound(v *big.Int, decimals uint8, precision uint8, mode RoundingMode) string {
+	if v == nil {
+		v = new(big.Int)
+	}
+	if precision >= decimals {
+		integer, fraction, neg := splitUnits(v, decimals)
+		return joinUnits(integer, fraction+strings.Repeat("0", int(precision-decimals)), neg)
+	}
+
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-precision)), nil)
+
+	q, r := new(big.Int).QuoRem(new(big.Int).Abs(v), factor, new(big.Int))
+	if r.Sign() != 0 {
+		// compare the remainder with half the factor
+		half := new(big.Int).Lsh(r, 1).Cmp(factor)
+
+		var inc bool
+		switch mode {
+		case RoundUp:
+			inc = true
+		case RoundHalfUp:
+			inc = half >= 0
+		case RoundHalfEven:
+			inc = half > 0 || (half == 0 && q.Bit(0) == 1)
+		}
+		if inc {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+
+	integer, fraction, neg := splitUnits(q, precision)
+	return joinUnits(integer, fraction, neg)
+}
+
+// splitUnits returns the integer and the fraction (with all the decimals) of the value
+func splitUnits(v *big.Int, decimals uint8) (string, string, bool) {
+	if v == nil {
+		v = new(big.Int)
+	}
+	str := new(big.Int).Abs(v).String()
+	if len(str) <= int(decimals) {
+		str = strings.Repeat("0", int(decimals)-len(str)+1) + str
+	}
+	indx := len(str) - int(decimals)
+	return str[:indx], str[indx:], v.Sign() < 0
+}
+
+func joinUnits(integer, fraction string, neg bool) string {
+	str := integer
+	if fraction != "" {
+		str += "." + fraction
+	}
+	if neg {
+		str = "-" + str
+	}
+	return str
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetter(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Amount is an integer value (i.e. wei or the smallest unit of a token) with its
+// number of decimals. It is encoded in text and json as a decimal string (i.e. "1.5"
+// is 1.5 * 10^decimals). The decimals must be set before it is decoded and units
+// are not accepted, even with 18 decimals since it may not be ether. Use
+// EtherAmount to decode an amount of ether with a unit (i.e. "1.5 gwei").
+type Amount struct {
+	Value    *big.Int
+	Decimals uint8
+}
+
+// NewAmount creates an amount with the given number of decimals
+func NewAmount(v *big.Int, decimals uint8) *Amount {
+	return &Amount{Value: v, Decimals: decimals}
+}
+
+// String implements the stringer interface
+func (a Amount) String() string {
+	return FormatUnits(a.Value, a.Decimals)
+}
+
+// MarshalText implements the marshal interface
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements the unmarshal interface
+func (a *Amount) UnmarshalText(input []byte) error {
+	v, err := ParseUnits(string(input), a.Decimals)
+	if err != nil {
+		return err
+	}
+	a.Value = v
+	return nil
+}
+
+// MarshalJSON implements the json marshal interface
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + a.String() + "\""), nil
+}
+
+// UnmarshalJSON implements the json unmarshal interface. It
+// accepts the amount either as a string or as a number.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return a.UnmarshalText(bytes.Trim(data, "\""))
+}
+
+// EtherAmount is an amount of wei. It is encoded in text and json in ether
+// (i.e. "1.5") and it is decoded with an optional unit (i.e. "1.5 gwei").
+type EtherAmount struct {
+	Value *big.Int
+}
+
+// NewEtherAmount creates an amount of wei
+func NewEtherAmount(v *big.Int) *EtherAmount {
+	return &EtherAmount{Value: v}
+}
+
+// String implements the stringer interface
+func (e EtherAmount) String() string {
+	return FormatEther(e.Value)
+}
+
+// MarshalText implements the marshal interface
+func (e EtherAmount) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements the unmarshal interface
+func (e *EtherAmount) UnmarshalText(input []byte) error {
+	v, err := ParseEther(string(input))
+	if err != nil {
+		return err
+	}
+	e.Value = v
+	return nil
+}
+
+// MarshalJSON implements the json marshal interface
+func (e EtherAmount) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + e.String() + "\""), nil
+}
+
+// UnmarshalJSON implements the json unmarshal interface. It
+// accepts the amount either as a string or as a number.
+func (e *EtherAmount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return e.UnmarshalText(bytes.Trim(data, "\""))
+}
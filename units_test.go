@@ -0,0 +1,175 @@
+package ethgo
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnits_Parse(t *testing.T) {
+	cases := []struct {
+		input    string
+		decimals uint8
+		output   string
+	}{
+		{"12.345678", 6, "12345678"},
+		{"1", 6, "1000000"},
+		{"0.000001", 6, "1"},
+		{"1.50", 1, "15"},
+		{"-1.5", 2, "-150"},
+		{"100", 0, "100"},
+		{" 2.5 ", 18, "2500000000000000000"},
+	}
+	for _, c := range cases {
+		v, err := ParseUnits(c.input, c.decimals)
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.output, v.String(), c.input)
+	}
+
+	invalid := []struct {
+		input    string
+		decimals uint8
+	}{
+		{"1.0000001", 6}, // excess precision
+		{"1.5", 0},
+		{"", 6},
+		{"1.", 6},
+		{".5", 6},
+		{"1.2.3", 6},
+		{"1e18", 18},
+		{"0x10", 18},
+		{"1,5", 6},
+	}
+	for _, c := range invalid {
+		_, err := ParseUnits(c.input, c.decimals)
+		assert.Error(t, err, c.input)
+	}
+}
+
+func TestUnits_ParseEther(t *testing.T) {
+	cases := map[string]string{
+		"1.5 gwei":    "1500000000",
+		"0.001ether":  "1000000000000000",
+		"1.25 ether":  "1250000000000000000",
+		"1.25":        "1250000000000000000",
+		"100 wei":     "100",
+		"1 Gwei":      "1000000000",
+		"2 finney":    "2000000000000000",
+		"0.5 szabo":   "500000000000",
+		"3 kwei":      "3000",
+		"1.000000001": "1000000001000000000",
+	}
+	for input, output := range cases {
+		v, err := ParseEther(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, output, v.String(), input)
+	}
+
+	for _, input := range []string{"1.5 wei", "1 eth", "gwei", "1 gwei wei"} {
+		_, err := ParseEther(input)
+		assert.Error(t, err, input)
+	}
+
+	// same as the integer helpers
+	v, err := ParseEther("3 gwei")
+	assert.NoError(t, err)
+	assert.Equal(t, Gwei(3), v)
+}
+
+func TestUnits_Format(t *testing.T) {
+	assert.Equal(t, "1.5", FormatUnits(big.NewInt(1500000), 6))
+	assert.Equal(t, "0.000001", FormatUnits(big.NewInt(1), 6))
+	assert.Equal(t, "1", FormatUnits(big.NewInt(1000000), 6))
+	assert.Equal(t, "-0.5", FormatUnits(big.NewInt(-500000), 6))
+	assert.Equal(t, "100", FormatUnits(big.NewInt(100), 0))
+	assert.Equal(t, "0", FormatUnits(nil, 18))
+	assert.Equal(t, "1.25", FormatEther(big.NewInt(1250000000000000000)))
+
+	cases := []struct {
+		value     int64
+		precision uint8
+		mode      RoundingMode
+		output    string
+	}{
+		{1234567, 2, RoundHalfUp, "1.23"},
+		{1235000, 2, RoundHalfUp, "1.24"},
+		{1225000, 2, RoundHalfUp, "1.23"},
+		{1225000, 2, RoundHalfEven, "1.22"},
+		{1235000, 2, RoundHalfEven, "1.24"},
+		{1225001, 2, RoundHalfEven, "1.23"},
+		{1239999, 2, RoundDown, "1.23"},
+		{1230001, 2, RoundUp, "1.24"},
+		{1230000, 2, RoundUp, "1.23"},
+		{-1235000, 2, RoundHalfUp, "-1.24"},
+		{-1239999, 2, RoundDown, "-1.23"},
+		{1500000, 0, RoundHalfUp, "2"},
+		{1500000, 8, RoundHalfUp, "1.50000000"},
+		{1, 2, RoundDown, "0.00"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.output, FormatUnitsRound(big.NewInt(c.value), 6, c.precision, c.mode), "%d %d", c.value, c.precision)
+	}
+}
+
+func TestAmount_Encoding(t *testing.T) {
+	type config struct {
+		Fee   *Amount `json:"fee"`
+		Token Amount  `json:"token"`
+	}
+
+	// the decimals are set before the amounts are decoded
+	c := &config{
+		Fee:   NewAmount(nil, EtherDecimals),
+		Token: Amount{Decimals: 6},
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"fee": "0.0000000015", "token": 12.345678}`), c))
+	assert.Equal(t, "1500000000", c.Fee.Value.String())
+	assert.Equal(t, "12345678", c.Token.Value.String())
+
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"fee":"0.0000000015","token":"12.345678"}`, string(data))
+
+	// the units are not accepted even with the decimals of ether
+	assert.Error(t, json.Unmarshal([]byte(`{"fee": "1.5 gwei"}`), c))
+
+	// excess precision
+	assert.Error(t, json.Unmarshal([]byte(`{"token": "1.0000001"}`), c))
+
+	// text encoding
+	a := NewAmount(nil, 2)
+	assert.NoError(t, a.UnmarshalText([]byte("3.14")))
+	assert.Equal(t, "314", a.Value.String())
+	assert.Equal(t, "3.14", a.String())
+
+	// the ether units are only valid for EtherAmount
+	assert.Error(t, a.UnmarshalText([]byte("1 gwei")))
+}
+
+func TestEtherAmount_Encoding(t *testing.T) {
+	type config struct {
+		Fee   EtherAmount  `json:"fee"`
+		Limit *EtherAmount `json:"limit"`
+		Tip   EtherAmount  `json:"tip"`
+	}
+
+	// the zero value decodes ether with and without units
+	c := &config{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"fee": "1.5", "limit": "1.5 ether", "tip": 2}`), c))
+	assert.Equal(t, "1500000000000000000", c.Fee.Value.String())
+	assert.Equal(t, "1500000000000000000", c.Limit.Value.String())
+	assert.Equal(t, "2000000000000000000", c.Tip.Value.String())
+
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"fee":"1.5","limit":"1.5","tip":"2"}`, string(data))
+
+	var e EtherAmount
+	assert.NoError(t, e.UnmarshalText([]byte("3 gwei")))
+	assert.Equal(t, "3000000000", e.Value.String())
+	assert.Equal(t, "0.000000003", NewEtherAmount(e.Value).String())
+
+	assert.Error(t, e.UnmarshalText([]byte("1 foo")))
+}
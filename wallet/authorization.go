@@ -0,0 +1,50 @@
+package wallet
+
+import (
+	"math/big"
+
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// authorizationMagic is the prefix of the hash signed by an authorization (eip-7702)
+const authorizationMagic = 0x05
+
+// SignAuthorization signs the authorization to set the code of the address
+// as the code of the key account. The chain id, address and nonce must be set.
+func SignAuthorization(auth *ethgo.Authorization, key ethgo.Key) (*ethgo.Authorization, error) {
+	sig, err := key.Sign(authorizationHash(auth))
+	if err != nil {
+		return nil, err
+	}
+
+	auth.YParity = uint64(sig[64])
+	auth.R = trimBytesZeros(sig[:32])
+	auth.S = trimBytesZeros(sig[32:64])
+	return auth, nil
+}
+
+// RecoverAuthority returns the account that signed the authorization. The y parity
+// must be 0 or 1 and the s value in the lower half of the curve order.
+func RecoverAuthority(auth *ethgo.Authorization) (ethgo.Address, error) {
+	return recoverPlain(authorizationHash(auth), auth.R, auth.S, auth.YParity, true)
+}
+
+func authorizationHash(auth *ethgo.Authorization) []byte {
+	a := fastrlp.DefaultArenaPool.Get()
+
+	chainID := auth.ChainID
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+
+	v := a.NewArray()
+	v.Set(a.NewBigInt(chainID))
+	v.Set(a.NewCopyBytes(auth.Address[:]))
+	v.Set(a.NewUint(auth.Nonce))
+
+	dst := v.MarshalTo([]byte{authorizationMagic})
+	fastrlp.DefaultArenaPool.Put(a)
+
+	return ethgo.Keccak256(dst)
+}
@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/umbracle/ethgo"
@@ -15,25 +16,276 @@ type Signer interface {
 	SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error)
 }
 
-type EIP1155Signer struct {
+var (
+	// ErrTxTypeNotSupported is returned if the signer does not support the type of the transaction
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+
+	// ErrInvalidChainID is returned if the chain id of the transaction is not the one of the signer
+	ErrInvalidChainID = errors.New("invalid chain id for signer")
+
+	// ErrInvalidSig is returned if the v, r and s values of the transaction are not valid
+	ErrInvalidSig = errors.New("invalid transaction v, r, s values")
+)
+
+// LatestSignerForChainID returns the signer that supports all the transaction types
+// known for the chain id. The transactions are signed and recovered depending on their type.
+// If the chain id is zero, only the unprotected legacy transactions are supported.
+func LatestSignerForChainID(chainID uint64) Signer {
+	if chainID == 0 {
+		return NewHomesteadSigner()
+	}
+	return NewPragueSigner(chainID)
+}
+
+// FrontierSigner signs and recovers the legacy transactions without replay protection
+type FrontierSigner struct {
+}
+
+func NewFrontierSigner() *FrontierSigner {
+	return &FrontierSigner{}
+}
+
+func (f *FrontierSigner) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	return recoverUnprotected(tx, false)
+}
+
+func (f *FrontierSigner) SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	return signUnprotected(tx, key)
+}
+
+// HomesteadSigner is the frontier signer that rejects
+// the signatures with a high s value (EIP-2)
+type HomesteadSigner struct {
+}
+
+func NewHomesteadSigner() *HomesteadSigner {
+	return &HomesteadSigner{}
+}
+
+func (h *HomesteadSigner) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	return recoverUnprotected(tx, true)
+}
+
+func (h *HomesteadSigner) SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	return signUnprotected(tx, key)
+}
+
+// EIP155Signer signs the legacy transactions with replay protection (EIP-155).
+// It also recovers the sender of the unprotected legacy transactions.
+type EIP155Signer struct {
 	chainID uint64
 }
 
+// NewEIP155LegacySigner returns the signer of the legacy transactions with
+// replay protection. It does not support the typed transactions.
+func NewEIP155LegacySigner(chainID uint64) *EIP155Signer {
+	return &EIP155Signer{chainID: chainID}
+}
+
+// EIP1155Signer signs and recovers all the transaction types (legacy with EIP-155)
+// like the signer of the latest fork.
+//
+// Deprecated: use LatestSignerForChainID or the signer of a specific fork.
+type EIP1155Signer struct {
+	PragueSigner
+}
+
+// NewEIP155Signer returns the signer of all the transaction types for the chain id.
+// Use NewEIP155LegacySigner for a signer that only supports the legacy transactions.
 func NewEIP155Signer(chainID uint64) *EIP1155Signer {
-	return &EIP1155Signer{chainID: chainID}
+	return &EIP1155Signer{PragueSigner{EIP155Signer{chainID: chainID}}}
+}
+
+func (e *EIP155Signer) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	if tx.Type != ethgo.TransactionLegacy {
+		return ethgo.Address{}, ErrTxTypeNotSupported
+	}
+
+	v := new(big.Int).SetBytes(tx.V)
+	if !v.IsUint64() {
+		return ethgo.Address{}, ErrInvalidSig
+	}
+	vv := v.Uint64()
+	if vv == 27 || vv == 28 {
+		return recoverUnprotected(tx, true)
+	}
+	if vv < 35 {
+		return ethgo.Address{}, ErrInvalidSig
+	}
+	if (vv-35)/2 != e.chainID {
+		return ethgo.Address{}, ErrInvalidChainID
+	}
+	return recoverPlain(signHash(tx, e.chainID), tx.R, tx.S, vv-35-e.chainID*2, true)
+}
+
+func (e *EIP155Signer) SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	if tx.Type != ethgo.TransactionLegacy {
+		return nil, ErrTxTypeNotSupported
+	}
+	return sign(tx, key, e.chainID, 35+e.chainID*2)
+}
+
+// BerlinSigner signs the access list transactions (EIP-2930)
+// and the legacy transactions with the EIP-155 signer
+type BerlinSigner struct {
+	EIP155Signer
+}
+
+func NewBerlinSigner(chainID uint64) *BerlinSigner {
+	return &BerlinSigner{EIP155Signer{chainID: chainID}}
+}
+
+func (b *BerlinSigner) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	return recoverTyped(&b.EIP155Signer, tx, ethgo.TransactionAccessList)
 }
 
-func (e *EIP1155Signer) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
-	v := new(big.Int).SetBytes(tx.V).Uint64()
-	v -= e.chainID * 2
-	v -= 8
-	v -= 27
+func (b *BerlinSigner) SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	return signTyped(&b.EIP155Signer, tx, key, ethgo.TransactionAccessList)
+}
 
-	sig, err := encodeSignature(tx.R, tx.S, byte(v))
+// LondonSigner is the berlin signer that also signs the dynamic fee transactions (EIP-1559)
+type LondonSigner struct {
+	EIP155Signer
+}
+
+func NewLondonSigner(chainID uint64) *LondonSigner {
+	return &LondonSigner{EIP155Signer{chainID: chainID}}
+}
+
+func (l *LondonSigner) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	return recoverTyped(&l.EIP155Signer, tx, ethgo.TransactionDynamicFee)
+}
+
+func (l *LondonSigner) SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	return signTyped(&l.EIP155Signer, tx, key, ethgo.TransactionDynamicFee)
+}
+
+// CancunSigner is the london signer that also signs the blob transactions (EIP-4844)
+type CancunSigner struct {
+	EIP155Signer
+}
+
+func NewCancunSigner(chainID uint64) *CancunSigner {
+	return &CancunSigner{EIP155Signer{chainID: chainID}}
+}
+
+func (c *CancunSigner) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	return recoverTyped(&c.EIP155Signer, tx, ethgo.TransactionBlob)
+}
+
+func (c *CancunSigner) SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	return signTyped(&c.EIP155Signer, tx, key, ethgo.TransactionBlob)
+}
+
+// PragueSigner is the cancun signer that also signs the set code transactions (EIP-7702)
+type PragueSigner struct {
+	EIP155Signer
+}
+
+func NewPragueSigner(chainID uint64) *PragueSigner {
+	return &PragueSigner{EIP155Signer{chainID: chainID}}
+}
+
+func (p *PragueSigner) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	return recoverTyped(&p.EIP155Signer, tx, ethgo.TransactionSetCode)
+}
+
+func (p *PragueSigner) SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	return signTyped(&p.EIP155Signer, tx, key, ethgo.TransactionSetCode)
+}
+
+func recoverUnprotected(tx *ethgo.Transaction, homestead bool) (ethgo.Address, error) {
+	if tx.Type != ethgo.TransactionLegacy {
+		return ethgo.Address{}, ErrTxTypeNotSupported
+	}
+	v := new(big.Int).SetBytes(tx.V)
+	if !v.IsUint64() || (v.Uint64() != 27 && v.Uint64() != 28) {
+		return ethgo.Address{}, ErrInvalidSig
+	}
+	return recoverPlain(signHash(tx, 0), tx.R, tx.S, v.Uint64()-27, homestead)
+}
+
+func signUnprotected(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	if tx.Type != ethgo.TransactionLegacy {
+		return nil, ErrTxTypeNotSupported
+	}
+	return sign(tx, key, 0, 27)
+}
+
+// recoverTyped recovers the sender of the typed transactions up to the max type.
+// The sender of the legacy transactions is recovered with the EIP-155 signer.
+func recoverTyped(e *EIP155Signer, tx *ethgo.Transaction, max ethgo.TransactionType) (ethgo.Address, error) {
+	if tx.Type == ethgo.TransactionLegacy {
+		return e.RecoverSender(tx)
+	}
+	if tx.Type > max {
+		return ethgo.Address{}, ErrTxTypeNotSupported
+	}
+	if tx.ChainID == nil || !tx.ChainID.IsUint64() || tx.ChainID.Uint64() != e.chainID {
+		return ethgo.Address{}, ErrInvalidChainID
+	}
+	// the typed transactions use the y parity (0 or 1) as v
+	v := new(big.Int).SetBytes(tx.V)
+	if v.Cmp(big.NewInt(1)) > 0 {
+		return ethgo.Address{}, ErrInvalidSig
+	}
+	return recoverPlain(signHash(tx, e.chainID), tx.R, tx.S, v.Uint64(), true)
+}
+
+// signTyped signs the typed transactions up to the max type. If the chain id
+// of the transaction is not set, it uses the one of the signer.
+func signTyped(e *EIP155Signer, tx *ethgo.Transaction, key ethgo.Key, max ethgo.TransactionType) (*ethgo.Transaction, error) {
+	if tx.Type == ethgo.TransactionLegacy {
+		return e.SignTx(tx, key)
+	}
+	if tx.Type > max {
+		return nil, ErrTxTypeNotSupported
+	}
+	if tx.ChainID == nil {
+		tx.ChainID = new(big.Int).SetUint64(e.chainID)
+	} else if !tx.ChainID.IsUint64() || tx.ChainID.Uint64() != e.chainID {
+		return nil, ErrInvalidChainID
+	}
+	return sign(tx, key, e.chainID, 0)
+}
+
+// sign signs the transaction and sets the signature values. The
+// recovery id of the signature is added to the base value of v.
+func sign(tx *ethgo.Transaction, key ethgo.Key, chainID uint64, base uint64) (*ethgo.Transaction, error) {
+	hash := signHash(tx, chainID)
+
+	sig, err := key.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.R = trimBytesZeros(sig[:32])
+	tx.S = trimBytesZeros(sig[32:64])
+	tx.V = new(big.Int).SetUint64(base + uint64(sig[64])).Bytes()
+	return tx, nil
+}
+
+var secp256k1halfN = new(big.Int).Rsh(S256.N, 1)
+
+// recoverPlain returns the address that signed the hash. After homestead
+// the signatures with a s value in the upper half of the curve are not valid.
+func recoverPlain(hash []byte, R, S []byte, recID uint64, homestead bool) (ethgo.Address, error) {
+	if recID > 1 || len(R) > 32 || len(S) > 32 {
+		return ethgo.Address{}, ErrInvalidSig
+	}
+	r, s := new(big.Int).SetBytes(R), new(big.Int).SetBytes(S)
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(S256.N) >= 0 || s.Cmp(S256.N) >= 0 {
+		return ethgo.Address{}, ErrInvalidSig
+	}
+	if homestead && s.Cmp(secp256k1halfN) > 0 {
+		return ethgo.Address{}, ErrInvalidSig
+	}
+
+	sig, err := encodeSignature(R, S, byte(recID))
 	if err != nil {
 		return ethgo.Address{}, err
 	}
-	addr, err := Ecrecover(signHash(tx, e.chainID), sig)
+	addr, err := Ecrecover(hash, sig)
 	if err != nil {
 		return ethgo.Address{}, err
 	}
@@ -50,25 +302,6 @@ func trimBytesZeros(b []byte) []byte {
 	return b[i:]
 }
 
-func (e *EIP1155Signer) SignTx(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
-	hash := signHash(tx, e.chainID)
-
-	sig, err := key.Sign(hash)
-	if err != nil {
-		return nil, err
-	}
-
-	vv := uint64(sig[64])
-	if tx.Type == 0 {
-		vv = vv + 35 + e.chainID*2
-	}
-
-	tx.R = trimBytesZeros(sig[:32])
-	tx.S = trimBytesZeros(sig[32:64])
-	tx.V = new(big.Int).SetUint64(vv).Bytes()
-	return tx, nil
-}
-
 func signHash(tx *ethgo.Transaction, chainID uint64) []byte {
 	a := fastrlp.DefaultArenaPool.Get()
 
@@ -81,7 +314,7 @@ func signHash(tx *ethgo.Transaction, chainID uint64) []byte {
 
 	v.Set(a.NewUint(tx.Nonce))
 
-	if tx.Type == ethgo.TransactionDynamicFee {
+	if tx.Type >= ethgo.TransactionDynamicFee {
 		// dynamic fee uses
 		v.Set(a.NewBigInt(tx.MaxPriorityFeePerGas))
 		v.Set(a.NewBigInt(tx.MaxFeePerGas))
@@ -108,6 +341,26 @@ func signHash(tx *ethgo.Transaction, chainID uint64) []byte {
 		v.Set(accessList)
 	}
 
+	if tx.Type == ethgo.TransactionBlob {
+		v.Set(a.NewBigInt(tx.MaxFeePerBlobGas))
+		hashes := a.NewArray()
+		for _, h := range tx.BlobVersionedHashes {
+			hashes.Set(a.NewCopyBytes(h[:]))
+		}
+		v.Set(hashes)
+	}
+	if tx.Type == ethgo.TransactionSetCode {
+		list := a.NewArray()
+		for _, auth := range tx.AuthorizationList {
+			elem, err := auth.MarshalRLPWith(a)
+			if err != nil {
+				panic(err)
+			}
+			list.Set(elem)
+		}
+		v.Set(list)
+	}
+
 	// EIP155
 	if chainID != 0 && tx.Type == 0 {
 		v.Set(a.NewUint(chainID))
@@ -118,10 +371,8 @@ func signHash(tx *ethgo.Transaction, chainID uint64) []byte {
 	dst := v.MarshalTo(nil)
 
 	// append the tx type byte
-	if tx.Type == ethgo.TransactionAccessList {
-		dst = append([]byte{0x1}, dst...)
-	} else if tx.Type == ethgo.TransactionDynamicFee {
-		dst = append([]byte{0x2}, dst...)
+	if tx.Type != ethgo.TransactionLegacy {
+		dst = append([]byte{byte(tx.Type)}, dst...)
 	}
 
 	hash := ethgo.Keccak256(dst)
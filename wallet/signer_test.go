@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"bytes"
+	"encoding/hex"
 	"math/big"
 	"testing"
 
@@ -36,6 +38,280 @@ func TestSigner_EIP1155(t *testing.T) {
 	*/
 }
 
+func TestSigner_TypedTransactions(t *testing.T) {
+	signer := NewEIP155Signer(1337)
+
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	addr0 := ethgo.Address{0x1}
+	for _, typ := range []ethgo.TransactionType{ethgo.TransactionBlob, ethgo.TransactionSetCode} {
+		txn := &ethgo.Transaction{
+			Type:                 typ,
+			ChainID:              big.NewInt(1337),
+			To:                   &addr0,
+			Value:                big.NewInt(10),
+			MaxFeePerGas:         big.NewInt(10),
+			MaxPriorityFeePerGas: big.NewInt(1),
+		}
+		if typ == ethgo.TransactionBlob {
+			txn.MaxFeePerBlobGas = big.NewInt(1)
+			txn.BlobVersionedHashes = []ethgo.Hash{{0x1}}
+		} else {
+			txn.AuthorizationList = []*ethgo.Authorization{{ChainID: big.NewInt(1337), Address: addr0}}
+		}
+
+		txn, err = signer.SignTx(txn, key)
+		assert.NoError(t, err)
+
+		from, err := signer.RecoverSender(txn)
+		assert.NoError(t, err)
+		assert.Equal(t, key.addr, from)
+
+		// the signature is valid after the encoding
+		raw, err := txn.MarshalRLPTo(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, byte(typ), raw[0])
+
+		txn2 := new(ethgo.Transaction)
+		assert.NoError(t, txn2.UnmarshalRLP(raw))
+
+		from, err = signer.RecoverSender(txn2)
+		assert.NoError(t, err)
+		assert.Equal(t, key.addr, from)
+	}
+}
+
+func TestSigner_EIP155Vector(t *testing.T) {
+	// example of eip-155
+	key, err := NewWalletFromPrivKey(bytes.Repeat([]byte{0x46}, 32))
+	assert.NoError(t, err)
+
+	to := ethgo.HexToAddress("0x3535353535353535353535353535353535353535")
+	txn := &ethgo.Transaction{
+		Nonce:    9,
+		GasPrice: 20000000000,
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1000000000000000000),
+	}
+
+	signer := NewEIP155Signer(1)
+	txn, err = signer.SignTx(txn, key)
+	assert.NoError(t, err)
+
+	raw, err := txn.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83", "0x"+hex.EncodeToString(raw))
+
+	from, err := signer.RecoverSender(txn)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address(), from)
+
+	// the signature is not valid on another chain
+	_, err = NewEIP155LegacySigner(2).RecoverSender(txn)
+	assert.Equal(t, ErrInvalidChainID, err)
+}
+
+func TestSigner_Forks(t *testing.T) {
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	signers := []struct {
+		name    string
+		signer  Signer
+		maxType ethgo.TransactionType
+		legacy  bool // supports only the unprotected legacy transactions
+	}{
+		{"frontier", NewFrontierSigner(), ethgo.TransactionLegacy, true},
+		{"homestead", NewHomesteadSigner(), ethgo.TransactionLegacy, true},
+		{"eip155", NewEIP155LegacySigner(1), ethgo.TransactionLegacy, false},
+		{"eip1155 (deprecated)", NewEIP155Signer(1), ethgo.TransactionSetCode, false},
+		{"berlin", NewBerlinSigner(1), ethgo.TransactionAccessList, false},
+		{"london", NewLondonSigner(1), ethgo.TransactionDynamicFee, false},
+		{"cancun", NewCancunSigner(1), ethgo.TransactionBlob, false},
+		{"prague", NewPragueSigner(1), ethgo.TransactionSetCode, false},
+	}
+
+	for _, c := range signers {
+		t.Run(c.name, func(t *testing.T) {
+			for typ := ethgo.TransactionLegacy; typ <= ethgo.TransactionSetCode; typ++ {
+				txn := testTransaction(typ)
+
+				txn, err := c.signer.SignTx(txn, key)
+				if typ > c.maxType {
+					assert.Equal(t, ErrTxTypeNotSupported, err)
+					continue
+				}
+				assert.NoError(t, err)
+
+				v := new(big.Int).SetBytes(txn.V).Uint64()
+				if typ != ethgo.TransactionLegacy {
+					assert.LessOrEqual(t, v, uint64(1))
+					assert.Equal(t, uint64(1), txn.ChainID.Uint64())
+				} else if c.legacy {
+					assert.True(t, v == 27 || v == 28)
+				} else {
+					assert.True(t, v == 37 || v == 38)
+				}
+
+				from, err := c.signer.RecoverSender(txn)
+				assert.NoError(t, err)
+				assert.Equal(t, key.addr, from)
+			}
+		})
+	}
+
+	t.Run("ChainID", func(t *testing.T) {
+		txn := testTransaction(ethgo.TransactionDynamicFee)
+		txn.ChainID = big.NewInt(2)
+
+		_, err := NewLondonSigner(1).SignTx(txn, key)
+		assert.Equal(t, ErrInvalidChainID, err)
+
+		txn, err = NewLondonSigner(2).SignTx(txn, key)
+		assert.NoError(t, err)
+
+		_, err = NewLondonSigner(1).RecoverSender(txn)
+		assert.Equal(t, ErrInvalidChainID, err)
+	})
+
+	t.Run("Unprotected", func(t *testing.T) {
+		txn, err := NewHomesteadSigner().SignTx(testTransaction(ethgo.TransactionLegacy), key)
+		assert.NoError(t, err)
+
+		// the eip-155 signers recover the unprotected transactions
+		from, err := LatestSignerForChainID(1).RecoverSender(txn)
+		assert.NoError(t, err)
+		assert.Equal(t, key.addr, from)
+
+		// a signature with a high s value is only valid before homestead
+		s := new(big.Int).Sub(S256.N, new(big.Int).SetBytes(txn.S))
+		txn.S = s.Bytes()
+		txn.V = []byte{55 - txn.V[0]}
+
+		from, err = NewFrontierSigner().RecoverSender(txn)
+		assert.NoError(t, err)
+		assert.Equal(t, key.addr, from)
+
+		_, err = NewHomesteadSigner().RecoverSender(txn)
+		assert.Equal(t, ErrInvalidSig, err)
+	})
+}
+
+func TestSigner_Block(t *testing.T) {
+	signer := LatestSignerForChainID(1)
+
+	// the transactions of a block are recovered after they are encoded in json
+	block := &ethgo.Block{Hash: ethgo.Hash{0x1}}
+	senders := []ethgo.Address{}
+	for typ := ethgo.TransactionLegacy; typ <= ethgo.TransactionSetCode; typ++ {
+		key, err := GenerateKey()
+		assert.NoError(t, err)
+
+		txn, err := signer.SignTx(testTransaction(typ), key)
+		assert.NoError(t, err)
+		txn.From = key.addr
+
+		block.Transactions = append(block.Transactions, txn)
+		senders = append(senders, key.addr)
+	}
+
+	// unprotected legacy transaction
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	txn, err := NewHomesteadSigner().SignTx(testTransaction(ethgo.TransactionLegacy), key)
+	assert.NoError(t, err)
+	txn.From = key.addr
+
+	block.Transactions = append(block.Transactions, txn)
+	senders = append(senders, key.addr)
+
+	data, err := block.MarshalJSON()
+	assert.NoError(t, err)
+
+	block2 := new(ethgo.Block)
+	assert.NoError(t, block2.UnmarshalJSON(data))
+	assert.Len(t, block2.Transactions, len(senders))
+
+	for indx, txn := range block2.Transactions {
+		from, err := signer.RecoverSender(txn)
+		assert.NoError(t, err)
+		assert.Equal(t, senders[indx], from)
+	}
+}
+
+func testTransaction(typ ethgo.TransactionType) *ethgo.Transaction {
+	to := ethgo.Address{0x1}
+	txn := &ethgo.Transaction{
+		Type:  typ,
+		Nonce: 1,
+		Gas:   21000,
+		To:    &to,
+		Value: big.NewInt(10),
+		Input: []byte{0x1},
+	}
+	if typ < ethgo.TransactionDynamicFee {
+		txn.GasPrice = 10
+	} else {
+		txn.MaxFeePerGas = big.NewInt(10)
+		txn.MaxPriorityFeePerGas = big.NewInt(1)
+	}
+	if typ == ethgo.TransactionBlob {
+		txn.MaxFeePerBlobGas = big.NewInt(1)
+		txn.BlobVersionedHashes = []ethgo.Hash{{0x1}}
+	}
+	if typ == ethgo.TransactionSetCode {
+		txn.AuthorizationList = []*ethgo.Authorization{
+			{ChainID: big.NewInt(1), Address: to, R: []byte{0x1}, S: []byte{0x1}},
+		}
+	}
+	return txn
+}
+
+func TestSigner_Authorization(t *testing.T) {
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	auth := &ethgo.Authorization{
+		ChainID: big.NewInt(1),
+		Address: ethgo.Address{0x1},
+		Nonce:   5,
+	}
+	auth, err = SignAuthorization(auth, key)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, auth.YParity, uint64(1))
+
+	authority, err := RecoverAuthority(auth)
+	assert.NoError(t, err)
+	assert.Equal(t, key.addr, authority)
+
+	// the y parity is not truncated to a byte
+	invalid := *auth
+	invalid.YParity += 256
+	_, err = RecoverAuthority(&invalid)
+	assert.Equal(t, ErrInvalidSig, err)
+
+	invalid.YParity = 2
+	_, err = RecoverAuthority(&invalid)
+	assert.Equal(t, ErrInvalidSig, err)
+
+	// the malleable signature with the high s value is rejected
+	invalid = *auth
+	invalid.S = new(big.Int).Sub(S256.N, new(big.Int).SetBytes(auth.S)).Bytes()
+	invalid.YParity = 1 - auth.YParity
+	_, err = RecoverAuthority(&invalid)
+	assert.Equal(t, ErrInvalidSig, err)
+
+	// the signature does not match another nonce
+	auth.Nonce = 6
+	authority, err = RecoverAuthority(auth)
+	if err == nil {
+		assert.NotEqual(t, key.addr, authority)
+	}
+}
+
 func TestTrimBytesZeros(t *testing.T) {
 	assert.Equal(t, trimBytesZeros([]byte{0x1, 0x2}), []byte{0x1, 0x2})
 	assert.Equal(t, trimBytesZeros([]byte{0x0, 0x1}), []byte{0x1})